@@ -26,6 +26,13 @@ type Expression interface {
 
 type Program struct {
 	Statements []Statement // Program is simply just a series of statements
+	Comments   []*Comment  // every comment in the source , in order , the evaluator ignore them
+}
+
+// Line comment , only kept so that tools like the formatter can put them back
+type Comment struct {
+	Token token.Token // the COMMENT token
+	Text  string      // comment text including the leading //
 }
 
 // Return the whole program back as string to debug and test
@@ -69,6 +76,16 @@ func (il *IntegerLiteral) expressionNode()      {}
 func (il *IntegerLiteral) TokenLiteral() string { return il.Token.Literal }
 func (il *IntegerLiteral) String() string       { return il.Token.Literal }
 
+// represent float , as 1.5
+type FloatLiteral struct {
+	Token token.Token
+	Value float64
+}
+
+func (fl *FloatLiteral) expressionNode()      {}
+func (fl *FloatLiteral) TokenLiteral() string { return fl.Token.Literal }
+func (fl *FloatLiteral) String() string       { return fl.Token.Literal }
+
 // Represent string
 type StringLiteral struct {
 	Token token.Token
@@ -98,9 +115,14 @@ func (bl *BooleanLiteral) String() string       { return bl.Token.Literal }
 
 // Function is the first class citizen , so it is an expression too
 type FunctionLiteral struct {
-	Token      token.Token //the fn token
-	Parameters []*Identifier
+	Token      token.Token  //the fn token
+	Parameters []Expression // *Identifier , *ArrayPattern , *HashPattern , *DefaultParameter or a last *SpreadExpression
 	Body       *BlockStatement
+
+	ParameterTypes []*Type // annotation of each parameter , in the order of Parameters , nil for the ones without
+	ReturnType     *Type   // annotation of the result , nil when there is none
+
+	Generator bool // written func* or its body contain a yield , a call return a generator instead of running the body
 }
 
 func (fl *FunctionLiteral) expressionNode()      {}
@@ -108,33 +130,78 @@ func (fl *FunctionLiteral) TokenLiteral() string { return fl.Token.Literal }
 func (fl *FunctionLiteral) String() string {
 	var out bytes.Buffer
 	params := []string{}
-	for _, para := range fl.Parameters {
-		params = append(params, para.String())
+	for i, para := range fl.Parameters {
+		params = append(params, ParameterString(para, fl.ParameterType(i)))
 	}
 	out.WriteString(fl.TokenLiteral())
+	if fl.Generator {
+		out.WriteString("*")
+	}
 	out.WriteString("(")
 	out.WriteString(strings.Join(params, ","))
-	out.WriteString(") ")
+	out.WriteString(")")
+	if fl.ReturnType != nil {
+		out.WriteString(": " + fl.ReturnType.String())
+	}
+	out.WriteString(" ")
 	out.WriteString(fl.Body.String())
 	return out.String()
 }
 
+// ParameterType return the annotation of the parameter i , nil when it has none
+func (fl *FunctionLiteral) ParameterType(i int) *Type {
+	if i < len(fl.ParameterTypes) {
+		return fl.ParameterTypes[i]
+	}
+	return nil
+}
+
+// ParameterString show a parameter with its annotation , as b: int = 10
+func ParameterString(param Expression, typ *Type) string {
+	if typ == nil {
+		return param.String()
+	}
+	if def, ok := param.(*DefaultParameter); ok {
+		return def.Target.String() + ": " + typ.String() + " = " + def.Value.String()
+	}
+	return param.String() + ": " + typ.String()
+}
+
 //-----------------------------------------------------------------------------------
 
 // STATEMENTS
 // ----------------------------------------------------------------------------------
+// const NAME = value is a LetStatement whose Token is CONST
 type LetStatement struct {
-	Token token.Token // The LET token
+	Token token.Token // The LET or CONST token
 	Name  *Identifier // To keep the nodeType small , we use the Identifier for both binding variable value and identifier in the right part , but to clarify that the identifier used in this case doesn't produce a value  but  still satisfy the Expression interface
 	Value Expression  // point to the expression
+
+	Pattern Expression // *ArrayPattern or *HashPattern of let [a, b] = ... , Name is nil when it is set
+	Type    *Type      // annotation of let name: type = value , nil when there is none
 }
 
 func (ls *LetStatement) TokenLiteral() string { return ls.Token.Literal }
 func (ls *LetStatement) statementNode()       {}
+
+// IsConst report whether the statement is a const , whose names cannot be bound again in the same scope
+func (ls *LetStatement) IsConst() bool { return ls.Token.Type == token.CONST }
+
+// Target is what the let bind , its Name or its Pattern
+func (ls *LetStatement) Target() Expression {
+	if ls.Pattern != nil {
+		return ls.Pattern
+	}
+	return ls.Name
+}
+
 func (ls *LetStatement) String() string {
 	var out bytes.Buffer
 	out.WriteString(ls.TokenLiteral() + " ")
-	out.WriteString(ls.Name.String())
+	out.WriteString(ls.Target().String())
+	if ls.Type != nil {
+		out.WriteString(": " + ls.Type.String())
+	}
 	out.WriteString(" = ")
 	if ls.Value != nil {
 		out.WriteString(ls.Value.String())
@@ -152,6 +219,7 @@ func (rs *ReturnStatement) statementNode()       {}
 func (rs *ReturnStatement) TokenLiteral() string { return rs.Token.Literal }
 func (rs *ReturnStatement) String() string {
 	var out bytes.Buffer
+	out.WriteString(rs.TokenLiteral() + " ")
 	if rs.ReturnValue != nil {
 		out.WriteString(rs.ReturnValue.String())
 	}
@@ -159,6 +227,83 @@ func (rs *ReturnStatement) String() string {
 	return out.String()
 }
 
+// Import statement evaluate the file at Path and bind it to the file name without extension ,
+// as import "lib/list" bind list
+type ImportStatement struct {
+	Token token.Token // the IMPORT token
+	Path  Expression  // a string literal
+}
+
+func (is *ImportStatement) statementNode()       {}
+func (is *ImportStatement) TokenLiteral() string { return is.Token.Literal }
+func (is *ImportStatement) String() string {
+	return is.TokenLiteral() + " " + is.Path.String() + ";"
+}
+
+// Name return the name the module is bound to , empty when Path is not a string literal
+func (is *ImportStatement) Name() string {
+	path, ok := is.Path.(*StringLiteral)
+	if !ok {
+		return ""
+	}
+	name := path.Value
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "."); i > 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// Throw statement raise Value as an error , caught by the closest enclosing try
+type ThrowStatement struct {
+	Token token.Token // the THROW token
+	Value Expression
+}
+
+func (ts *ThrowStatement) statementNode()       {}
+func (ts *ThrowStatement) TokenLiteral() string { return ts.Token.Literal }
+func (ts *ThrowStatement) String() string {
+	return ts.TokenLiteral() + " " + ts.Value.String() + ";"
+}
+
+// For statement run Body once for each element of Iterable , bound to Target , a name or a destructuring pattern
+// as for [i, x] in enumerate(xs) { }
+type ForStatement struct {
+	Token    token.Token // the FOR token
+	Target   Expression  // *Identifier , *ArrayPattern or *HashPattern
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+func (fs *ForStatement) statementNode()       {}
+func (fs *ForStatement) TokenLiteral() string { return fs.Token.Literal }
+func (fs *ForStatement) String() string {
+	return "for " + fs.Target.String() + " in " + fs.Iterable.String() + " " + fs.Body.String()
+}
+
+// Struct statement declare a record type and bind its constructor to Name , as struct Point { x, y }
+type StructStatement struct {
+	Token  token.Token // the STRUCT token
+	Name   *Identifier
+	Fields []*Identifier
+	Rbrace token.Token // the closing } token
+}
+
+func (ss *StructStatement) statementNode()       {}
+func (ss *StructStatement) TokenLiteral() string { return ss.Token.Literal }
+func (ss *StructStatement) String() string {
+	fields := []string{}
+	for _, field := range ss.Fields {
+		fields = append(fields, field.String())
+	}
+	if len(fields) == 0 {
+		return ss.TokenLiteral() + " " + ss.Name.String() + " {}"
+	}
+	return ss.TokenLiteral() + " " + ss.Name.String() + " { " + strings.Join(fields, ", ") + " }"
+}
+
 // only the wrapper around the expression ,since  it is totally fine to write either
 // let x = 1; // let stament  OR  x + 2; // expression by itself
 type ExpressionStatement struct {
@@ -179,6 +324,7 @@ func (es *ExpressionStatement) String() string {
 type BlockStatement struct {
 	Token      token.Token // the { token
 	Statements []Statement
+	Rbrace     token.Token // the closing } token
 }
 
 func (bs *BlockStatement) statementNode()       {}
@@ -259,14 +405,58 @@ func (ife *IfExpression) String() string {
 	return out.String()
 }
 
+// Try expression evaluate Body , an error raised inside is bound to Param and handled by Catch ,
+// Finally run in every case . Catch or Finally may be missing but not both
+type TryExpression struct {
+	Token   token.Token // the TRY token
+	Body    *BlockStatement
+	Param   *Identifier // nil without catch
+	Catch   *BlockStatement
+	Finally *BlockStatement
+}
+
+func (te *TryExpression) expressionNode()      {}
+func (te *TryExpression) TokenLiteral() string { return te.Token.Literal }
+func (te *TryExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("try")
+	out.WriteString(te.Body.String())
+	if te.Catch != nil {
+		out.WriteString("catch(" + te.Param.String() + ")")
+		out.WriteString(te.Catch.String())
+	}
+	if te.Finally != nil {
+		out.WriteString("finally")
+		out.WriteString(te.Finally.String())
+	}
+	return out.String()
+}
+
+// Yield expression suspend the generator running it and hand Value to the caller of next ,
+// it evaluate to the value given to the next call that resume it . Value is nil for a bare yield
+type YieldExpression struct {
+	Token token.Token // the YIELD token
+	Value Expression
+}
+
+func (ye *YieldExpression) expressionNode()      {}
+func (ye *YieldExpression) TokenLiteral() string { return ye.Token.Literal }
+func (ye *YieldExpression) String() string {
+	if ye.Value == nil {
+		return "yield"
+	}
+	return "(yield " + ye.Value.String() + ")"
+}
+
 // Call expression consist  of an expression that result in a function when evaluated and a list of expression that are the arguments to this function call
 // as add(2 , 3) is valid
 // add(2 + 3 + 3  * 2) is also valid
 // multiply(2  , add( 2 , 3)) // is also valid
 type CallExpression struct {
-	Token     token.Token
+	Token     token.Token // the ( token
 	Function  Expression
-	Arguments []Expression
+	Arguments []Expression // positional arguments , ...array to spread one , then the *NamedArgument
+	Rparen    token.Token  // the closing ) token
 }
 
 func (ce *CallExpression) expressionNode()      {}
@@ -283,3 +473,223 @@ func (ce *CallExpression) String() string {
 	out.WriteString(")")
 	return out.String()
 }
+
+// Named argument is name: value in a call , as f(1, scale: 2)
+type NamedArgument struct {
+	Token token.Token // the IDENT token of the name
+	Name  *Identifier
+	Value Expression
+}
+
+func (na *NamedArgument) expressionNode()      {}
+func (na *NamedArgument) TokenLiteral() string { return na.Token.Literal }
+func (na *NamedArgument) String() string {
+	return na.Name.String() + ": " + na.Value.String()
+}
+
+// Array literal is a comma separated list of expression inside brackets , as [1, 2 * 2, add(1, 2)]
+type ArrayLiteral struct {
+	Token    token.Token // the [ token
+	Elements []Expression
+	Rbracket token.Token // the closing ] token
+}
+
+func (al *ArrayLiteral) expressionNode()      {}
+func (al *ArrayLiteral) TokenLiteral() string { return al.Token.Literal }
+func (al *ArrayLiteral) String() string {
+	elements := []string{}
+	for _, el := range al.Elements {
+		elements = append(elements, el.String())
+	}
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// Index expression access one element of what Left evaluate to , as array[1] or "abc"[i + 1]
+type IndexExpression struct {
+	Token    token.Token // the [ token
+	Left     Expression
+	Index    Expression
+	Rbracket token.Token // the closing ] token
+}
+
+func (ie *IndexExpression) expressionNode()      {}
+func (ie *IndexExpression) TokenLiteral() string { return ie.Token.Literal }
+func (ie *IndexExpression) String() string {
+	return "(" + ie.Left.String() + "[" + ie.Index.String() + "])"
+}
+
+// Member expression access a name inside what Object evaluate to , as strings.split
+type MemberExpression struct {
+	Token  token.Token // the . token
+	Object Expression
+	Member *Identifier
+}
+
+func (me *MemberExpression) expressionNode()      {}
+func (me *MemberExpression) TokenLiteral() string { return me.Token.Literal }
+func (me *MemberExpression) String() string {
+	return me.Object.String() + "." + me.Member.String()
+}
+
+// Hash literal is a comma separated list of key: value inside braces , as {"name": "a", 1: true}
+// Keys and Values are parallel , so the pairs keep the order of the source
+type HashLiteral struct {
+	Token  token.Token // the { token
+	Keys   []Expression
+	Values []Expression
+	Rbrace token.Token // the closing } token
+}
+
+func (hl *HashLiteral) expressionNode()      {}
+func (hl *HashLiteral) TokenLiteral() string { return hl.Token.Literal }
+func (hl *HashLiteral) String() string {
+	pairs := []string{}
+	for i, key := range hl.Keys {
+		pairs = append(pairs, key.String()+": "+hl.Values[i].String())
+	}
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// Import expression evaluate to the module at Path , as let list = import("lib/list")
+type ImportExpression struct {
+	Token  token.Token // the IMPORT token
+	Path   Expression
+	Rparen token.Token // the closing ) token
+}
+
+func (ie *ImportExpression) expressionNode()      {}
+func (ie *ImportExpression) TokenLiteral() string { return ie.Token.Literal }
+func (ie *ImportExpression) String() string {
+	return ie.TokenLiteral() + "(" + ie.Path.String() + ")"
+}
+
+// Spread expression is ...Value , in a pattern it collect the rest of an array , as [first, ...rest]
+type SpreadExpression struct {
+	Token token.Token // the ... token
+	Value Expression
+}
+
+func (se *SpreadExpression) expressionNode()      {}
+func (se *SpreadExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SpreadExpression) String() string {
+	return "..." + se.Value.String()
+}
+
+// Match expression evaluate the Body of the first arm whose pattern match Subject
+type MatchExpression struct {
+	Token   token.Token // the MATCH token
+	Subject Expression
+	Arms    []*MatchArm
+	Rbrace  token.Token // the closing } token
+}
+
+// Pattern is a literal , _ , a name to bind , or an array or hash literal of patterns ,
+// Guard is nil when the arm has none and Body is an *ExpressionStatement or a *BlockStatement
+type MatchArm struct {
+	Pattern Expression
+	Guard   Expression
+	Arrow   token.Token // the => token
+	Body    Statement
+}
+
+func (me *MatchExpression) expressionNode()      {}
+func (me *MatchExpression) TokenLiteral() string { return me.Token.Literal }
+func (me *MatchExpression) String() string {
+	arms := []string{}
+	for _, arm := range me.Arms {
+		text := arm.Pattern.String()
+		if arm.Guard != nil {
+			text += " if " + arm.Guard.String()
+		}
+		arms = append(arms, text+" => "+arm.Body.String())
+	}
+	return "match " + me.Subject.String() + " {" + strings.Join(arms, ", ") + "}"
+}
+
+// PATTERNS
+// ----------------------------------------------------------------------------------
+// Destructuring patterns of let and of the function parameters , they only bind names
+
+// Array pattern is [a, [b, c], ...rest] , Rest is nil when there is no ...name
+type ArrayPattern struct {
+	Token    token.Token  // the [ token
+	Elements []Expression // *Identifier , *ArrayPattern or *HashPattern
+	Rest     *Identifier
+	Rbracket token.Token // the closing ] token
+}
+
+func (ap *ArrayPattern) expressionNode()      {}
+func (ap *ArrayPattern) TokenLiteral() string { return ap.Token.Literal }
+func (ap *ArrayPattern) String() string {
+	elements := []string{}
+	for _, el := range ap.Elements {
+		elements = append(elements, el.String())
+	}
+	if ap.Rest != nil {
+		elements = append(elements, "..."+ap.Rest.String())
+	}
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// Default parameter is name = value in a parameter list , Value is evaluated at each call that does not give it
+type DefaultParameter struct {
+	Token  token.Token // the = token
+	Target Expression  // *Identifier , *ArrayPattern or *HashPattern
+	Value  Expression
+}
+
+func (dp *DefaultParameter) expressionNode()      {}
+func (dp *DefaultParameter) TokenLiteral() string { return dp.Token.Literal }
+func (dp *DefaultParameter) String() string {
+	return dp.Target.String() + " = " + dp.Value.String()
+}
+
+// Hash pattern is {name, age: years} , Keys are the key or field names and Values the pattern bound to each ,
+// the shorthand {name} has a Value that is an *Identifier with the same name as its key
+type HashPattern struct {
+	Token  token.Token // the { token
+	Keys   []*Identifier
+	Values []Expression // *Identifier , *ArrayPattern or *HashPattern
+	Rbrace token.Token  // the closing } token
+}
+
+func (hp *HashPattern) expressionNode()      {}
+func (hp *HashPattern) TokenLiteral() string { return hp.Token.Literal }
+func (hp *HashPattern) String() string {
+	pairs := []string{}
+	for i, key := range hp.Keys {
+		if hp.IsShorthand(i) {
+			pairs = append(pairs, key.Value)
+			continue
+		}
+		pairs = append(pairs, key.Value+": "+hp.Values[i].String())
+	}
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// IsShorthand report whether the pair i is written {name} instead of {name: name}
+func (hp *HashPattern) IsShorthand(i int) bool {
+	ident, ok := hp.Values[i].(*Identifier)
+	return ok && ident.Value == hp.Keys[i].Value
+}
+
+// TYPES
+// ----------------------------------------------------------------------------------
+// Type annotation , as int , Point or hash<string, array<int>> , Arguments are the types between < and >
+type Type struct {
+	Token     token.Token // the name , an IDENT token or the FUNCTION token of func
+	Arguments []*Type
+	Close     token.Token // the closing > token , zero when there is no argument
+}
+
+func (t *Type) TokenLiteral() string { return t.Token.Literal }
+func (t *Type) String() string {
+	if len(t.Arguments) == 0 {
+		return t.Token.Literal
+	}
+	args := []string{}
+	for _, arg := range t.Arguments {
+		args = append(args, arg.String())
+	}
+	return t.Token.Literal + "<" + strings.Join(args, ", ") + ">"
+}
@@ -1,6 +1,7 @@
 package ast
 
 import (
+	"bytes"
 	"khanhanh_lang/token"
 	"testing"
 )
@@ -25,3 +26,34 @@ func TestString(t *testing.T) {
 		t.Errorf("program.String() wrong. got=%q", program.String())
 	}
 }
+
+func TestFprint(t *testing.T) {
+	program := &Program{
+		Statements: []Statement{
+			&LetStatement{
+				Token: token.Token{Type: token.LET, Literal: "let"},
+				Name:  &Identifier{Token: token.Token{Type: token.IDENT, Literal: "x"}, Value: "x"},
+				Value: &InfixExpression{
+					Token:    token.Token{Type: token.PLUS, Literal: "+"},
+					Operator: "+",
+					Left:     &IntegerLiteral{Token: token.Token{Type: token.INT, Literal: "1"}, Value: 1},
+					Right:    &StringLiteral{Token: token.Token{Type: token.STRING, Literal: "a"}, Value: "a"},
+				},
+			},
+		},
+	}
+	var out bytes.Buffer
+	if err := Fprint(&out, program); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	expected := `Program
+  LetStatement
+    Identifier x
+    InfixExpression +
+      IntegerLiteral 1
+      StringLiteral "a"
+`
+	if out.String() != expected {
+		t.Errorf("Fprint wrong. expected\n%s\ngot\n%s", expected, out.String())
+	}
+}
@@ -0,0 +1,71 @@
+package ast
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PRINT
+// ---------------------------------------------------------------------------------
+// Fprint write node as an indented tree , one node per line with its type and its own value
+// ( operator , literal , name ) , children are indented under their parent
+//
+//	InfixExpression +
+//	  IntegerLiteral 1
+//	  IntegerLiteral 2
+func Fprint(w io.Writer, node Node) error {
+	p := &treePrinter{w: w}
+	Walk(p, node)
+	return p.err
+}
+
+type treePrinter struct {
+	w     io.Writer
+	depth int
+	err   error
+}
+
+func (p *treePrinter) Visit(node Node) Visitor {
+	if node == nil {
+		p.depth--
+		return nil
+	}
+	line := strings.Repeat("  ", p.depth) + nodeLabel(node) + "\n"
+	if _, err := io.WriteString(p.w, line); err != nil && p.err == nil {
+		p.err = err
+	}
+	p.depth++
+	return p
+}
+
+func nodeLabel(node Node) string {
+	name := strings.TrimPrefix(fmt.Sprintf("%T", node), "*ast.")
+	switch n := node.(type) {
+	case *Identifier:
+		return name + " " + n.Value
+	case *IntegerLiteral:
+		return name + " " + n.Token.Literal
+	case *FloatLiteral:
+		return name + " " + n.Token.Literal
+	case *StringLiteral:
+		return name + " " + n.String()
+	case *BooleanLiteral:
+		return fmt.Sprintf("%s %t", name, n.Value)
+	case *PrefixExpression:
+		return name + " " + n.Operator
+	case *InfixExpression:
+		return name + " " + n.Operator
+	case *StructStatement:
+		if n.Name != nil {
+			return name + " " + n.Name.Value
+		}
+	case *MemberExpression:
+		if n.Member != nil {
+			return name + " ." + n.Member.Value
+		}
+	case *Type:
+		return name + " " + n.Token.Literal
+	}
+	return name
+}
@@ -0,0 +1,513 @@
+package ast
+
+import "fmt"
+
+// WALK
+// ---------------------------------------------------------------------------------
+// A Visitor's Visit method is invoked for each node encountered by Walk.
+// If the result visitor w is not nil, Walk visits each of the children
+// of node with the visitor w, followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses an AST in depth-first order, the same way go/ast does.
+// It starts by calling v.Visit(node); node must not be nil.
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	// Program and statements
+	case *Program:
+		walkStatements(v, n.Statements)
+	case *LetStatement:
+		if n.Name != nil {
+			Walk(v, n.Name)
+		}
+		if n.Pattern != nil {
+			Walk(v, n.Pattern)
+		}
+		if n.Type != nil {
+			Walk(v, n.Type)
+		}
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+	case *ReturnStatement:
+		if n.ReturnValue != nil {
+			Walk(v, n.ReturnValue)
+		}
+	case *ExpressionStatement:
+		if n.Expression != nil {
+			Walk(v, n.Expression)
+		}
+	case *ImportStatement:
+		if n.Path != nil {
+			Walk(v, n.Path)
+		}
+	case *ThrowStatement:
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+	case *ForStatement:
+		if n.Target != nil {
+			Walk(v, n.Target)
+		}
+		if n.Iterable != nil {
+			Walk(v, n.Iterable)
+		}
+		if n.Body != nil {
+			Walk(v, n.Body)
+		}
+	case *StructStatement:
+		if n.Name != nil {
+			Walk(v, n.Name)
+		}
+		for _, field := range n.Fields {
+			Walk(v, field)
+		}
+	case *BlockStatement:
+		walkStatements(v, n.Statements)
+
+	// Literals , leaf node that have no children
+	case *Identifier, *IntegerLiteral, *FloatLiteral, *StringLiteral, *BooleanLiteral:
+
+	case *FunctionLiteral:
+		// each parameter is walked before its annotation
+		for i, param := range n.Parameters {
+			if param != nil {
+				Walk(v, param)
+			}
+			if typ := n.ParameterType(i); typ != nil {
+				Walk(v, typ)
+			}
+		}
+		if n.ReturnType != nil {
+			Walk(v, n.ReturnType)
+		}
+		if n.Body != nil {
+			Walk(v, n.Body)
+		}
+
+	// Expressions
+	case *PrefixExpression:
+		if n.Right != nil {
+			Walk(v, n.Right)
+		}
+	case *InfixExpression:
+		if n.Left != nil {
+			Walk(v, n.Left)
+		}
+		if n.Right != nil {
+			Walk(v, n.Right)
+		}
+	case *IfExpression:
+		if n.Condition != nil {
+			Walk(v, n.Condition)
+		}
+		if n.Consequence != nil {
+			Walk(v, n.Consequence)
+		}
+		if n.Alternative != nil {
+			Walk(v, n.Alternative)
+		}
+	case *TryExpression:
+		if n.Body != nil {
+			Walk(v, n.Body)
+		}
+		if n.Param != nil {
+			Walk(v, n.Param)
+		}
+		if n.Catch != nil {
+			Walk(v, n.Catch)
+		}
+		if n.Finally != nil {
+			Walk(v, n.Finally)
+		}
+	case *CallExpression:
+		if n.Function != nil {
+			Walk(v, n.Function)
+		}
+		walkExpressions(v, n.Arguments)
+	case *ArrayLiteral:
+		walkExpressions(v, n.Elements)
+	case *IndexExpression:
+		if n.Left != nil {
+			Walk(v, n.Left)
+		}
+		if n.Index != nil {
+			Walk(v, n.Index)
+		}
+	case *MemberExpression:
+		if n.Object != nil {
+			Walk(v, n.Object)
+		}
+		if n.Member != nil {
+			Walk(v, n.Member)
+		}
+	case *HashLiteral:
+		for i, key := range n.Keys {
+			if key != nil {
+				Walk(v, key)
+			}
+			if i < len(n.Values) && n.Values[i] != nil {
+				Walk(v, n.Values[i])
+			}
+		}
+	case *ImportExpression:
+		if n.Path != nil {
+			Walk(v, n.Path)
+		}
+	case *SpreadExpression:
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+	case *YieldExpression:
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+	case *NamedArgument:
+		if n.Name != nil {
+			Walk(v, n.Name)
+		}
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+	case *DefaultParameter:
+		if n.Target != nil {
+			Walk(v, n.Target)
+		}
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+	case *ArrayPattern:
+		walkExpressions(v, n.Elements)
+		if n.Rest != nil {
+			Walk(v, n.Rest)
+		}
+	case *HashPattern:
+		// each key is walked before the pattern bound to it
+		for i, key := range n.Keys {
+			Walk(v, key)
+			if n.Values[i] != nil {
+				Walk(v, n.Values[i])
+			}
+		}
+	case *MatchExpression:
+		if n.Subject != nil {
+			Walk(v, n.Subject)
+		}
+		for _, arm := range n.Arms {
+			if arm.Pattern != nil {
+				Walk(v, arm.Pattern)
+			}
+			if arm.Guard != nil {
+				Walk(v, arm.Guard)
+			}
+			if arm.Body != nil {
+				Walk(v, arm.Body)
+			}
+		}
+
+	// Types
+	case *Type:
+		for _, arg := range n.Arguments {
+			Walk(v, arg)
+		}
+
+	default:
+		panic(fmt.Sprintf("ast.Walk: unexpected node type %T", n))
+	}
+
+	v.Visit(nil)
+}
+
+func walkStatements(v Visitor, stmts []Statement) {
+	for _, stmt := range stmts {
+		if stmt != nil {
+			Walk(v, stmt)
+		}
+	}
+}
+
+func walkExpressions(v Visitor, exps []Expression) {
+	for _, exp := range exps {
+		if exp != nil {
+			Walk(v, exp)
+		}
+	}
+}
+
+// INSPECT
+// ---------------------------------------------------------------------------------
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses an AST in depth-first order: It starts by calling f(node);
+// if f returns true, Inspect invokes f recursively for each of the non-nil children of node,
+// followed by a call of f(nil).
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
+
+// REWRITE
+// ---------------------------------------------------------------------------------
+// Rewrite traverses an AST in depth-first order and replace each node with the result of f.
+// Children are rewritten before their parent (post order) , so f always see the already rewritten subtree.
+// Returning the node itself keep it unchanged , returning nil remove it from its parent when the parent
+// hold a list (statements , arguments) or clear the field otherwise.
+// The replacement must be of the same kind as the slot it goes into (a Statement for a statement slot ,
+// an Expression for an expression slot , *Identifier for names and parameters) , Rewrite panic otherwise.
+func Rewrite(node Node, f func(Node) Node) Node {
+	switch n := node.(type) {
+	// Program and statements
+	case *Program:
+		n.Statements = rewriteStatements(n.Statements, f)
+	case *LetStatement:
+		n.Name = rewriteIdentifier(n.Name, f)
+		n.Pattern = rewriteExpression(n.Pattern, f)
+		n.Type = rewriteType(n.Type, f)
+		n.Value = rewriteExpression(n.Value, f)
+	case *ReturnStatement:
+		n.ReturnValue = rewriteExpression(n.ReturnValue, f)
+	case *ExpressionStatement:
+		n.Expression = rewriteExpression(n.Expression, f)
+	case *ImportStatement:
+		n.Path = rewriteExpression(n.Path, f)
+	case *ThrowStatement:
+		n.Value = rewriteExpression(n.Value, f)
+	case *ForStatement:
+		n.Target = rewriteExpression(n.Target, f)
+		n.Iterable = rewriteExpression(n.Iterable, f)
+		n.Body = rewriteBlock(n.Body, f)
+	case *StructStatement:
+		n.Name = rewriteIdentifier(n.Name, f)
+		n.Fields = rewriteIdentifiers(n.Fields, f)
+	case *BlockStatement:
+		n.Statements = rewriteStatements(n.Statements, f)
+
+	// Literals
+	case *Identifier, *IntegerLiteral, *FloatLiteral, *StringLiteral, *BooleanLiteral:
+
+	case *FunctionLiteral:
+		// a removed parameter take its annotation with it
+		params, types := []Expression{}, []*Type{}
+		for i, param := range n.Parameters {
+			typ := n.ParameterType(i)
+			if param = rewriteExpression(param, f); param != nil {
+				params, types = append(params, param), append(types, rewriteType(typ, f))
+			}
+		}
+		n.Parameters = params
+		if len(n.ParameterTypes) != 0 {
+			n.ParameterTypes = types
+		}
+		n.ReturnType = rewriteType(n.ReturnType, f)
+		n.Body = rewriteBlock(n.Body, f)
+
+	// Expressions
+	case *PrefixExpression:
+		n.Right = rewriteExpression(n.Right, f)
+	case *InfixExpression:
+		n.Left = rewriteExpression(n.Left, f)
+		n.Right = rewriteExpression(n.Right, f)
+	case *IfExpression:
+		n.Condition = rewriteExpression(n.Condition, f)
+		n.Consequence = rewriteBlock(n.Consequence, f)
+		n.Alternative = rewriteBlock(n.Alternative, f)
+	case *TryExpression:
+		n.Body = rewriteBlock(n.Body, f)
+		n.Param = rewriteIdentifier(n.Param, f)
+		n.Catch = rewriteBlock(n.Catch, f)
+		n.Finally = rewriteBlock(n.Finally, f)
+	case *CallExpression:
+		n.Function = rewriteExpression(n.Function, f)
+		n.Arguments = rewriteExpressions(n.Arguments, f)
+	case *ArrayLiteral:
+		n.Elements = rewriteExpressions(n.Elements, f)
+	case *IndexExpression:
+		n.Left = rewriteExpression(n.Left, f)
+		n.Index = rewriteExpression(n.Index, f)
+	case *MemberExpression:
+		n.Object = rewriteExpression(n.Object, f)
+		n.Member = rewriteIdentifier(n.Member, f)
+	case *HashLiteral:
+		// a pair is removed when its key or its value is removed
+		keys, values := []Expression{}, []Expression{}
+		for i := range n.Keys {
+			key := rewriteExpression(n.Keys[i], f)
+			value := rewriteExpression(n.Values[i], f)
+			if key != nil && value != nil {
+				keys, values = append(keys, key), append(values, value)
+			}
+		}
+		n.Keys, n.Values = keys, values
+	case *ImportExpression:
+		n.Path = rewriteExpression(n.Path, f)
+	case *SpreadExpression:
+		n.Value = rewriteExpression(n.Value, f)
+	case *YieldExpression:
+		n.Value = rewriteExpression(n.Value, f)
+	case *NamedArgument:
+		n.Name = rewriteIdentifier(n.Name, f)
+		n.Value = rewriteExpression(n.Value, f)
+	case *DefaultParameter:
+		n.Target = rewriteExpression(n.Target, f)
+		n.Value = rewriteExpression(n.Value, f)
+	case *ArrayPattern:
+		n.Elements = rewriteExpressions(n.Elements, f)
+		n.Rest = rewriteIdentifier(n.Rest, f)
+	case *HashPattern:
+		// a pair is removed when its key or its value is removed
+		keys, values := []*Identifier{}, []Expression{}
+		for i := range n.Keys {
+			key := rewriteIdentifier(n.Keys[i], f)
+			value := rewriteExpression(n.Values[i], f)
+			if key != nil && value != nil {
+				keys, values = append(keys, key), append(values, value)
+			}
+		}
+		n.Keys, n.Values = keys, values
+	case *MatchExpression:
+		n.Subject = rewriteExpression(n.Subject, f)
+		// an arm is removed when its pattern or its body is removed
+		arms := []*MatchArm{}
+		for _, arm := range n.Arms {
+			arm.Pattern = rewriteExpression(arm.Pattern, f)
+			arm.Guard = rewriteExpression(arm.Guard, f)
+			arm.Body = rewriteStatement(arm.Body, f)
+			if arm.Pattern != nil && arm.Body != nil {
+				arms = append(arms, arm)
+			}
+		}
+		n.Arms = arms
+
+	// Types
+	case *Type:
+		args := []*Type{}
+		for _, arg := range n.Arguments {
+			if arg = rewriteType(arg, f); arg != nil {
+				args = append(args, arg)
+			}
+		}
+		n.Arguments = args
+
+	default:
+		panic(fmt.Sprintf("ast.Rewrite: unexpected node type %T", n))
+	}
+
+	return f(node)
+}
+
+func rewriteStatement(stmt Statement, f func(Node) Node) Statement {
+	if stmt == nil {
+		return nil
+	}
+	result := Rewrite(stmt, f)
+	if result == nil {
+		return nil
+	}
+	s, ok := result.(Statement)
+	if !ok {
+		panic(fmt.Sprintf("ast.Rewrite: cannot replace statement with %T", result))
+	}
+	return s
+}
+
+func rewriteExpression(exp Expression, f func(Node) Node) Expression {
+	if exp == nil {
+		return nil
+	}
+	result := Rewrite(exp, f)
+	if result == nil {
+		return nil
+	}
+	e, ok := result.(Expression)
+	if !ok {
+		panic(fmt.Sprintf("ast.Rewrite: cannot replace expression with %T", result))
+	}
+	return e
+}
+
+func rewriteIdentifier(ident *Identifier, f func(Node) Node) *Identifier {
+	if ident == nil {
+		return nil
+	}
+	result := Rewrite(ident, f)
+	if result == nil {
+		return nil
+	}
+	i, ok := result.(*Identifier)
+	if !ok {
+		panic(fmt.Sprintf("ast.Rewrite: cannot replace identifier with %T", result))
+	}
+	return i
+}
+
+func rewriteBlock(block *BlockStatement, f func(Node) Node) *BlockStatement {
+	if block == nil {
+		return nil
+	}
+	result := Rewrite(block, f)
+	if result == nil {
+		return nil
+	}
+	b, ok := result.(*BlockStatement)
+	if !ok {
+		panic(fmt.Sprintf("ast.Rewrite: cannot replace block with %T", result))
+	}
+	return b
+}
+
+func rewriteType(typ *Type, f func(Node) Node) *Type {
+	if typ == nil {
+		return nil
+	}
+	result := Rewrite(typ, f)
+	if result == nil {
+		return nil
+	}
+	t, ok := result.(*Type)
+	if !ok {
+		panic(fmt.Sprintf("ast.Rewrite: cannot replace type with %T", result))
+	}
+	return t
+}
+
+func rewriteStatements(stmts []Statement, f func(Node) Node) []Statement {
+	result := stmts[:0]
+	for _, stmt := range stmts {
+		if s := rewriteStatement(stmt, f); s != nil {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func rewriteExpressions(exps []Expression, f func(Node) Node) []Expression {
+	result := exps[:0]
+	for _, exp := range exps {
+		if e := rewriteExpression(exp, f); e != nil {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func rewriteIdentifiers(idents []*Identifier, f func(Node) Node) []*Identifier {
+	result := idents[:0]
+	for _, ident := range idents {
+		if i := rewriteIdentifier(ident, f); i != nil {
+			result = append(result, i)
+		}
+	}
+	return result
+}
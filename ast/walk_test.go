@@ -0,0 +1,434 @@
+package ast
+
+import (
+	goast "go/ast"
+	"go/parser"
+	gotoken "go/token"
+	"io/fs"
+	"khanhanh_lang/token"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func ident(name string) *Identifier {
+	return &Identifier{Token: token.Token{Type: token.IDENT, Literal: name}, Value: name}
+}
+
+func block(stmts ...Statement) *BlockStatement {
+	return &BlockStatement{Token: token.Token{Type: token.LBRACE, Literal: "{"}, Statements: stmts}
+}
+
+func exprStmt(exp Expression) *ExpressionStatement {
+	return &ExpressionStatement{Token: token.Token{Type: token.IDENT, Literal: exp.TokenLiteral()}, Expression: exp}
+}
+
+// Every node type in the package with a sample whose identifiers (in walk order) are listed in idents
+// When a new node type is added , TestWalkCoversEveryNode fail until a sample is added here , and the sample
+// fail until Walk and Rewrite know how to reach its children
+var walkSamples = map[string]struct {
+	node   Node
+	idents []string
+}{
+	"Program": {
+		&Program{Statements: []Statement{exprStmt(ident("a")), exprStmt(ident("b"))}},
+		[]string{"a", "b"},
+	},
+	"Identifier": {ident("a"), []string{"a"}},
+	"IntegerLiteral": {
+		&IntegerLiteral{Token: token.Token{Type: token.INT, Literal: "5"}, Value: 5},
+		nil,
+	},
+	"FloatLiteral": {
+		&FloatLiteral{Token: token.Token{Type: token.FLOAT, Literal: "1.5"}, Value: 1.5},
+		nil,
+	},
+	"StringLiteral": {
+		&StringLiteral{Token: token.Token{Type: token.STRING, Literal: "s"}, Value: "s"},
+		nil,
+	},
+	"BooleanLiteral": {
+		&BooleanLiteral{Token: token.Token{Type: token.TRUE, Literal: "true"}, Value: true},
+		nil,
+	},
+	"FunctionLiteral": {
+		&FunctionLiteral{
+			Token:      token.Token{Type: token.FUNCTION, Literal: "func"},
+			Parameters: []Expression{ident("a"), ident("b")},
+			Body:       block(exprStmt(ident("c"))),
+		},
+		[]string{"a", "b", "c"},
+	},
+	"LetStatement": {
+		&LetStatement{Token: token.Token{Type: token.LET, Literal: "let"}, Name: ident("a"), Value: ident("b")},
+		[]string{"a", "b"},
+	},
+	"ReturnStatement": {
+		&ReturnStatement{Token: token.Token{Type: token.RETURN, Literal: "return"}, ReturnValue: ident("a")},
+		[]string{"a"},
+	},
+	"ExpressionStatement": {exprStmt(ident("a")), []string{"a"}},
+	"BlockStatement": {
+		block(exprStmt(ident("a")), exprStmt(ident("b"))),
+		[]string{"a", "b"},
+	},
+	"PrefixExpression": {
+		&PrefixExpression{Token: token.Token{Type: token.BANG, Literal: "!"}, Operator: "!", Right: ident("a")},
+		[]string{"a"},
+	},
+	"InfixExpression": {
+		&InfixExpression{Token: token.Token{Type: token.PLUS, Literal: "+"}, Operator: "+", Left: ident("a"), Right: ident("b")},
+		[]string{"a", "b"},
+	},
+	"IfExpression": {
+		&IfExpression{
+			Token:       token.Token{Type: token.IF, Literal: "if"},
+			Condition:   ident("a"),
+			Consequence: block(exprStmt(ident("b"))),
+			Alternative: block(exprStmt(ident("c"))),
+		},
+		[]string{"a", "b", "c"},
+	},
+	"CallExpression": {
+		&CallExpression{
+			Token:     token.Token{Type: token.LPAREN, Literal: "("},
+			Function:  ident("a"),
+			Arguments: []Expression{ident("b"), ident("c")},
+		},
+		[]string{"a", "b", "c"},
+	},
+	"ArrayLiteral": {
+		&ArrayLiteral{Token: token.Token{Type: token.LBRACKET, Literal: "["}, Elements: []Expression{ident("a"), ident("b")}},
+		[]string{"a", "b"},
+	},
+	"IndexExpression": {
+		&IndexExpression{Token: token.Token{Type: token.LBRACKET, Literal: "["}, Left: ident("a"), Index: ident("b")},
+		[]string{"a", "b"},
+	},
+	"HashLiteral": {
+		&HashLiteral{Token: token.Token{Type: token.LBRACE, Literal: "{"}, Keys: []Expression{ident("a"), ident("c")}, Values: []Expression{ident("b"), ident("d")}},
+		[]string{"a", "b", "c", "d"},
+	},
+	"MemberExpression": {
+		&MemberExpression{Token: token.Token{Type: token.DOT, Literal: "."}, Object: ident("a"), Member: ident("b")},
+		[]string{"a", "b"},
+	},
+	"ImportStatement": {
+		&ImportStatement{Token: token.Token{Type: token.IMPORT, Literal: "import"}, Path: ident("a")},
+		[]string{"a"},
+	},
+	"ThrowStatement": {
+		&ThrowStatement{Token: token.Token{Type: token.THROW, Literal: "throw"}, Value: ident("a")},
+		[]string{"a"},
+	},
+	"TryExpression": {
+		&TryExpression{
+			Token:   token.Token{Type: token.TRY, Literal: "try"},
+			Body:    block(exprStmt(ident("a"))),
+			Param:   ident("b"),
+			Catch:   block(exprStmt(ident("c"))),
+			Finally: block(exprStmt(ident("d"))),
+		},
+		[]string{"a", "b", "c", "d"},
+	},
+	"NamedArgument": {
+		&NamedArgument{Token: token.Token{Type: token.IDENT, Literal: "a"}, Name: ident("a"), Value: ident("b")},
+		[]string{"a", "b"},
+	},
+	"DefaultParameter": {
+		&DefaultParameter{Token: token.Token{Type: token.ASSIGN, Literal: "="}, Target: ident("a"), Value: ident("b")},
+		[]string{"a", "b"},
+	},
+	"ArrayPattern": {
+		&ArrayPattern{
+			Token:    token.Token{Type: token.LBRACKET, Literal: "["},
+			Elements: []Expression{ident("a"), &ArrayPattern{Elements: []Expression{ident("b")}}},
+			Rest:     ident("c"),
+		},
+		[]string{"a", "b", "c"},
+	},
+	"HashPattern": {
+		&HashPattern{
+			Token:  token.Token{Type: token.LBRACE, Literal: "{"},
+			Keys:   []*Identifier{ident("a"), ident("b")},
+			Values: []Expression{ident("a"), ident("c")},
+		},
+		[]string{"a", "a", "b", "c"},
+	},
+	"SpreadExpression": {
+		&SpreadExpression{Token: token.Token{Type: token.ELLIPSIS, Literal: "..."}, Value: ident("a")},
+		[]string{"a"},
+	},
+	"YieldExpression": {
+		&YieldExpression{Token: token.Token{Type: token.YIELD, Literal: "yield"}, Value: ident("a")},
+		[]string{"a"},
+	},
+	"ForStatement": {
+		&ForStatement{
+			Token:    token.Token{Type: token.FOR, Literal: "for"},
+			Target:   ident("a"),
+			Iterable: ident("b"),
+			Body:     block(exprStmt(ident("c"))),
+		},
+		[]string{"a", "b", "c"},
+	},
+	"MatchExpression": {
+		&MatchExpression{
+			Token:   token.Token{Type: token.MATCH, Literal: "match"},
+			Subject: ident("a"),
+			Arms: []*MatchArm{
+				{Pattern: ident("b"), Guard: ident("c"), Body: exprStmt(ident("d"))},
+				{Pattern: ident("e"), Body: block(exprStmt(ident("f")))},
+			},
+		},
+		[]string{"a", "b", "c", "d", "e", "f"},
+	},
+	"StructStatement": {
+		&StructStatement{Token: token.Token{Type: token.STRUCT, Literal: "struct"}, Name: ident("a"), Fields: []*Identifier{ident("b"), ident("c")}},
+		[]string{"a", "b", "c"},
+	},
+	"ImportExpression": {
+		&ImportExpression{Token: token.Token{Type: token.IMPORT, Literal: "import"}, Path: ident("a")},
+		[]string{"a"},
+	},
+}
+
+// Collect the name of every type in this package that implement Node , by looking for the
+// statementNode / expressionNode marker methods in the source
+func declaredNodeTypes(t *testing.T) []string {
+	fset := gotoken.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatalf("could not parse package source: %s", err)
+	}
+	names := map[string]bool{"Program": true}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*goast.FuncDecl)
+				if !ok || fn.Recv == nil {
+					continue
+				}
+				if fn.Name.Name != "statementNode" && fn.Name.Name != "expressionNode" {
+					continue
+				}
+				recv := fn.Recv.List[0].Type
+				if star, ok := recv.(*goast.StarExpr); ok {
+					recv = star.X
+				}
+				names[recv.(*goast.Ident).Name] = true
+			}
+		}
+	}
+	result := []string{}
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestWalkCoversEveryNode(t *testing.T) {
+	for _, name := range declaredNodeTypes(t) {
+		sample, ok := walkSamples[name]
+		if !ok {
+			t.Errorf("node type %s has no walk sample , add one and teach Walk and Rewrite about it", name)
+			continue
+		}
+		if got := reflect.TypeOf(sample.node).Elem().Name(); got != name {
+			t.Errorf("walk sample for %s hold a %s", name, got)
+		}
+	}
+}
+
+func TestInspect(t *testing.T) {
+	for name, sample := range walkSamples {
+		visited := []string{}
+		Inspect(sample.node, func(n Node) bool {
+			if i, ok := n.(*Identifier); ok {
+				visited = append(visited, i.Value)
+			}
+			return true
+		})
+		if strings.Join(visited, ",") != strings.Join(sample.idents, ",") {
+			t.Errorf("%s: Inspect visited identifiers %v , want %v", name, visited, sample.idents)
+		}
+	}
+}
+
+type countingVisitor struct {
+	enter, leave *int
+}
+
+func (c countingVisitor) Visit(n Node) Visitor {
+	if n == nil {
+		*c.leave++
+	} else {
+		*c.enter++
+	}
+	return c
+}
+
+func TestWalkBalanced(t *testing.T) {
+	for name, sample := range walkSamples {
+		enter, leave := 0, 0
+		Walk(countingVisitor{&enter, &leave}, sample.node)
+		if enter != leave {
+			t.Errorf("%s: Walk entered %d nodes but left %d", name, enter, leave)
+		}
+	}
+}
+
+func TestInspectPrune(t *testing.T) {
+	fn := walkSamples["FunctionLiteral"].node
+	visited := 0
+	Inspect(fn, func(n Node) bool {
+		if n != nil {
+			visited++
+		}
+		_, isBlock := n.(*BlockStatement)
+		return !isBlock
+	})
+	// function , a , b , block ( body not entered )
+	if visited != 4 {
+		t.Errorf("expected 4 visited nodes when pruning at block , got=%d", visited)
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	for name, sample := range walkSamples {
+		result := Rewrite(sample.node, func(n Node) Node {
+			if i, ok := n.(*Identifier); ok {
+				return ident(strings.ToUpper(i.Value))
+			}
+			return n
+		})
+		visited := []string{}
+		Inspect(result, func(n Node) bool {
+			if i, ok := n.(*Identifier); ok {
+				visited = append(visited, i.Value)
+			}
+			return true
+		})
+		want := []string{}
+		for _, id := range sample.idents {
+			want = append(want, strings.ToUpper(id))
+		}
+		if strings.Join(visited, ",") != strings.Join(want, ",") {
+			t.Errorf("%s: after Rewrite got identifiers %v , want %v", name, visited, want)
+		}
+		// restore the sample so test order does not matter
+		Rewrite(result, func(n Node) Node {
+			if i, ok := n.(*Identifier); ok {
+				return ident(strings.ToLower(i.Value))
+			}
+			return n
+		})
+	}
+}
+
+func TestRewriteReplaceExpression(t *testing.T) {
+	// (1 + 2) get folded to 3
+	infix := &InfixExpression{
+		Token:    token.Token{Type: token.PLUS, Literal: "+"},
+		Operator: "+",
+		Left:     &IntegerLiteral{Token: token.Token{Type: token.INT, Literal: "1"}, Value: 1},
+		Right:    &IntegerLiteral{Token: token.Token{Type: token.INT, Literal: "2"}, Value: 2},
+	}
+	program := &Program{Statements: []Statement{
+		&LetStatement{Token: token.Token{Type: token.LET, Literal: "let"}, Name: ident("x"), Value: infix},
+	}}
+	Rewrite(program, func(n Node) Node {
+		ie, ok := n.(*InfixExpression)
+		if !ok {
+			return n
+		}
+		l, lok := ie.Left.(*IntegerLiteral)
+		r, rok := ie.Right.(*IntegerLiteral)
+		if !lok || !rok || ie.Operator != "+" {
+			return n
+		}
+		return &IntegerLiteral{Token: token.Token{Type: token.INT, Literal: "3"}, Value: l.Value + r.Value}
+	})
+	if program.String() != "let x = 3;" {
+		t.Errorf("folded program wrong. got=%q", program.String())
+	}
+}
+
+func TestRewriteRemoveStatement(t *testing.T) {
+	program := &Program{Statements: []Statement{exprStmt(ident("a")), exprStmt(ident("b"))}}
+	Rewrite(program, func(n Node) Node {
+		if es, ok := n.(*ExpressionStatement); ok && es.Expression.String() == "a" {
+			return nil
+		}
+		return n
+	})
+	if len(program.Statements) != 1 || program.String() != "b" {
+		t.Errorf("expected only b to remain , got=%q", program.String())
+	}
+}
+
+func TestRewriteWrongKindPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected Rewrite to panic when replacing an expression with a statement")
+		}
+	}()
+	program := &Program{Statements: []Statement{exprStmt(ident("a"))}}
+	Rewrite(program, func(n Node) Node {
+		if _, ok := n.(*Identifier); ok {
+			return block()
+		}
+		return n
+	})
+}
+
+func typ(name string, args ...*Type) *Type {
+	return &Type{Token: token.Token{Type: token.IDENT, Literal: name}, Arguments: args}
+}
+
+func TestWalkTypes(t *testing.T) {
+	fn := &FunctionLiteral{
+		Token:          token.Token{Type: token.FUNCTION, Literal: "func"},
+		Parameters:     []Expression{ident("a"), ident("b")},
+		ParameterTypes: []*Type{typ("array", typ("int")), nil},
+		ReturnType:     typ("bool"),
+		Body:           block(exprStmt(ident("c"))),
+	}
+	let := &LetStatement{Token: token.Token{Type: token.LET, Literal: "let"}, Name: ident("f"), Type: typ("func"), Value: fn}
+
+	visited := []string{}
+	Inspect(let, func(n Node) bool {
+		switch n := n.(type) {
+		case *Identifier:
+			visited = append(visited, n.Value)
+		case *Type:
+			visited = append(visited, "<"+n.TokenLiteral()+">")
+		}
+		return true
+	})
+	expect := "f,<func>,a,<array>,<int>,b,<bool>,c"
+	if strings.Join(visited, ",") != expect {
+		t.Errorf("wrong walk order. expect=%s got=%s", expect, strings.Join(visited, ","))
+	}
+
+	// removing a parameter remove its annotation , removing a type argument keep the type
+	Rewrite(let, func(n Node) Node {
+		if i, ok := n.(*Identifier); ok && i.Value == "a" {
+			return nil
+		}
+		if t, ok := n.(*Type); ok && t.TokenLiteral() == "int" {
+			return nil
+		}
+		return n
+	})
+	if len(fn.Parameters) != 1 || len(fn.ParameterTypes) != 1 || fn.ParameterTypes[0] != nil {
+		t.Errorf("parameter types not rewritten with their parameters. got=%v", fn.ParameterTypes)
+	}
+	if let.String() != "let f: func = func(b): bool c;" {
+		t.Errorf("wrong rewritten let. got=%q", let.String())
+	}
+}
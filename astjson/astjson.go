@@ -0,0 +1,864 @@
+package astjson
+
+// JSON representation of tokens and AST , for tooling written in other languages and golden tests
+// Every node is an object with its "type" tag , its "span" in the source , its "token" and its own fields
+// Decoding ignore the span ( it is derived from the tokens ) and rebuild the exact same tree
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"khanhanh_lang/ast"
+	"khanhanh_lang/lexer"
+	"khanhanh_lang/token"
+	"strconv"
+)
+
+// Source range covered by a node , End is exclusive
+type Span struct {
+	Start token.Position `json:"start"`
+	End   token.Position `json:"end"`
+}
+
+// Tokens run the lexer until EOF and return every token , EOF included
+func Tokens(l *lexer.Lexer) []token.Token {
+	tokens := []token.Token{}
+	for {
+		tok := l.NextToken()
+		tokens = append(tokens, tok)
+		if tok.Type == token.EOF {
+			return tokens
+		}
+	}
+}
+
+// Marshal return the JSON encoding of node
+func Marshal(node ast.Node) ([]byte, error) {
+	obj, _, err := encodeNode(node)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(obj)
+}
+
+// MarshalIndent is like Marshal but format the output , see json.MarshalIndent
+func MarshalIndent(node ast.Node, prefix, indent string) ([]byte, error) {
+	obj, _, err := encodeNode(node)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(obj, prefix, indent)
+}
+
+// Unmarshal decode a node previously encoded by Marshal
+func Unmarshal(data []byte) (ast.Node, error) {
+	return decodeNode(data)
+}
+
+// UnmarshalProgram decode a whole program , data must hold a Program node
+func UnmarshalProgram(data []byte) (*ast.Program, error) {
+	node, err := decodeNode(data)
+	if err != nil {
+		return nil, err
+	}
+	program, ok := node.(*ast.Program)
+	if !ok {
+		return nil, fmt.Errorf("astjson: expected Program , got %T", node)
+	}
+	return program, nil
+}
+
+// ORDERED OBJECT
+// ---------------------------------------------------------------------------------
+// encoding/json sort map keys , we want the type tag first and the fields in declaration order
+
+type field struct {
+	key   string
+	value any
+}
+
+type jsonObject []field
+
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var out bytes.Buffer
+	out.WriteString("{")
+	for i, f := range o {
+		if i > 0 {
+			out.WriteString(",")
+		}
+		key, _ := json.Marshal(f.key)
+		out.Write(key)
+		out.WriteString(":")
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		out.Write(value)
+	}
+	out.WriteString("}")
+	return out.Bytes(), nil
+}
+
+// SPAN
+// ---------------------------------------------------------------------------------
+func before(a, b token.Position) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+}
+
+// grow the span so it cover tok , synthetic tokens ( line 0 ) are ignored
+func (s *Span) addToken(tok token.Token) {
+	if tok.Line == 0 {
+		return
+	}
+	s.add(Span{Start: tok.Pos(), End: tok.End()})
+}
+
+func (s *Span) add(other Span) {
+	if other.Start.Line == 0 {
+		return
+	}
+	if s.Start.Line == 0 || before(other.Start, s.Start) {
+		s.Start = other.Start
+	}
+	if s.End.Line == 0 || before(s.End, other.End) {
+		s.End = other.End
+	}
+}
+
+// ENCODE
+// ---------------------------------------------------------------------------------
+// encode a node and return its span , the span of a node cover its own tokens and all of its children
+func encodeNode(node ast.Node) (jsonObject, Span, error) {
+	enc := &encoder{}
+	var fields jsonObject
+	switch n := node.(type) {
+	case *ast.Program:
+		fields = jsonObject{
+			{"statements", enc.statements(n.Statements)},
+			{"comments", enc.comments(n.Comments)},
+		}
+		return enc.finish("Program", nil, fields)
+
+	// Statements
+	case *ast.LetStatement:
+		enc.span.addToken(n.Token)
+		// a destructuring let keep its pattern in place of the name
+		fields = jsonObject{{"name", enc.node(n.Target())}, {"annotation", enc.node(n.Type)}, {"value", enc.node(n.Value)}}
+		return enc.finish("LetStatement", &n.Token, fields)
+	case *ast.ReturnStatement:
+		enc.span.addToken(n.Token)
+		fields = jsonObject{{"returnValue", enc.node(n.ReturnValue)}}
+		return enc.finish("ReturnStatement", &n.Token, fields)
+	case *ast.ExpressionStatement:
+		fields = jsonObject{{"expression", enc.node(n.Expression)}}
+		return enc.finish("ExpressionStatement", &n.Token, fields)
+	case *ast.ImportStatement:
+		enc.span.addToken(n.Token)
+		fields = jsonObject{{"path", enc.node(n.Path)}}
+		return enc.finish("ImportStatement", &n.Token, fields)
+	case *ast.ThrowStatement:
+		enc.span.addToken(n.Token)
+		fields = jsonObject{{"value", enc.node(n.Value)}}
+		return enc.finish("ThrowStatement", &n.Token, fields)
+	case *ast.ForStatement:
+		enc.span.addToken(n.Token)
+		fields = jsonObject{
+			{"target", enc.node(n.Target)},
+			{"iterable", enc.node(n.Iterable)},
+			{"body", enc.node(n.Body)},
+		}
+		return enc.finish("ForStatement", &n.Token, fields)
+	case *ast.StructStatement:
+		enc.span.addToken(n.Token)
+		enc.span.addToken(n.Rbrace)
+		structFields := []any{}
+		for _, field := range n.Fields {
+			structFields = append(structFields, enc.node(field))
+		}
+		fields = jsonObject{{"name", enc.node(n.Name)}, {"fields", structFields}, {"rbrace", n.Rbrace}}
+		return enc.finish("StructStatement", &n.Token, fields)
+	case *ast.BlockStatement:
+		enc.span.addToken(n.Token)
+		enc.span.addToken(n.Rbrace)
+		fields = jsonObject{{"statements", enc.statements(n.Statements)}, {"rbrace", n.Rbrace}}
+		return enc.finish("BlockStatement", &n.Token, fields)
+
+	// Literals
+	case *ast.Identifier:
+		enc.span.addToken(n.Token)
+		return enc.finish("Identifier", &n.Token, jsonObject{{"value", n.Value}})
+	case *ast.IntegerLiteral:
+		enc.span.addToken(n.Token)
+		return enc.finish("IntegerLiteral", &n.Token, jsonObject{{"value", n.Value}})
+	case *ast.FloatLiteral:
+		enc.span.addToken(n.Token)
+		return enc.finish("FloatLiteral", &n.Token, jsonObject{{"value", n.Value}})
+	case *ast.StringLiteral:
+		enc.span.addToken(n.Token)
+		return enc.finish("StringLiteral", &n.Token, jsonObject{{"value", n.Value}})
+	case *ast.BooleanLiteral:
+		enc.span.addToken(n.Token)
+		return enc.finish("BooleanLiteral", &n.Token, jsonObject{{"value", n.Value}})
+	case *ast.FunctionLiteral:
+		enc.span.addToken(n.Token)
+		// parameterTypes follow parameters , with null for a parameter without annotation
+		types := []any{}
+		for i := range n.Parameters {
+			types = append(types, enc.node(n.ParameterType(i)))
+		}
+		fields = jsonObject{
+			{"parameters", enc.expressions(n.Parameters)},
+			{"parameterTypes", types},
+			{"returnType", enc.node(n.ReturnType)},
+			{"generator", n.Generator},
+			{"body", enc.node(n.Body)},
+		}
+		return enc.finish("FunctionLiteral", &n.Token, fields)
+
+	// Expressions
+	case *ast.PrefixExpression:
+		enc.span.addToken(n.Token)
+		fields = jsonObject{{"operator", n.Operator}, {"right", enc.node(n.Right)}}
+		return enc.finish("PrefixExpression", &n.Token, fields)
+	case *ast.InfixExpression:
+		enc.span.addToken(n.Token)
+		fields = jsonObject{{"operator", n.Operator}, {"left", enc.node(n.Left)}, {"right", enc.node(n.Right)}}
+		return enc.finish("InfixExpression", &n.Token, fields)
+	case *ast.IfExpression:
+		enc.span.addToken(n.Token)
+		fields = jsonObject{
+			{"condition", enc.node(n.Condition)},
+			{"consequence", enc.node(n.Consequence)},
+			{"alternative", enc.node(n.Alternative)},
+		}
+		return enc.finish("IfExpression", &n.Token, fields)
+	case *ast.TryExpression:
+		enc.span.addToken(n.Token)
+		fields = jsonObject{
+			{"body", enc.node(n.Body)},
+			{"param", enc.node(n.Param)},
+			{"catch", enc.node(n.Catch)},
+			{"finally", enc.node(n.Finally)},
+		}
+		return enc.finish("TryExpression", &n.Token, fields)
+	case *ast.CallExpression:
+		enc.span.addToken(n.Token)
+		enc.span.addToken(n.Rparen)
+		fields = jsonObject{
+			{"function", enc.node(n.Function)},
+			{"arguments", enc.expressions(n.Arguments)},
+			{"rparen", n.Rparen},
+		}
+		return enc.finish("CallExpression", &n.Token, fields)
+	case *ast.ArrayLiteral:
+		enc.span.addToken(n.Token)
+		enc.span.addToken(n.Rbracket)
+		fields = jsonObject{{"elements", enc.expressions(n.Elements)}, {"rbracket", n.Rbracket}}
+		return enc.finish("ArrayLiteral", &n.Token, fields)
+	case *ast.IndexExpression:
+		enc.span.addToken(n.Token)
+		enc.span.addToken(n.Rbracket)
+		fields = jsonObject{{"left", enc.node(n.Left)}, {"index", enc.node(n.Index)}, {"rbracket", n.Rbracket}}
+		return enc.finish("IndexExpression", &n.Token, fields)
+	case *ast.HashLiteral:
+		enc.span.addToken(n.Token)
+		enc.span.addToken(n.Rbrace)
+		pairs := []any{}
+		for i, key := range n.Keys {
+			pairs = append(pairs, jsonObject{{"key", enc.node(key)}, {"value", enc.node(n.Values[i])}})
+		}
+		fields = jsonObject{{"pairs", pairs}, {"rbrace", n.Rbrace}}
+		return enc.finish("HashLiteral", &n.Token, fields)
+	case *ast.MemberExpression:
+		enc.span.addToken(n.Token)
+		fields = jsonObject{{"object", enc.node(n.Object)}, {"member", enc.node(n.Member)}}
+		return enc.finish("MemberExpression", &n.Token, fields)
+	case *ast.ImportExpression:
+		enc.span.addToken(n.Token)
+		enc.span.addToken(n.Rparen)
+		fields = jsonObject{{"path", enc.node(n.Path)}, {"rparen", n.Rparen}}
+		return enc.finish("ImportExpression", &n.Token, fields)
+	case *ast.NamedArgument:
+		enc.span.addToken(n.Token)
+		fields = jsonObject{{"name", enc.node(n.Name)}, {"value", enc.node(n.Value)}}
+		return enc.finish("NamedArgument", &n.Token, fields)
+	case *ast.DefaultParameter:
+		enc.span.addToken(n.Token)
+		fields = jsonObject{{"target", enc.node(n.Target)}, {"value", enc.node(n.Value)}}
+		return enc.finish("DefaultParameter", &n.Token, fields)
+	case *ast.ArrayPattern:
+		enc.span.addToken(n.Token)
+		enc.span.addToken(n.Rbracket)
+		fields = jsonObject{
+			{"elements", enc.expressions(n.Elements)},
+			{"rest", enc.node(n.Rest)},
+			{"rbracket", n.Rbracket},
+		}
+		return enc.finish("ArrayPattern", &n.Token, fields)
+	case *ast.HashPattern:
+		enc.span.addToken(n.Token)
+		enc.span.addToken(n.Rbrace)
+		pairs := []any{}
+		for i, key := range n.Keys {
+			pairs = append(pairs, jsonObject{{"key", enc.node(key)}, {"value", enc.node(n.Values[i])}})
+		}
+		fields = jsonObject{{"pairs", pairs}, {"rbrace", n.Rbrace}}
+		return enc.finish("HashPattern", &n.Token, fields)
+	case *ast.SpreadExpression:
+		enc.span.addToken(n.Token)
+		fields = jsonObject{{"value", enc.node(n.Value)}}
+		return enc.finish("SpreadExpression", &n.Token, fields)
+	case *ast.YieldExpression:
+		enc.span.addToken(n.Token)
+		fields = jsonObject{{"value", enc.node(n.Value)}}
+		return enc.finish("YieldExpression", &n.Token, fields)
+	case *ast.MatchExpression:
+		enc.span.addToken(n.Token)
+		enc.span.addToken(n.Rbrace)
+		arms := []any{}
+		for _, arm := range n.Arms {
+			arms = append(arms, jsonObject{
+				{"pattern", enc.node(arm.Pattern)},
+				{"guard", enc.node(arm.Guard)},
+				{"arrow", arm.Arrow},
+				{"body", enc.node(arm.Body)},
+			})
+		}
+		fields = jsonObject{{"subject", enc.node(n.Subject)}, {"arms", arms}, {"rbrace", n.Rbrace}}
+		return enc.finish("MatchExpression", &n.Token, fields)
+
+	// Types
+	case *ast.Type:
+		enc.span.addToken(n.Token)
+		enc.span.addToken(n.Close)
+		args := []any{}
+		for _, arg := range n.Arguments {
+			args = append(args, enc.node(arg))
+		}
+		fields = jsonObject{{"arguments", args}, {"close", n.Close}}
+		return enc.finish("Type", &n.Token, fields)
+	}
+	return nil, Span{}, fmt.Errorf("astjson: unsupported node type %T", node)
+}
+
+// collect the span of the children and the first error while encoding a node
+type encoder struct {
+	span Span
+	err  error
+}
+
+func (enc *encoder) finish(tag string, tok *token.Token, fields jsonObject) (jsonObject, Span, error) {
+	if enc.err != nil {
+		return nil, Span{}, enc.err
+	}
+	obj := jsonObject{{"type", tag}, {"span", enc.span}}
+	if tok != nil {
+		obj = append(obj, field{"token", *tok})
+	}
+	return append(obj, fields...), enc.span, nil
+}
+
+// encode a child , nil child ( missing else , ... ) is encoded as null
+func (enc *encoder) node(node ast.Node) any {
+	if isNil(node) || enc.err != nil {
+		return nil
+	}
+	obj, span, err := encodeNode(node)
+	if err != nil {
+		enc.err = err
+		return nil
+	}
+	enc.span.add(span)
+	return obj
+}
+
+func (enc *encoder) statements(stmts []ast.Statement) []any {
+	result := []any{}
+	for _, stmt := range stmts {
+		result = append(result, enc.node(stmt))
+	}
+	return result
+}
+
+func (enc *encoder) expressions(exps []ast.Expression) []any {
+	result := []any{}
+	for _, exp := range exps {
+		result = append(result, enc.node(exp))
+	}
+	return result
+}
+
+func (enc *encoder) comments(comments []*ast.Comment) []any {
+	result := []any{}
+	for _, comment := range comments {
+		result = append(result, jsonObject{{"token", comment.Token}, {"text", comment.Text}})
+	}
+	return result
+}
+
+// the parser can leave typed nil pointer inside interface when a statement fail to parse
+func isNil(node ast.Node) bool {
+	switch n := node.(type) {
+	case nil:
+		return true
+	case *ast.Identifier:
+		return n == nil
+	case *ast.BlockStatement:
+		return n == nil
+	case *ast.LetStatement:
+		return n == nil
+	case *ast.Type:
+		return n == nil
+	}
+	return false
+}
+
+// DECODE
+// ---------------------------------------------------------------------------------
+// raw node , every field is kept raw and decoded on demand depending on the type tag
+type rawNode map[string]json.RawMessage
+
+func (r rawNode) decode(key string, v any) error {
+	value, ok := r[key]
+	if !ok {
+		return fmt.Errorf("astjson: missing field %q", key)
+	}
+	if err := json.Unmarshal(value, v); err != nil {
+		return fmt.Errorf("astjson: field %q: %w", key, err)
+	}
+	return nil
+}
+
+func decodeNode(data []byte) (ast.Node, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("astjson: missing node")
+	}
+	if bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+	raw := rawNode{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("astjson: %w", err)
+	}
+	var tag string
+	if err := raw.decode("type", &tag); err != nil {
+		return nil, err
+	}
+	dec := &decoder{raw: raw}
+	var tok token.Token
+	if tag != "Program" {
+		dec.decode("token", &tok)
+	}
+
+	var node ast.Node
+	switch tag {
+	case "Program":
+		program := &ast.Program{Statements: dec.statements("statements")}
+		var comments []struct {
+			Token token.Token `json:"token"`
+			Text  string      `json:"text"`
+		}
+		dec.decode("comments", &comments)
+		for _, comment := range comments {
+			program.Comments = append(program.Comments, &ast.Comment{Token: comment.Token, Text: comment.Text})
+		}
+		node = program
+
+	// Statements
+	case "LetStatement":
+		stmt := &ast.LetStatement{Token: tok, Type: dec.typ("annotation"), Value: dec.expression("value")}
+		target := dec.expression("name")
+		if name, ok := target.(*ast.Identifier); ok {
+			stmt.Name = name
+		} else {
+			stmt.Pattern = target
+		}
+		node = stmt
+	case "ReturnStatement":
+		node = &ast.ReturnStatement{Token: tok, ReturnValue: dec.expression("returnValue")}
+	case "ExpressionStatement":
+		node = &ast.ExpressionStatement{Token: tok, Expression: dec.expression("expression")}
+	case "ImportStatement":
+		node = &ast.ImportStatement{Token: tok, Path: dec.expression("path")}
+	case "ThrowStatement":
+		node = &ast.ThrowStatement{Token: tok, Value: dec.expression("value")}
+	case "ForStatement":
+		node = &ast.ForStatement{
+			Token:    tok,
+			Target:   dec.expression("target"),
+			Iterable: dec.expression("iterable"),
+			Body:     dec.block("body"),
+		}
+	case "StructStatement":
+		stmt := &ast.StructStatement{Token: tok, Name: dec.identifier("name"), Fields: []*ast.Identifier{}}
+		var fields []json.RawMessage
+		dec.decode("fields", &fields)
+		for _, field := range fields {
+			stmt.Fields = append(stmt.Fields, dec.identifierFrom("fields", field))
+		}
+		dec.decode("rbrace", &stmt.Rbrace)
+		node = stmt
+	case "BlockStatement":
+		block := &ast.BlockStatement{Token: tok, Statements: dec.statements("statements")}
+		dec.decode("rbrace", &block.Rbrace)
+		node = block
+
+	// Literals
+	case "Identifier":
+		ident := &ast.Identifier{Token: tok}
+		dec.decode("value", &ident.Value)
+		node = ident
+	case "IntegerLiteral":
+		lit := &ast.IntegerLiteral{Token: tok}
+		var value json.Number
+		dec.decode("value", &value)
+		if dec.err == nil {
+			lit.Value, dec.err = strconv.ParseInt(string(value), 10, 64)
+		}
+		node = lit
+	case "FloatLiteral":
+		lit := &ast.FloatLiteral{Token: tok}
+		var value json.Number
+		dec.decode("value", &value)
+		if dec.err == nil {
+			lit.Value, dec.err = strconv.ParseFloat(string(value), 64)
+		}
+		node = lit
+	case "StringLiteral":
+		lit := &ast.StringLiteral{Token: tok}
+		dec.decode("value", &lit.Value)
+		node = lit
+	case "BooleanLiteral":
+		lit := &ast.BooleanLiteral{Token: tok}
+		dec.decode("value", &lit.Value)
+		node = lit
+	case "FunctionLiteral":
+		lit := &ast.FunctionLiteral{Token: tok, Parameters: []ast.Expression{}}
+		var params []json.RawMessage
+		dec.decode("parameters", &params)
+		for _, param := range params {
+			lit.Parameters = append(lit.Parameters, dec.elementFrom("parameters", param))
+		}
+		// like the parser , the types stay nil when no parameter has an annotation
+		var types []json.RawMessage
+		dec.decode("parameterTypes", &types)
+		for i, raw := range types {
+			typ := dec.typFrom(raw)
+			if typ != nil && lit.ParameterTypes == nil {
+				lit.ParameterTypes = make([]*ast.Type, len(types))
+			}
+			if typ != nil {
+				lit.ParameterTypes[i] = typ
+			}
+		}
+		lit.ReturnType = dec.typ("returnType")
+		dec.decode("generator", &lit.Generator)
+		lit.Body = dec.block("body")
+		node = lit
+
+	// Expressions
+	case "PrefixExpression":
+		exp := &ast.PrefixExpression{Token: tok, Right: dec.expression("right")}
+		dec.decode("operator", &exp.Operator)
+		node = exp
+	case "InfixExpression":
+		exp := &ast.InfixExpression{Token: tok, Left: dec.expression("left"), Right: dec.expression("right")}
+		dec.decode("operator", &exp.Operator)
+		node = exp
+	case "IfExpression":
+		node = &ast.IfExpression{
+			Token:       tok,
+			Condition:   dec.expression("condition"),
+			Consequence: dec.block("consequence"),
+			Alternative: dec.optionalBlock("alternative"),
+		}
+	case "TryExpression":
+		node = &ast.TryExpression{
+			Token:   tok,
+			Body:    dec.block("body"),
+			Param:   dec.optionalIdentifier("param"),
+			Catch:   dec.optionalBlock("catch"),
+			Finally: dec.optionalBlock("finally"),
+		}
+	case "CallExpression":
+		exp := &ast.CallExpression{Token: tok, Function: dec.expression("function"), Arguments: []ast.Expression{}}
+		var args []json.RawMessage
+		dec.decode("arguments", &args)
+		for _, arg := range args {
+			exp.Arguments = append(exp.Arguments, dec.elementFrom("arguments", arg))
+		}
+		dec.decode("rparen", &exp.Rparen)
+		node = exp
+	case "ArrayLiteral":
+		lit := &ast.ArrayLiteral{Token: tok, Elements: []ast.Expression{}}
+		var elements []json.RawMessage
+		dec.decode("elements", &elements)
+		for _, el := range elements {
+			lit.Elements = append(lit.Elements, dec.elementFrom("elements", el))
+		}
+		dec.decode("rbracket", &lit.Rbracket)
+		node = lit
+	case "IndexExpression":
+		exp := &ast.IndexExpression{Token: tok, Left: dec.expression("left"), Index: dec.expression("index")}
+		dec.decode("rbracket", &exp.Rbracket)
+		node = exp
+	case "HashLiteral":
+		lit := &ast.HashLiteral{Token: tok, Keys: []ast.Expression{}, Values: []ast.Expression{}}
+		var pairs []struct {
+			Key   json.RawMessage `json:"key"`
+			Value json.RawMessage `json:"value"`
+		}
+		dec.decode("pairs", &pairs)
+		for _, pair := range pairs {
+			lit.Keys = append(lit.Keys, dec.elementFrom("pairs", pair.Key))
+			lit.Values = append(lit.Values, dec.elementFrom("pairs", pair.Value))
+		}
+		dec.decode("rbrace", &lit.Rbrace)
+		node = lit
+	case "MemberExpression":
+		node = &ast.MemberExpression{Token: tok, Object: dec.expression("object"), Member: dec.identifier("member")}
+	case "ImportExpression":
+		exp := &ast.ImportExpression{Token: tok, Path: dec.expression("path")}
+		dec.decode("rparen", &exp.Rparen)
+		node = exp
+	case "NamedArgument":
+		node = &ast.NamedArgument{Token: tok, Name: dec.identifier("name"), Value: dec.expression("value")}
+	case "DefaultParameter":
+		node = &ast.DefaultParameter{Token: tok, Target: dec.expression("target"), Value: dec.expression("value")}
+	case "ArrayPattern":
+		pattern := &ast.ArrayPattern{Token: tok, Elements: []ast.Expression{}, Rest: dec.optionalIdentifier("rest")}
+		var elements []json.RawMessage
+		dec.decode("elements", &elements)
+		for _, el := range elements {
+			pattern.Elements = append(pattern.Elements, dec.elementFrom("elements", el))
+		}
+		dec.decode("rbracket", &pattern.Rbracket)
+		node = pattern
+	case "HashPattern":
+		pattern := &ast.HashPattern{Token: tok, Keys: []*ast.Identifier{}, Values: []ast.Expression{}}
+		var pairs []struct {
+			Key   json.RawMessage `json:"key"`
+			Value json.RawMessage `json:"value"`
+		}
+		dec.decode("pairs", &pairs)
+		for _, pair := range pairs {
+			pattern.Keys = append(pattern.Keys, dec.identifierFrom("pairs", pair.Key))
+			pattern.Values = append(pattern.Values, dec.elementFrom("pairs", pair.Value))
+		}
+		dec.decode("rbrace", &pattern.Rbrace)
+		node = pattern
+	case "SpreadExpression":
+		node = &ast.SpreadExpression{Token: tok, Value: dec.expression("value")}
+	case "YieldExpression":
+		node = &ast.YieldExpression{Token: tok, Value: dec.optionalExpression("value")}
+	case "MatchExpression":
+		exp := &ast.MatchExpression{Token: tok, Subject: dec.expression("subject"), Arms: []*ast.MatchArm{}}
+		var arms []struct {
+			Pattern json.RawMessage `json:"pattern"`
+			Guard   json.RawMessage `json:"guard"`
+			Arrow   token.Token     `json:"arrow"`
+			Body    json.RawMessage `json:"body"`
+		}
+		dec.decode("arms", &arms)
+		for _, arm := range arms {
+			exp.Arms = append(exp.Arms, &ast.MatchArm{
+				Pattern: dec.elementFrom("arms", arm.Pattern),
+				Guard:   dec.expressionFrom(arm.Guard),
+				Arrow:   arm.Arrow,
+				Body:    dec.statementFrom("arms", arm.Body),
+			})
+		}
+		dec.decode("rbrace", &exp.Rbrace)
+		node = exp
+
+	// Types
+	case "Type":
+		typ := &ast.Type{Token: tok}
+		var args []json.RawMessage
+		dec.decode("arguments", &args)
+		for _, arg := range args {
+			argument := dec.typFrom(arg)
+			dec.missing("arguments", argument == nil)
+			typ.Arguments = append(typ.Arguments, argument)
+		}
+		dec.decode("close", &typ.Close)
+		node = typ
+
+	default:
+		return nil, fmt.Errorf("astjson: unknown node type %q", tag)
+	}
+
+	if dec.err != nil {
+		return nil, fmt.Errorf("%s: %w", tag, dec.err)
+	}
+	return node, nil
+}
+
+// decode the children of a node , keeping the first error
+type decoder struct {
+	raw rawNode
+	err error
+}
+
+func (dec *decoder) decode(key string, v any) {
+	if dec.err == nil {
+		dec.err = dec.raw.decode(key, v)
+	}
+}
+
+func (dec *decoder) child(data json.RawMessage) ast.Node {
+	if dec.err != nil {
+		return nil
+	}
+	node, err := decodeNode(data)
+	if err != nil {
+		dec.err = err
+	}
+	return node
+}
+
+func (dec *decoder) expressionFrom(data json.RawMessage) ast.Expression {
+	node := dec.child(data)
+	if node == nil {
+		return nil
+	}
+	exp, ok := node.(ast.Expression)
+	if !ok && dec.err == nil {
+		dec.err = fmt.Errorf("astjson: expected expression , got %T", node)
+	}
+	return exp
+}
+
+// missing report key as missing when the child decoded from it is null , a node cannot be evaluated without
+// its required children
+func (dec *decoder) missing(key string, null bool) {
+	if dec.err == nil && null {
+		dec.err = fmt.Errorf("astjson: missing field %q", key)
+	}
+}
+
+// an element of the list in key , null is not a valid element
+func (dec *decoder) elementFrom(key string, data json.RawMessage) ast.Expression {
+	exp := dec.expressionFrom(data)
+	dec.missing(key, exp == nil)
+	return exp
+}
+
+func (dec *decoder) expression(key string) ast.Expression {
+	exp := dec.optionalExpression(key)
+	dec.missing(key, exp == nil)
+	return exp
+}
+
+func (dec *decoder) optionalExpression(key string) ast.Expression {
+	if dec.err != nil {
+		return nil
+	}
+	return dec.expressionFrom(dec.raw[key])
+}
+
+// a required identifier , key name the field it come from
+func (dec *decoder) identifierFrom(key string, data json.RawMessage) *ast.Identifier {
+	ident := dec.optionalIdentifierFrom(data)
+	dec.missing(key, ident == nil)
+	return ident
+}
+
+func (dec *decoder) optionalIdentifierFrom(data json.RawMessage) *ast.Identifier {
+	node := dec.child(data)
+	if node == nil {
+		return nil
+	}
+	ident, ok := node.(*ast.Identifier)
+	if !ok && dec.err == nil {
+		dec.err = fmt.Errorf("astjson: expected Identifier , got %T", node)
+	}
+	return ident
+}
+
+func (dec *decoder) identifier(key string) *ast.Identifier {
+	if dec.err != nil {
+		return nil
+	}
+	return dec.identifierFrom(key, dec.raw[key])
+}
+
+func (dec *decoder) optionalIdentifier(key string) *ast.Identifier {
+	if dec.err != nil {
+		return nil
+	}
+	return dec.optionalIdentifierFrom(dec.raw[key])
+}
+
+func (dec *decoder) block(key string) *ast.BlockStatement {
+	block := dec.optionalBlock(key)
+	dec.missing(key, block == nil)
+	return block
+}
+
+func (dec *decoder) optionalBlock(key string) *ast.BlockStatement {
+	if dec.err != nil {
+		return nil
+	}
+	node := dec.child(dec.raw[key])
+	if node == nil {
+		return nil
+	}
+	block, ok := node.(*ast.BlockStatement)
+	if !ok && dec.err == nil {
+		dec.err = fmt.Errorf("astjson: expected BlockStatement , got %T", node)
+	}
+	return block
+}
+
+func (dec *decoder) statementFrom(key string, data json.RawMessage) ast.Statement {
+	node := dec.child(data)
+	if node == nil {
+		dec.missing(key, true)
+		return nil
+	}
+	stmt, ok := node.(ast.Statement)
+	if !ok && dec.err == nil {
+		dec.err = fmt.Errorf("astjson: expected statement , got %T", node)
+	}
+	return stmt
+}
+
+func (dec *decoder) statements(key string) []ast.Statement {
+	var raws []json.RawMessage
+	dec.decode(key, &raws)
+	result := []ast.Statement{}
+	for _, raw := range raws {
+		node := dec.child(raw)
+		if dec.err != nil {
+			return nil
+		}
+		stmt, ok := node.(ast.Statement)
+		if !ok {
+			dec.err = fmt.Errorf("astjson: expected statement , got %T", node)
+			return nil
+		}
+		result = append(result, stmt)
+	}
+	return result
+}
+
+func (dec *decoder) typFrom(data json.RawMessage) *ast.Type {
+	node := dec.child(data)
+	if node == nil {
+		return nil
+	}
+	typ, ok := node.(*ast.Type)
+	if !ok && dec.err == nil {
+		dec.err = fmt.Errorf("astjson: expected Type , got %T", node)
+	}
+	return typ
+}
+
+func (dec *decoder) typ(key string) *ast.Type {
+	if dec.err != nil {
+		return nil
+	}
+	return dec.typFrom(dec.raw[key])
+}
@@ -0,0 +1,157 @@
+package astjson
+
+import (
+	"khanhanh_lang/ast"
+	"khanhanh_lang/evaluator"
+	"khanhanh_lang/lexer"
+	"khanhanh_lang/object"
+	"khanhanh_lang/parser"
+	"khanhanh_lang/token"
+	"strings"
+	"testing"
+)
+
+func parse(t *testing.T, input string) *ast.Program {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors: %v", p.Errors())
+	}
+	return program
+}
+
+func TestMarshalGolden(t *testing.T) {
+	program := parse(t, `let x = -5 + "a";`)
+	got, err := Marshal(program)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	expected := `{"type":"Program","span":{"start":{"line":1,"column":1},"end":{"line":1,"column":17}},` +
+		`"statements":[{"type":"LetStatement","span":{"start":{"line":1,"column":1},"end":{"line":1,"column":17}},` +
+		`"token":{"type":"LET","literal":"let","line":1,"column":1},` +
+		`"name":{"type":"Identifier","span":{"start":{"line":1,"column":5},"end":{"line":1,"column":6}},` +
+		`"token":{"type":"INDENT","literal":"x","line":1,"column":5},"value":"x"},"annotation":null,` +
+		`"value":{"type":"InfixExpression","span":{"start":{"line":1,"column":9},"end":{"line":1,"column":17}},` +
+		`"token":{"type":"+","literal":"+","line":1,"column":12},"operator":"+",` +
+		`"left":{"type":"PrefixExpression","span":{"start":{"line":1,"column":9},"end":{"line":1,"column":11}},` +
+		`"token":{"type":"-","literal":"-","line":1,"column":9},"operator":"-",` +
+		`"right":{"type":"IntegerLiteral","span":{"start":{"line":1,"column":10},"end":{"line":1,"column":11}},` +
+		`"token":{"type":"INT","literal":"5","line":1,"column":10},"value":5}},` +
+		`"right":{"type":"StringLiteral","span":{"start":{"line":1,"column":14},"end":{"line":1,"column":17}},` +
+		`"token":{"type":"STRING","literal":"a","line":1,"column":14},"value":"a"}}}],"comments":[]}`
+	if string(got) != expected {
+		t.Errorf("wrong encoding\nexpected: %s\ngot:      %s", expected, got)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	// every node type appear at least once
+	input := `// comment
+let add = func(x, y) { return x + y; };
+let r = if (!(1 < 2)) { "no" } else { add([1, 2][0], 2) };
+let u = strings.upper("a");
+let f = 1.5;
+let h = {"a": [1], 2: f};
+struct Point { x, y };
+let safe = try { throw "x" } catch (e) { 1 } finally { 2 };
+let plain = try { 1 } finally { 2 };
+let load = func() { import "lib/list"; import("lib/" + "map") };
+let [first, {x, y: [b, ...more]}] = [1, {"x": 2, "y": [3]}];
+let swap = func([a, b], {k}) { [b, a, k] };
+const LIMIT = 3;
+let scale = func(x, by = 2, ...more) { x * by };
+let typed: array<int> = [1];
+let check = func(a, b: hash<string, int> = {}, ...rest: array): bool { true };
+let scaled = scale(...[1], by: 3);
+let m = match [1, 2] { [0, ...rest] => rest, {"k": v} => v, n if n => { n }, _ => 3 };
+let gen = func*(xs) { for [i, x] in enumerate(xs) { let sent = yield x; yield } };
+let count = func() { yield 1 };
+true;
+r`
+	program := parse(t, input)
+	data, err := MarshalIndent(program, "", "  ")
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	decoded, err := UnmarshalProgram(data)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if decoded.String() != program.String() {
+		t.Errorf("round trip changed the program\nbefore: %s\nafter:  %s", program.String(), decoded.String())
+	}
+	if len(decoded.Comments) != 1 || decoded.Comments[0].Text != "// comment" {
+		t.Errorf("comments not kept , got %v", decoded.Comments)
+	}
+	again, err := MarshalIndent(decoded, "", "  ")
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if string(again) != string(data) {
+		t.Errorf("encoding is not stable after a round trip")
+	}
+
+	result := evaluator.Eval(decoded, object.NewTracker())
+	if result == nil || result.Inspect() != "3" {
+		t.Errorf("decoded program should evaluate to 3 , got %v", result)
+	}
+}
+
+func TestSpan(t *testing.T) {
+	program := parse(t, "add(1,\n  2)")
+	obj, span, err := encodeNode(program.Statements[0].(*ast.ExpressionStatement).Expression)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if obj[0].value != "CallExpression" {
+		t.Fatalf("expected CallExpression , got %v", obj[0].value)
+	}
+	expected := Span{Start: token.Position{Line: 1, Column: 1}, End: token.Position{Line: 2, Column: 5}}
+	if span != expected {
+		t.Errorf("wrong span , expected %+v , got %+v", expected, span)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"type":"Nope"}`, `unknown node type "Nope"`},
+		{`{"statements":[]}`, `missing field "type"`},
+		{`{"type":"Identifier","token":{}}`, `missing field "value"`},
+		{`{"type":"Program","statements":[{"type":"Identifier","token":{},"value":"x"}],"comments":[]}`, "expected statement"},
+		{`[1]`, "astjson: json"},
+		// a null or missing child the node cannot do without
+		{`{"type":"InfixExpression","token":{},"operator":"+","left":null,"right":null}`, `missing field "left"`},
+		{`{"type":"InfixExpression","token":{},"operator":"+","left":{"type":"Identifier","token":{},"value":"a"},"right":null}`, `missing field "right"`},
+		{`{"type":"InfixExpression","token":{},"operator":"+","left":{"type":"Identifier","token":{},"value":"a"}}`, "missing node"},
+		{`{"type":"LetStatement","token":{},"annotation":null,"name":null,"value":{"type":"Identifier","token":{},"value":"a"}}`, `missing field "name"`},
+		{`{"type":"LetStatement","token":{},"annotation":null,"name":{"type":"Identifier","token":{},"value":"a"},"value":null}`, `missing field "value"`},
+		{`{"type":"PrefixExpression","token":{},"operator":"-","right":null}`, `missing field "right"`},
+		{`{"type":"ReturnStatement","token":{},"returnValue":null}`, `missing field "returnValue"`},
+		{`{"type":"ExpressionStatement","token":{},"expression":null}`, `missing field "expression"`},
+		{`{"type":"IfExpression","token":{},"condition":null,"consequence":null,"alternative":null}`, `missing field "condition"`},
+		{`{"type":"CallExpression","token":{},"function":null,"arguments":[],"rparen":{}}`, `missing field "function"`},
+		{`{"type":"CallExpression","token":{},"function":{"type":"Identifier","token":{},"value":"f"},"arguments":[null],"rparen":{}}`, `missing field "arguments"`},
+		{`{"type":"ForStatement","token":{},"target":{"type":"Identifier","token":{},"value":"x"},"iterable":null,"body":null}`, `missing field "iterable"`},
+		{`{"type":"FunctionLiteral","token":{},"parameters":[],"parameterTypes":[],"returnType":null,"generator":false,"body":null}`, `missing field "body"`},
+		{`{"type":"TryExpression","token":{},"body":null,"param":null,"catch":null,"finally":null}`, `missing field "body"`},
+		{`{"type":"MemberExpression","token":{},"object":{"type":"Identifier","token":{},"value":"a"},"member":null}`, `missing field "member"`},
+		{`{"type":"IndexExpression","token":{},"left":null,"index":null,"rbracket":{}}`, `missing field "left"`},
+		{`{"type":"ArrayLiteral","token":{},"elements":[null],"rbracket":{}}`, `missing field "elements"`},
+	}
+	for _, test := range tests {
+		_, err := Unmarshal([]byte(test.input))
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("input %s: expected error containing %q , got %v", test.input, test.expected, err)
+		}
+	}
+}
+
+func TestTokens(t *testing.T) {
+	tokens := Tokens(lexer.New("let a = 1;"))
+	if len(tokens) != 6 || tokens[5].Type != token.EOF {
+		t.Fatalf("expected 5 tokens and EOF , got %v", tokens)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"khanhanh_lang/lexer"
+	"khanhanh_lang/parser"
+	"khanhanh_lang/types"
+	"os"
+	"strings"
+)
+
+// check subcommand
+// infer the types of each file ( or stdin ) without running it and list the mismatches , exit with 1 when
+// there is one , see types.Check
+func runCheck(args []string) int {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: khanhanh_lang check [file ...]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if flags.NArg() == 0 {
+		src, err := readInput(nil)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return checkFile("<stdin>", src)
+	}
+
+	status := 0
+	for _, path := range flags.Args() {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			status = 1
+			continue
+		}
+		if code := checkFile(path, src); code != 0 {
+			status = code
+		}
+	}
+	return status
+}
+
+func checkFile(path string, src []byte) int {
+	p := parser.New(lexer.New(string(src)))
+	program := p.ParseProgram()
+	problems := p.Errors()
+	if len(problems) == 0 {
+		problems = types.Check(program)
+	}
+	if len(problems) == 0 {
+		return 0
+	}
+	fmt.Fprintf(os.Stderr, "%s:\n%s\n", path, strings.Join(problems, "\n"))
+	return 1
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"khanhanh_lang/astjson"
+	"khanhanh_lang/lexer"
+	"khanhanh_lang/parser"
+	"os"
+	"strings"
+)
+
+// lex subcommand , print the tokens of a file ( or stdin ) as a JSON array
+func runLex(args []string) int {
+	flags := flag.NewFlagSet("lex", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	src, err := readInput(flags.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	out, err := json.MarshalIndent(astjson.Tokens(lexer.New(string(src))), "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Println(string(out))
+	return 0
+}
+
+// parse subcommand , print the AST of a file ( or stdin ) as JSON
+func runParse(args []string) int {
+	flags := flag.NewFlagSet("parse", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	src, err := readInput(flags.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	p := parser.New(lexer.New(string(src)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		fmt.Fprintln(os.Stderr, strings.Join(p.Errors(), "\n"))
+		return 1
+	}
+	out, err := astjson.MarshalIndent(program, "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Println(string(out))
+	return 0
+}
+
+// read the single file given as argument , or stdin when there is none
+func readInput(args []string) ([]byte, error) {
+	switch len(args) {
+	case 0:
+		return io.ReadAll(os.Stdin)
+	case 1:
+		return os.ReadFile(args[0])
+	default:
+		return nil, fmt.Errorf("expected at most one file , got %d", len(args))
+	}
+}
@@ -0,0 +1,185 @@
+package evaluator
+
+import (
+	"khanhanh_lang/ast"
+	"khanhanh_lang/object"
+	"khanhanh_lang/types"
+	"strings"
+)
+
+// ARGUMENTS AND PARAMETERS
+// ---------------------------------------------------------------------------------
+// A call give positional arguments , ...array spread an array into positional arguments , then name: value
+// give an argument by the name of its parameter . A parameter missing from the call take its default ,
+// evaluated at the call in the function scope so it can use the parameters before it , and ...rest collect
+// the positional arguments left over into an array
+
+type namedArgument struct {
+	name  string
+	value object.Object
+}
+
+// evaluate the arguments of a call , a single error is returned as the only positional argument like evalExpressions
+func evalArguments(exps []ast.Expression, env *object.Tracker) ([]object.Object, []namedArgument) {
+	args := []object.Object{}
+	var named []namedArgument
+	for _, e := range exps {
+		switch e := e.(type) {
+		case *ast.SpreadExpression:
+			value := Eval(e.Value, env)
+			if isError(value) {
+				return []object.Object{value}, nil
+			}
+			array, ok := value.(*object.Array)
+			if !ok {
+				err := newError("[Error]: Cannot spread %s , expected ARRAY, got=%s", value.Inspect(), value.Type())
+				return []object.Object{atPosition(err, e.Token)}, nil
+			}
+			args = append(args, array.Elements...)
+		case *ast.NamedArgument:
+			value := Eval(e.Value, env)
+			if isError(value) {
+				return []object.Object{value}, nil
+			}
+			named = append(named, namedArgument{name: e.Name.Value, value: value})
+		default:
+			value := Eval(e, env)
+			if isError(value) {
+				return []object.Object{value}, nil
+			}
+			args = append(args, value)
+		}
+	}
+	return args, named
+}
+
+func extendFunctionEnv(fn *object.Function, args []object.Object, named []namedArgument) (*object.Tracker, object.Object) {
+	env := object.NewEnclosedTracker(fn.Env)
+	params, rest := splitRest(fn.Parameters)
+	required := requiredParams(fn)
+	if rest == nil && len(args) > len(params) {
+		return nil, newError("[Error]: %s expect %s, got=%d", signature(fn), arity(required, len(params)), len(args))
+	}
+	if len(named) == 0 && len(args) < required {
+		if rest != nil {
+			return nil, newError("[Error]: %s expect at least %s, got=%d", signature(fn), arity(required, required), len(args))
+		}
+		return nil, newError("[Error]: %s expect %s, got=%d", signature(fn), arity(required, len(params)), len(args))
+	}
+
+	byName := map[string]object.Object{}
+	for _, arg := range named {
+		i := paramIndex(params, arg.name)
+		if i < 0 {
+			return nil, newError("[Error]: %s has no parameter %s", signature(fn), arg.name)
+		}
+		if i < len(args) {
+			return nil, newError("[Error]: %s got argument %s twice", signature(fn), arg.name)
+		}
+		byName[arg.name] = arg.value
+	}
+
+	for i, param := range params {
+		target, def := param, ast.Expression(nil)
+		if d, ok := param.(*ast.DefaultParameter); ok {
+			target, def = d.Target, d.Value
+		}
+		var value object.Object
+		if i < len(args) {
+			value = args[i]
+		} else if ident, ok := target.(*ast.Identifier); ok && byName[ident.Value] != nil {
+			value = byName[ident.Value]
+		} else if def != nil {
+			value = Eval(def, env)
+			if isError(value) {
+				return nil, value
+			}
+		} else {
+			return nil, newError("[Error]: %s missing argument %s", signature(fn), target.String())
+		}
+		if err := checkAnnotation(fn.ParameterType(i), value, "parameter "+target.String()+" of "+signature(fn)); err != nil {
+			return nil, err
+		}
+		if err := bindPattern(target, value, env.Set); err != nil {
+			return nil, err
+		}
+	}
+
+	if rest != nil {
+		extra := []object.Object{}
+		if len(args) > len(params) {
+			extra = append(extra, args[len(params):]...)
+		}
+		array := &object.Array{Elements: extra}
+		what := "parameter " + rest.Value.String() + " of " + signature(fn)
+		if err := checkAnnotation(fn.ParameterType(len(params)), array, what); err != nil {
+			return nil, err
+		}
+		if err := bindPattern(rest.Value, array, env.Set); err != nil {
+			return nil, err
+		}
+	}
+	return env, nil
+}
+
+// the parameters without the last ...rest , and that rest ( nil when the function has none )
+func splitRest(params []ast.Expression) ([]ast.Expression, *ast.SpreadExpression) {
+	if len(params) == 0 {
+		return params, nil
+	}
+	if rest, ok := params[len(params)-1].(*ast.SpreadExpression); ok {
+		return params[:len(params)-1], rest
+	}
+	return params, nil
+}
+
+// number of parameters a call must give , the ones before the first default
+func requiredParams(fn *object.Function) int {
+	params, _ := splitRest(fn.Parameters)
+	for i, param := range params {
+		if _, ok := param.(*ast.DefaultParameter); ok {
+			return i
+		}
+	}
+	return len(params)
+}
+
+// position of the parameter called name , -1 when no parameter is a plain name equal to name
+func paramIndex(params []ast.Expression, name string) int {
+	for i, param := range params {
+		if d, ok := param.(*ast.DefaultParameter); ok {
+			param = d.Target
+		}
+		if ident, ok := param.(*ast.Identifier); ok && ident.Value == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// func(a, b: int = 10, ...rest): int , how a function is shown in the errors of its calls
+func signature(fn *object.Function) string {
+	params := []string{}
+	for i, param := range fn.Parameters {
+		params = append(params, ast.ParameterString(param, fn.ParameterType(i)))
+	}
+	if fn.ReturnType != nil {
+		return "func(" + strings.Join(params, ", ") + "): " + fn.ReturnType.String()
+	}
+	return "func(" + strings.Join(params, ", ") + ")"
+}
+
+// TYPE ANNOTATIONS
+// ---------------------------------------------------------------------------------
+// A value is checked when it cross an annotation : a let , a parameter or the result of a function ,
+// what describe the place in the error , a nil annotation accept everything
+
+func checkAnnotation(annotation *ast.Type, obj object.Object, what string) object.Object {
+	if annotation == nil {
+		return nil
+	}
+	if typ := types.FromAST(annotation); !typ.Matches(obj) {
+		return newError("[Error]: Cannot use %s as %s for %s", types.Of(obj), typ, what)
+	}
+	return nil
+}
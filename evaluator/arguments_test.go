@@ -0,0 +1,83 @@
+package evaluator
+
+import (
+	"testing"
+)
+
+func TestFunctionArguments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		// defaults
+		{`let f = func(a, b = 10) { a + b }; f(1)`, int64(11)},
+		{`let f = func(a, b = 10) { a + b }; f(1, 2)`, int64(3)},
+		{`let f = func(a, b = a * 2) { b }; f(4)`, int64(8)},
+		{`let n = 1; let f = func(a = n) { a }; let n = 2; f()`, int64(2)},
+		{`let f = func(xs = range(3)) { xs }; f()`, "[0, 1, 2]"},
+		{`let f = func([a, b] = [1, 2]) { a + b }; f()`, int64(3)},
+		// rest
+		{`let f = func(a, ...rest) { rest }; f(1, 2, 3)`, "[2, 3]"},
+		{`let f = func(a, ...rest) { rest }; f(1)`, "[]"},
+		{`let f = func(a = 0, ...rest) { [a, rest] }; f()`, "[0, []]"},
+		// spread at the call
+		{`let add = func(a, b, c) { a + b + c }; add(...[1, 2, 3])`, int64(6)},
+		{`let add = func(a, b, c) { a + b + c }; add(1, ...[2], ...[3])`, int64(6)},
+		{`range(...[1, 4])`, "[1, 2, 3]"},
+		// named arguments
+		{`let f = func(a, b = 2, c = 3) { [a, b, c] }; f(1, c: 30)`, "[1, 2, 30]"},
+		{`let f = func(a, b) { a - b }; f(b: 1, a: 10)`, int64(9)},
+		{`struct Point { x, y }; [Point(y: 2, x: 1)]`, "[Point{x: 1, y: 2}]"},
+		{`struct Point { x, y }; [Point(1, y: 2)]`, "[Point{x: 1, y: 2}]"},
+		// arity errors
+		{`let f = func(a, b) { a }; f(1)`, ErrorMesssage("[Error]: func(a, b) expect 2 arguments, got=1")},
+		{`let f = func(a, b = 1) { a }; f()`, ErrorMesssage("[Error]: func(a, b = 1) expect 1 to 2 arguments, got=0")},
+		{`let f = func(a) { a }; f(1, 2)`, ErrorMesssage("[Error]: func(a) expect 1 argument, got=2")},
+		{`let f = func(a, b, ...c) { a }; f(1)`, ErrorMesssage("[Error]: func(a, b, ...c) expect at least 2 arguments, got=1")},
+		{`let f = func(a, b) { a }; f(b: 1)`, ErrorMesssage("[Error]: func(a, b) missing argument a")},
+		{`let f = func(a) { a }; f(1, a: 2)`, ErrorMesssage("[Error]: func(a) got argument a twice")},
+		{`let f = func(a) { a }; f(b: 2)`, ErrorMesssage("[Error]: func(a) has no parameter b")},
+		{`let f = func([a]) { a }; f(a: [1])`, ErrorMesssage("[Error]: func([a]) has no parameter a")},
+		{`let f = func(a = missing) { a }; f()`, ErrorMesssage("[Error]: Identifier not found: missing")},
+		{`range(n: 3)`, ErrorMesssage("[Error]: range does not take named arguments")},
+		{`let f = func(a) { a }; f(...1)`, ErrorMesssage("[Error]: Cannot spread 1 , expected ARRAY, got=INTEGER")},
+		{`struct Point { x, y }; Point(1, x: 2)`, ErrorMesssage("[Error]: Point got field x twice")},
+		{`struct Point { x, y }; Point(z: 2)`, ErrorMesssage("[Error]: Point has no field z")},
+		{`struct Point { x, y }; Point(y: 2)`, ErrorMesssage("[Error]: Point missing field x")},
+		{`[...[1]]`, ErrorMesssage("[Error]: ... is only allowed in a call, a parameter list or an array pattern")},
+		// builtins calling back a function only need its parameters without default
+		{`map([1, 2], func(x, by = 10) { x * by })`, "[10, 20]"},
+		{`map([1, 2], func(x, y) { x })`, ErrorMesssage("[Error]: map call its function with 1 argument, got a function of 2 parameters")},
+	}
+	for _, tt := range tests {
+		testResult(t, tt.input, tt.expected)
+	}
+}
+
+func TestTypeAnnotations(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{`let x: int = 5; x`, int64(5)},
+		{`let x: float = 5; x`, int64(5)},
+		{`let xs: array<int> = [1, 2]; xs`, "[1, 2]"},
+		{`let h: hash<string, any> = {"a": 1, "b": "c"}; h["a"]`, int64(1)},
+		{`struct P { x }; let p: P = P(1); p.x`, int64(1)},
+		{`let f = func(a: int, b: float = 1): float { a + b }; f(2) == 3.0`, true},
+		{`let f = func(a: int, ...rest: array<int>): int { a + rest.len() }; f(1, 2, 3)`, int64(3)},
+		{`let f = func(a: string): bool { return a == "x" }; f("x")`, true},
+		{`let x: int = "a"`, ErrorMesssage("[Error]: Cannot use string as int for x")},
+		{`let x: array<int> = [1, 2.5]`, ErrorMesssage("[Error]: Cannot use array<float> as array<int> for x")},
+		{`let p: P = 1`, ErrorMesssage("[Error]: Cannot use int as P for p")},
+		{`let [a]: array<int> = ["s"]`, ErrorMesssage("[Error]: Cannot use array<string> as array<int> for [a]")},
+		{`let f = func(a: string) { a }; f(1)`, ErrorMesssage("[Error]: Cannot use int as string for parameter a of func(a: string)")},
+		{`let f = func(a, b: int = "s") { a }; f(1)`, ErrorMesssage("[Error]: Cannot use string as int for parameter b of func(a, b: int = \"s\")")},
+		{`let f = func(...r: array<int>) { r }; f(1, "a")`, ErrorMesssage("[Error]: Cannot use array<any> as array<int> for parameter r of func(...r: array<int>)")},
+		{`let f = func(): int { "s" }; f()`, ErrorMesssage("[Error]: Cannot use string as int for the result of func(): int")},
+		{`let f = func(): int { return "s" }; f()`, ErrorMesssage("[Error]: Cannot use string as int for the result of func(): int")},
+	}
+	for _, tt := range tests {
+		testResult(t, tt.input, tt.expected)
+	}
+}
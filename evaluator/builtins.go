@@ -0,0 +1,99 @@
+package evaluator
+
+import (
+	"fmt"
+	"khanhanh_lang/object"
+	"sort"
+)
+
+// BUILTINS
+// ---------------------------------------------------------------------------------
+// Names visible from every script without being declared , looked up after the tracker
+// Each standard module register itself in init
+var builtins = map[string]object.Object{}
+
+// Builtins return the name of every builtin , sorted , used for completion
+func Builtins() []string {
+	names := make([]string, 0, len(builtins)+len(hostBuiltins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	for name := range hostBuiltins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Add a global builtin function
+func register(name string, fn object.BuiltinFunction) {
+	builtins[name] = &object.Builtin{Name: name, Fn: fn}
+}
+
+// Build a module whose members are Go functions , named module.member in error messages
+func newModule(name string, functions map[string]object.BuiltinFunction) *object.Module {
+	module := &object.Module{Name: name, Members: map[string]object.Object{}}
+	for member, fn := range functions {
+		module.Members[member] = &object.Builtin{Name: name + "." + member, Fn: fn}
+	}
+	return module
+}
+
+// ARGUMENTS
+// ---------------------------------------------------------------------------------
+// Check a builtin got between min and len(types) arguments , each of the expected type
+// ANY_OBJ accept every type at its position , NUMBER_OBJ every numeric type and FUNCTION_OBJ builtins as well
+const (
+	ANY_OBJ    object.ObjectType = "ANY"
+	NUMBER_OBJ object.ObjectType = "NUMBER"
+)
+
+func checkArgs(name string, args []object.Object, min int, types ...object.ObjectType) *object.Error {
+	max := len(types)
+	if len(args) < min || len(args) > max {
+		return newError("[Error]: %s expect %s, got=%d", name, arity(min, max), len(args))
+	}
+	for i, arg := range args {
+		if types[i] == ANY_OBJ || (types[i] == NUMBER_OBJ && isNumber(arg)) || (types[i] == object.FUNCTION_OBJ && isCallable(arg)) {
+			continue
+		}
+		if arg.Type() != types[i] {
+			return newError("[Error]: argument %d of %s must be %s, got=%s", i+1, name, types[i], arg.Type())
+		}
+	}
+	return nil
+}
+
+func arity(min, max int) string {
+	plural := func(n int) string {
+		if n == 1 {
+			return "1 argument"
+		}
+		return fmt.Sprintf("%d arguments", n)
+	}
+	if min == max {
+		return plural(max)
+	}
+	return fmt.Sprintf("%d to %s", min, plural(max))
+}
+
+func isCallable(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Function, *object.Builtin, *object.Struct:
+		return true
+	}
+	return false
+}
+
+// Call back a function given to a builtin , nil when the function body is empty
+func callFunction(name string, fn object.Object, args ...object.Object) object.Object {
+	if function, ok := fn.(*object.Function); ok && requiredParams(function) > len(args) {
+		return newError("[Error]: %s call its function with %s, got a function of %d parameters",
+			name, arity(len(args), len(args)), requiredParams(function))
+	}
+	result := applyFunction(fn, args)
+	if result == nil {
+		return NIL
+	}
+	return result
+}
@@ -0,0 +1,247 @@
+package evaluator
+
+import (
+	"khanhanh_lang/object"
+	"sort"
+)
+
+// COLLECTION BUILTINS
+// ---------------------------------------------------------------------------------
+// Loop natively over an array and call back the given function for each element ,
+// so a script transform its data without writing recursive functions
+
+func init() {
+	register("map", builtinMap)
+	register("filter", builtinFilter)
+	register("reduce", builtinReduce)
+	register("each", builtinEach)
+	register("sort", builtinSort)
+	register("sort_by", builtinSortBy)
+	register("any", builtinAny)
+	register("all", builtinAll)
+	register("zip", builtinZip)
+	register("range", builtinRange)
+	register("enumerate", builtinEnumerate)
+}
+
+func arrayArg(args []object.Object, i int) []object.Object {
+	return args[i].(*object.Array).Elements
+}
+
+// map(array, fn) is the array of fn(element)
+func builtinMap(args ...object.Object) object.Object {
+	if err := checkArgs("map", args, 2, object.ARRAY_OBJ, object.FUNCTION_OBJ); err != nil {
+		return err
+	}
+	elements := arrayArg(args, 0)
+	result := make([]object.Object, len(elements))
+	for i, el := range elements {
+		value := callFunction("map", args[1], el)
+		if isError(value) {
+			return value
+		}
+		result[i] = value
+	}
+	return &object.Array{Elements: result}
+}
+
+// filter(array, fn) keep the elements for which fn is truthy
+func builtinFilter(args ...object.Object) object.Object {
+	if err := checkArgs("filter", args, 2, object.ARRAY_OBJ, object.FUNCTION_OBJ); err != nil {
+		return err
+	}
+	result := []object.Object{}
+	for _, el := range arrayArg(args, 0) {
+		keep := callFunction("filter", args[1], el)
+		if isError(keep) {
+			return keep
+		}
+		if isTruthy(keep) {
+			result = append(result, el)
+		}
+	}
+	return &object.Array{Elements: result}
+}
+
+// reduce(array, fn, initial) fold the array with fn(accumulator, element) ,
+// without initial the first element start the accumulator
+func builtinReduce(args ...object.Object) object.Object {
+	if err := checkArgs("reduce", args, 2, object.ARRAY_OBJ, object.FUNCTION_OBJ, ANY_OBJ); err != nil {
+		return err
+	}
+	elements := arrayArg(args, 0)
+	var acc object.Object
+	if len(args) == 3 {
+		acc = args[2]
+	} else {
+		if len(elements) == 0 {
+			return newError("[Error]: reduce of an empty array without initial value")
+		}
+		acc, elements = elements[0], elements[1:]
+	}
+	for _, el := range elements {
+		acc = callFunction("reduce", args[1], acc, el)
+		if isError(acc) {
+			return acc
+		}
+	}
+	return acc
+}
+
+// each(array, fn) call fn for every element , only for its effect
+func builtinEach(args ...object.Object) object.Object {
+	if err := checkArgs("each", args, 2, object.ARRAY_OBJ, object.FUNCTION_OBJ); err != nil {
+		return err
+	}
+	for _, el := range arrayArg(args, 0) {
+		if result := callFunction("each", args[1], el); isError(result) {
+			return result
+		}
+	}
+	return NIL
+}
+
+// sort_by(array, fn) is a sorted copy of array , ordered by the key fn(element)
+// Keys must be all numbers or all strings , elements with equal keys keep their order
+func builtinSortBy(args ...object.Object) object.Object {
+	if err := checkArgs("sort_by", args, 2, object.ARRAY_OBJ, object.FUNCTION_OBJ); err != nil {
+		return err
+	}
+	elements := arrayArg(args, 0)
+	keys := make([]object.Object, len(elements))
+	for i, el := range elements {
+		key := callFunction("sort_by", args[1], el)
+		if isError(key) {
+			return key
+		}
+		if !isNumber(key) && key.Type() != object.STRING_OBJ {
+			return newError("[Error]: sort_by key must be NUMBER or STRING, got=%s", key.Type())
+		}
+		if i > 0 && isNumber(key) != isNumber(keys[0]) {
+			return newError("[Error]: sort_by keys must have the same type, got=%s and %s", keys[0].Type(), key.Type())
+		}
+		keys[i] = key
+	}
+
+	order := make([]int, len(elements))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		left, right := keys[order[a]], keys[order[b]]
+		if isNumber(left) {
+			return toFloat(left) < toFloat(right)
+		}
+		return left.(*object.String).Value < right.(*object.String).Value
+	})
+	result := make([]object.Object, len(elements))
+	for i, index := range order {
+		result[i] = elements[index]
+	}
+	return &object.Array{Elements: result}
+}
+
+// any(array, fn) is true when fn is truthy for one element , it stop at the first one
+func builtinAny(args ...object.Object) object.Object {
+	return quantify("any", args, true)
+}
+
+// all(array, fn) is true when fn is truthy for every element , it stop at the first falsy one
+func builtinAll(args ...object.Object) object.Object {
+	return quantify("all", args, false)
+}
+
+func quantify(name string, args []object.Object, stopOn bool) object.Object {
+	if err := checkArgs(name, args, 2, object.ARRAY_OBJ, object.FUNCTION_OBJ); err != nil {
+		return err
+	}
+	for _, el := range arrayArg(args, 0) {
+		result := callFunction(name, args[1], el)
+		if isError(result) {
+			return result
+		}
+		if isTruthy(result) == stopOn {
+			return nativeBool(stopOn)
+		}
+	}
+	return nativeBool(!stopOn)
+}
+
+// zip(a, b, ...) pair the elements at the same index , as long as the shortest array
+func builtinZip(args ...object.Object) object.Object {
+	if len(args) < 2 {
+		return newError("[Error]: zip expect at least 2 arrays, got=%d", len(args))
+	}
+	length := -1
+	for i, arg := range args {
+		array, ok := arg.(*object.Array)
+		if !ok {
+			return newError("[Error]: argument %d of zip must be ARRAY, got=%s", i+1, arg.Type())
+		}
+		if length < 0 || len(array.Elements) < length {
+			length = len(array.Elements)
+		}
+	}
+	result := make([]object.Object, length)
+	for i := range result {
+		tuple := make([]object.Object, len(args))
+		for j, arg := range args {
+			tuple[j] = arg.(*object.Array).Elements[i]
+		}
+		result[i] = &object.Array{Elements: tuple}
+	}
+	return &object.Array{Elements: result}
+}
+
+// range(end) , range(start, end) and range(start, end, step) , end is excluded
+func builtinRange(args ...object.Object) object.Object {
+	if err := checkArgs("range", args, 1, object.INTEGER_OBJ, object.INTEGER_OBJ, object.INTEGER_OBJ); err != nil {
+		return err
+	}
+	start, end, step := int64(0), intArg(args, 0), int64(1)
+	if len(args) >= 2 {
+		start, end = intArg(args, 0), intArg(args, 1)
+	}
+	if len(args) == 3 {
+		step = intArg(args, 2)
+	}
+	if step == 0 {
+		return newError("[Error]: range step must not be 0")
+	}
+	// the number of elements , the differences are taken as uint64 so they cannot overflow
+	var span, stride uint64
+	switch {
+	case step > 0 && start < end:
+		span, stride = uint64(end)-uint64(start), uint64(step)
+	case step < 0 && start > end:
+		span, stride = uint64(start)-uint64(end), -uint64(step)
+	}
+	count := uint64(0)
+	if span > 0 {
+		count = (span-1)/stride + 1
+	}
+	if count > maxRangeLength {
+		return newError("[Error]: range too long: %d elements , the limit is %d", count, maxRangeLength)
+	}
+	result := make([]object.Object, count)
+	for k, i := 0, start; k < len(result); k, i = k+1, i+step {
+		result[k] = &object.Integer{Value: i}
+	}
+	return &object.Array{Elements: result}
+}
+
+// the most elements a range build
+const maxRangeLength = 1 << 24
+
+// enumerate(array) is the array of [index, element]
+func builtinEnumerate(args ...object.Object) object.Object {
+	if err := checkArgs("enumerate", args, 1, object.ARRAY_OBJ); err != nil {
+		return err
+	}
+	elements := arrayArg(args, 0)
+	result := make([]object.Object, len(elements))
+	for i, el := range elements {
+		result[i] = &object.Array{Elements: []object.Object{&object.Integer{Value: int64(i)}, el}}
+	}
+	return &object.Array{Elements: result}
+}
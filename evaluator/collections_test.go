@@ -0,0 +1,100 @@
+package evaluator
+
+import (
+	"khanhanh_lang/object"
+	"testing"
+)
+
+func TestCollections(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"map([1, 2, 3], func(x) { x * 2 })", "[2, 4, 6]"},
+		{"map([], func(x) { x })", "[]"},
+		{`map(["a", "b"], strings.upper)`, `["A", "B"]`},
+		{"let n = 10; map([1, 2], func(x) { x + n })", "[11, 12]"},
+		{"filter(range(10), func(x) { x / 2 * 2 == x })", "[0, 2, 4, 6, 8]"},
+		{"reduce([1, 2, 3, 4], func(acc, x) { acc + x })", "10"},
+		{"reduce([], func(acc, x) { acc + x }, 0)", "0"},
+		{`reduce(["a", "b"], func(acc, x) { acc + x }, ">")`, ">ab"},
+		{"each([1, 2], func(x) { x })", "nil"},
+		{`sort_by(["ccc", "a", "bb"], func(s) { strings.chars(s)[0] })`, `["a", "bb", "ccc"]`},
+		{"sort_by([3, 1, 2.5], func(x) { x })", "[1, 2.5, 3]"},
+		{"sort_by([[1, 9], [0, 5], [1, 2]], func(p) { p[0] })", "[[0, 5], [1, 9], [1, 2]]"},
+		{"any([1, 2, 3], func(x) { x > 2 })", "true"},
+		{"any([], func(x) { true })", "false"},
+		{"all([1, 2, 3], func(x) { x > 0 })", "true"},
+		{"all([1, 2, 3], func(x) { x > 1 })", "false"},
+		{"all([], func(x) { false })", "true"},
+		{`zip([1, 2, 3], ["a", "b"])`, `[[1, "a"], [2, "b"]]`},
+		{"zip([1], [2], [3])", "[[1, 2, 3]]"},
+		{"range(3)", "[0, 1, 2]"},
+		{"range(2, 5)", "[2, 3, 4]"},
+		{"range(5, 0, -2)", "[5, 3, 1]"},
+		{"range(0)", "[]"},
+		{"range(5, 0)", "[]"},
+		{"range(0, 5, -1)", "[]"},
+		{"range(0, 10, 3)", "[0, 3, 6, 9]"},
+		{"range(-9223372036854775807, -9223372036854775807 - 1, -5)", "[-9223372036854775807]"},
+		{"range(9223372036854775806, 9223372036854775807, 9223372036854775807)", "[9223372036854775806]"},
+		{"range(-9223372036854775807 - 1, 9223372036854775807, 9223372036854775807)", "[-9223372036854775808, -1, 9223372036854775806]"},
+		{`enumerate(["a", "b"])`, `[[0, "a"], [1, "b"]]`},
+		{"map(enumerate([5, 6]), func(p) { p[0] * p[1] })", "[0, 6]"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		if evaluated == nil {
+			t.Errorf("%q evaluated to nothing", test.input)
+			continue
+		}
+		if evaluated.Type() == object.ERROR_OBJ {
+			t.Errorf("%q unexpected error: %s", test.input, evaluated.Inspect())
+			continue
+		}
+		if got := evaluated.Inspect(); got != test.expected {
+			t.Errorf("%q wrong result. expected=%s , got=%s", test.input, test.expected, got)
+		}
+	}
+}
+
+// the callback is not called again once the answer is known
+func TestCollectionsShortCircuit(t *testing.T) {
+	input := `any([1, 2, 3], func(x) { if (x == 2) { true } else { if (x == 3) { 1 + true } else { false } } })`
+	testTypeObject(t, testEval(input), true)
+	input = `all([1, 2, 3], func(x) { if (x == 2) { false } else { if (x == 3) { 1 + true } else { true } } })`
+	testTypeObject(t, testEval(input), false)
+}
+
+func TestCollectionsErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"map(1, func(x) { x })", "[Error]: argument 1 of map must be ARRAY, got=INTEGER"},
+		{"map([1], 2)", "[Error]: argument 2 of map must be FUNCTION, got=INTEGER"},
+		{"map([1], func(x) { x + true })", "[Error]: Mismatch INTEGER + BOOLEAN"},
+		{"map([1], func(a, b) { a })", "[Error]: map call its function with 1 argument, got a function of 2 parameters"},
+		{"reduce([], func(a, b) { a })", "[Error]: reduce of an empty array without initial value"},
+		{"sort_by([1, 2], func(x) { true })", "[Error]: sort_by key must be NUMBER or STRING, got=BOOLEAN"},
+		{`sort_by([1, "a"], func(x) { x })`, "[Error]: sort_by keys must have the same type, got=INTEGER and STRING"},
+		{"zip([1])", "[Error]: zip expect at least 2 arrays, got=1"},
+		{"zip([1], 2)", "[Error]: argument 2 of zip must be ARRAY, got=INTEGER"},
+		{"range(1, 2, 0)", "[Error]: range step must not be 0"},
+		{"range(-9223372036854775807 - 1, 9223372036854775807)", "[Error]: range too long: 18446744073709551615 elements , the limit is 16777216"},
+		{"range(9223372036854775807, -9223372036854775807 - 1, -2)", "[Error]: range too long: 9223372036854775808 elements , the limit is 16777216"},
+		{"range()", "[Error]: range expect 1 to 3 arguments, got=0"},
+	}
+
+	for _, test := range tests {
+		err, ok := testEval(test.input).(*object.Error)
+		if !ok {
+			t.Errorf("%q should evaluate to an error", test.input)
+			continue
+		}
+		if err.Message != test.expected {
+			t.Errorf("%q wrong error. expected=%q , got=%q", test.input, test.expected, err.Message)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package evaluator
+
+import (
+	"khanhanh_lang/object"
+	"math"
+	"sort"
+	"strings"
+)
+
+// EQUALITY AND ORDERING
+// ---------------------------------------------------------------------------------
+// == compare what the values hold : two arrays are equal when their elements are , two hashes when they have the
+// same keys with equal values whatever their order , two instances when they come from the same struct with
+// equal fields. Functions , builtins , modules and structs are only equal to themselves. An integer and a float
+// are compared exactly , not through a float64 , and NaN is equal to nothing , not even to itself.
+// Sorting use a total ordering : the numbers first , NaN after every other number , then the strings , then the
+// arrays , element by element. Booleans , nil , hashes and the other values have no order , comparing or sorting
+// them is an error.
+// A collection may contain itself , a pair of values met again while it is being compared is taken as equal ,
+// so comparing two cyclic values always end
+
+// two values being compared , with the pairs in progress it detect the cycles
+type comparison struct {
+	left, right object.Object
+}
+
+func equal(left, right object.Object) bool {
+	return deepEqual(left, right, map[comparison]bool{})
+}
+
+func deepEqual(left, right object.Object, inProgress map[comparison]bool) bool {
+	switch left.(type) {
+	case *object.Integer, *object.Float:
+		order, ok := compareNumbers(left, right)
+		return ok && order == 0
+	}
+	switch l := left.(type) {
+	case *object.String:
+		r, ok := right.(*object.String)
+		return ok && l.Value == r.Value
+	case *object.Boolean:
+		r, ok := right.(*object.Boolean)
+		return ok && l.Value == r.Value
+	case *object.Nil:
+		_, ok := right.(*object.Nil)
+		return ok
+	case *object.Array:
+		r, ok := right.(*object.Array)
+		if !ok || len(l.Elements) != len(r.Elements) {
+			return false
+		}
+		pair := comparison{left, right}
+		if inProgress[pair] {
+			return true
+		}
+		inProgress[pair] = true
+		defer delete(inProgress, pair)
+		for i := range l.Elements {
+			if !deepEqual(l.Elements[i], r.Elements[i], inProgress) {
+				return false
+			}
+		}
+		return true
+	case *object.Hash:
+		r, ok := right.(*object.Hash)
+		if !ok || l.Len() != r.Len() {
+			return false
+		}
+		pair := comparison{left, right}
+		if inProgress[pair] {
+			return true
+		}
+		inProgress[pair] = true
+		defer delete(inProgress, pair)
+		for _, p := range l.Pairs() {
+			value, ok := r.Get(p.Key.(object.Hashable))
+			if !ok || !deepEqual(p.Value, value, inProgress) {
+				return false
+			}
+		}
+		return true
+	case *object.Instance:
+		r, ok := right.(*object.Instance)
+		if !ok || l.Struct != r.Struct {
+			return false
+		}
+		pair := comparison{left, right}
+		if inProgress[pair] {
+			return true
+		}
+		inProgress[pair] = true
+		defer delete(inProgress, pair)
+		for i := range l.Values {
+			if !deepEqual(l.Values[i], r.Values[i], inProgress) {
+				return false
+			}
+		}
+		return true
+	}
+	return left == right
+}
+
+// compareNumbers order two numbers exactly , ok is false when right is not a number or when one of them is NaN
+func compareNumbers(left, right object.Object) (order int, ok bool) {
+	switch l := left.(type) {
+	case *object.Integer:
+		switch r := right.(type) {
+		case *object.Integer:
+			return compareInts(l.Value, r.Value), true
+		case *object.Float:
+			if math.IsNaN(r.Value) {
+				return 0, false
+			}
+			return compareIntFloat(l.Value, r.Value), true
+		}
+	case *object.Float:
+		if math.IsNaN(l.Value) {
+			return 0, false
+		}
+		switch r := right.(type) {
+		case *object.Integer:
+			return -compareIntFloat(r.Value, l.Value), true
+		case *object.Float:
+			switch {
+			case math.IsNaN(r.Value):
+				return 0, false
+			case l.Value < r.Value:
+				return -1, true
+			case l.Value > r.Value:
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+	return 0, false
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// i against a float f that is not NaN , the integral part of f is compared as an integer when it fit one
+func compareIntFloat(i int64, f float64) int {
+	switch {
+	case f >= math.MaxInt64: // 2^63 , above every integer
+		return -1
+	case f < math.MinInt64:
+		return 1
+	}
+	whole := math.Trunc(f)
+	if order := compareInts(i, int64(whole)); order != 0 {
+		return order
+	}
+	// the same integral part , the fraction of f decide
+	switch fraction := f - whole; {
+	case fraction > 0:
+		return -1
+	case fraction < 0:
+		return 1
+	}
+	return 0
+}
+
+// position of the kind of obj in the total ordering , -1 when obj cannot be ordered
+func orderRank(obj object.Object) int {
+	switch obj.(type) {
+	case *object.Integer, *object.Float:
+		return 0
+	case *object.String:
+		return 1
+	case *object.Array:
+		return 2
+	}
+	return -1
+}
+
+// compare return a negative number , 0 or a positive number when left is before , equal to or after right ,
+// or an error when one of them , or one of the elements compared , cannot be ordered
+func compare(left, right object.Object) (int, *object.Error) {
+	return deepCompare(left, right, map[comparison]bool{})
+}
+
+func deepCompare(left, right object.Object, inProgress map[comparison]bool) (int, *object.Error) {
+	leftRank, rightRank := orderRank(left), orderRank(right)
+	switch {
+	case leftRank < 0 || rightRank < 0:
+		return 0, newError("[Error]: Cannot order %s and %s", left.Type(), right.Type())
+	case leftRank != rightRank:
+		return leftRank - rightRank, nil
+	}
+	switch l := left.(type) {
+	case *object.String:
+		return strings.Compare(l.Value, right.(*object.String).Value), nil
+	case *object.Array:
+		r := right.(*object.Array)
+		pair := comparison{left, right}
+		if inProgress[pair] {
+			return 0, nil
+		}
+		inProgress[pair] = true
+		defer delete(inProgress, pair)
+		for i := 0; i < len(l.Elements) && i < len(r.Elements); i++ {
+			order, err := deepCompare(l.Elements[i], r.Elements[i], inProgress)
+			if err != nil || order != 0 {
+				return order, err
+			}
+		}
+		// a prefix come first
+		return len(l.Elements) - len(r.Elements), nil
+	}
+	// numbers , NaN come after the others , two NaN are equal there so sort keep their order
+	if order, ok := compareNumbers(left, right); ok {
+		return order, nil
+	}
+	switch leftNaN, rightNaN := isNaN(left), isNaN(right); {
+	case leftNaN && rightNaN:
+		return 0, nil
+	case leftNaN:
+		return 1, nil
+	}
+	return -1, nil
+}
+
+func isNaN(obj object.Object) bool {
+	f, ok := obj.(*object.Float)
+	return ok && math.IsNaN(f.Value)
+}
+
+func isComparison(operator string) bool {
+	switch operator {
+	case "<", ">", "<=", ">=":
+		return true
+	}
+	return false
+}
+
+// < > <= >= on two strings or two arrays
+func evalComparison(operator string, left, right object.Object) object.Object {
+	order, err := compare(left, right)
+	if err != nil {
+		return err
+	}
+	return nativeBool(orderHolds(operator, order))
+}
+
+// whether left operator right hold for left and right in that order
+func orderHolds(operator string, order int) bool {
+	switch operator {
+	case "<":
+		return order < 0
+	case ">":
+		return order > 0
+	case "<=":
+		return order <= 0
+	default:
+		return order >= 0
+	}
+}
+
+// sort(array) is a sorted copy of array in the total ordering , equal elements keep their order
+func builtinSort(args ...object.Object) object.Object {
+	if err := checkArgs("sort", args, 1, object.ARRAY_OBJ); err != nil {
+		return err
+	}
+	result := make([]object.Object, len(arrayArg(args, 0)))
+	copy(result, arrayArg(args, 0))
+	for _, el := range result {
+		if orderRank(el) < 0 {
+			return newError("[Error]: sort cannot order %s", el.Type())
+		}
+	}
+	var failed *object.Error
+	sort.SliceStable(result, func(a, b int) bool {
+		order, err := compare(result[a], result[b])
+		if err != nil && failed == nil {
+			failed = err
+		}
+		return order < 0
+	})
+	if failed != nil {
+		return failed
+	}
+	return &object.Array{Elements: result}
+}
@@ -0,0 +1,168 @@
+package evaluator
+
+import (
+	"khanhanh_lang/object"
+	"testing"
+)
+
+func TestStructuralEquality(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{`[1, 2] == [1, 2]`, true},
+		{`[1, 2] == [2, 1]`, false},
+		{`[1, 2] == [1, 2, 3]`, false},
+		{`[1, [2, "a"]] == [1, [2, "a"]]`, true},
+		{`[1, [2, "a"]] != [1, [2, "b"]]`, true},
+		{`[1, 2.0] == [1.0, 2]`, true},
+		{`[] == []`, true},
+		{`[1] == ["1"]`, false},
+		{`{"a": 1, "b": [2]} == {"b": [2], "a": 1}`, true},
+		{`{"a": 1} == {"a": 1, "b": 2}`, false},
+		{`{"a": 1} == {"b": 1}`, false},
+		{`{1: "x"} == {1: "y"}`, false},
+		{`{} == []`, false},
+		{`struct P { x }; [P(1), {"p": P([2])}] == [P(1), {"p": P([2])}]`, true},
+		{`let f = func() {}; [f] == [f]`, true},
+		{`[func() {}] == [func() {}]`, false},
+		{`!(1 == 1)`, false},
+		// NaN is equal to nothing , the same value or an array holding it either
+		{`let n = 0.0 / 0.0; n == n`, false},
+		{`let n = 0.0 / 0.0; n != n`, true},
+		{`let n = 0.0 / 0.0; [n] == [n]`, false},
+		{`let n = 0.0 / 0.0; let xs = [n]; xs == xs`, false},
+		// an integer and a float are compared exactly
+		{`9007199254740993 == 9007199254740992.0`, false},
+		{`9007199254740992 == 9007199254740992.0`, true},
+		{`9223372036854775807 == 9223372036854775808.0`, false},
+		{`-9223372036854775807 - 1 == -9223372036854775808.0`, true},
+		{`2 == 2.5`, false},
+		{`-0.0 == 0`, true},
+	}
+	for _, tt := range tests {
+		testResult(t, tt.input, tt.expected)
+	}
+}
+
+func TestOrdering(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{`"a" < "b"`, true},
+		{`"b" < "a"`, false},
+		{`"ab" > "a"`, true},
+		{`"B" < "a"`, true},
+		{`"a" <= "a"`, true},
+		{`"" >= "a"`, false},
+		{`[1, 2] < [1, 3]`, true},
+		{`[1, 2] < [1]`, false},
+		{`[1] < [1, 0]`, true},
+		{`[] <= []`, true},
+		{`[2] > [1, 9]`, true},
+		{`[1, "a"] < [1, "b"]`, true},
+		{`[1] < ["a"]`, true},
+		{`[[1, 2]] > [[1]]`, true},
+		{`"a" < 1`, ErrorMesssage("[Error]: Mismatch STRING < INTEGER")},
+		{`[true] < [false]`, ErrorMesssage("[Error]: Cannot order BOOLEAN and BOOLEAN")},
+		{`{} < {}`, ErrorMesssage("[Error]: Unknown operator: HASH < HASH")},
+		{`true > false`, ErrorMesssage("[Error]: Unknown operator: BOOLEAN > BOOLEAN")},
+		{`let n = if (false) { 1 }; n < n`, ErrorMesssage("[Error]: Unknown operator: NIL < NIL")},
+		{`let n = if (false) { 1 }; [n] < [n]`, ErrorMesssage("[Error]: Cannot order NIL and NIL")},
+		{`[{}] < [{}]`, ErrorMesssage("[Error]: Cannot order HASH and HASH")},
+		{`9007199254740993 > 9007199254740992.0`, true},
+		{`9007199254740992.5 > 9007199254740992`, false},
+		{`2.5 > 2`, true},
+		{`-2.5 < -2`, true},
+		{`9223372036854775807 < 9223372036854775808.0`, true},
+		{`let n = 0.0 / 0.0; [n < 1, n > 1, n >= n, 1 <= n]`, "[false, false, false, false]"},
+		{`let n = 0.0 / 0.0; [[1] < [n], [n] < [1], [n] <= [n]]`, "[true, false, true]"},
+	}
+	for _, tt := range tests {
+		testResult(t, tt.input, tt.expected)
+	}
+}
+
+func TestSort(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`sort([3, 1, 2.5])`, "[1, 2.5, 3]"},
+		{`sort(["b", "a", "B"])`, `["B", "a", "b"]`},
+		{`sort([[1, 2], "z", 3, [1], "a", -1.5])`, `[-1.5, 3, "a", "z", [1], [1, 2]]`},
+		{`sort([[2, "b"], [2, "a"], [1, "c"]])`, `[[1, "c"], [2, "a"], [2, "b"]]`},
+		{`sort([1, 1.0, 1])`, "[1, 1.0, 1]"},
+		{`[3, 2, 1].sort()`, "[1, 2, 3]"},
+		{`let xs = [2, 1]; xs.sort(); xs`, "[2, 1]"},
+		{`sort([])`, "[]"},
+		{`let n = 0.0 / 0.0; sort([3, n, 1, 2, n, 0])`, "[0, 1, 2, 3, NaN, NaN]"},
+		{`let n = 0.0 / 0.0; sort(["a", n, -1.5])`, `[-1.5, NaN, "a"]`},
+		{`sort([9007199254740993, 9007199254740992.0])`, "[9.007199254740992e+15, 9007199254740993]"},
+		{`sort([1, true])`, "[Error]: sort cannot order BOOLEAN"},
+		{`let n = if (false) { 1 }; sort([n, 1])`, "[Error]: sort cannot order NIL"},
+		{`sort([{}])`, "[Error]: sort cannot order HASH"},
+		{`sort([[1], [{}]])`, "[Error]: Cannot order HASH and INTEGER"},
+		{`sort(1)`, "[Error]: argument 1 of sort must be ARRAY, got=INTEGER"},
+	}
+	for _, tt := range tests {
+		if got := testEval(tt.input).Inspect(); got != tt.expected {
+			t.Errorf("%s: expected %s , got %s", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestCyclicComparison(t *testing.T) {
+	// a = [1, a] and b = [1, b] hold the same infinite content
+	a := &object.Array{}
+	a.Elements = []object.Object{&object.Integer{Value: 1}, a}
+	b := &object.Array{}
+	b.Elements = []object.Object{&object.Integer{Value: 1}, b}
+	c := &object.Array{}
+	c.Elements = []object.Object{&object.Integer{Value: 2}, c}
+
+	if !equal(a, b) {
+		t.Errorf("expected %s == %s", "a", "b")
+	}
+	if equal(a, c) {
+		t.Errorf("expected %s != %s", "a", "c")
+	}
+	if order, err := compare(a, b); err != nil || order != 0 {
+		t.Errorf("compare(a, b) expected 0 , got %d %v", order, err)
+	}
+	if order, err := compare(a, c); err != nil || order >= 0 {
+		t.Errorf("compare(a, c) expected a negative number , got %d %v", order, err)
+	}
+
+	h := object.NewHash()
+	h.Set(&object.String{Value: "self"}, h)
+	g := object.NewHash()
+	g.Set(&object.String{Value: "self"}, g)
+	if !equal(h, g) {
+		t.Errorf("expected the cyclic hashes to be equal")
+	}
+}
+
+func TestTruthiness(t *testing.T) {
+	// booleans and nil that are not the singletons
+	tests := []struct {
+		obj    object.Object
+		truthy bool
+	}{
+		{&object.Boolean{Value: true}, true},
+		{&object.Boolean{Value: false}, false},
+		{&object.Nil{}, false},
+		{&object.Integer{Value: 0}, true},
+		{&object.String{Value: ""}, true},
+		{&object.Array{}, true},
+	}
+	for _, tt := range tests {
+		if isTruthy(tt.obj) != tt.truthy {
+			t.Errorf("isTruthy(%s) expected %t", tt.obj.Inspect(), tt.truthy)
+		}
+		if evalBangPrefix(tt.obj) != nativeBool(!tt.truthy) {
+			t.Errorf("!%s expected %t", tt.obj.Inspect(), !tt.truthy)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package evaluator
+
+import (
+	"khanhanh_lang/lexer"
+	"khanhanh_lang/object"
+	"khanhanh_lang/parser"
+	"testing"
+)
+
+func TestConst(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{`const LIMIT = 10; LIMIT * 2`, int64(20)},
+		{`const [A, B] = [1, 2]; A + B`, int64(3)},
+		{`const {name} = {"name": "cfg"}; name`, "cfg"},
+		{`const A = 1; let f = func() { let A = 2; A }; [f(), A]`, "[2, 1]"},
+		{`const A = 1; let f = func(A) { A }; f(5)`, int64(5)},
+	}
+	for _, tt := range tests {
+		testResult(t, tt.input, tt.expected)
+	}
+}
+
+// the parser reject a redefinition in one program , the tracker the ones spread over several programs
+// evaluated in the same tracker , as the inputs of the REPL
+func TestConstAcrossPrograms(t *testing.T) {
+	tests := []struct {
+		first  string
+		second string
+		expect string
+	}{
+		{"const A = 1", "let A = 2", "[Error]: Cannot redefine const A"},
+		{"const A = 1", "const A = 2", "[Error]: Cannot redefine const A"},
+		{"const [A, B] = [1, 2]", "let [C, B] = [3, 4]", "[Error]: Cannot redefine const B"},
+		{"const P = 1", "struct P { x }", "[Error]: Cannot redefine const P"},
+	}
+	for _, tt := range tests {
+		tracker := object.NewTracker()
+		evalIn(t, tt.first, tracker)
+		result := evalIn(t, tt.second, tracker)
+		err, ok := result.(*object.Error)
+		if !ok {
+			t.Errorf("%s then %s should fail. got=%v", tt.first, tt.second, result)
+			continue
+		}
+		if err.Message != tt.expect {
+			t.Errorf("wrong error. expect=%q got=%q", tt.expect, err.Message)
+		}
+		if err.Pos.Line == 0 {
+			t.Errorf("%s: the error should have a position", tt.second)
+		}
+	}
+
+	// a failed redefinition keep the const value
+	tracker := object.NewTracker()
+	evalIn(t, "const A = 1", tracker)
+	evalIn(t, "let A = 2", tracker)
+	testTypeObject(t, evalIn(t, "A", tracker), int64(1))
+}
+
+func evalIn(t *testing.T, input string, tracker *object.Tracker) object.Object {
+	par := parser.New(lexer.New(input))
+	program := par.ParseProgram()
+	if len(par.Errors()) != 0 {
+		t.Fatalf("%s: parser errors %v", input, par.Errors())
+	}
+	return Eval(program, tracker)
+}
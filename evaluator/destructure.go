@@ -0,0 +1,101 @@
+package evaluator
+
+import (
+	"khanhanh_lang/ast"
+	"khanhanh_lang/object"
+	"sort"
+)
+
+// DESTRUCTURING
+// ---------------------------------------------------------------------------------
+// let and the function parameters bind a value to a name or to a pattern . An array pattern need an array
+// with exactly as many elements , or at least as many when it end with ...rest which get the others .
+// A hash pattern need a hash with a string key for each of its names , or an instance with such fields .
+// A value of the wrong shape is an error , nothing is bound then
+
+// bind value to target with bind ( the Set or SetConst of a tracker ) , return an error when the value does not
+// have the shape of the pattern or when bind refuse a name
+func bindPattern(target ast.Expression, value object.Object, bind func(string, object.Object) object.Object) object.Object {
+	bindings := map[string]object.Object{}
+	if err := destructure(target, value, bindings); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(bindings))
+	for name := range bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if result := bind(name, bindings[name]); isError(result) {
+			return result
+		}
+	}
+	return nil
+}
+
+func destructure(target ast.Expression, value object.Object, bindings map[string]object.Object) object.Object {
+	switch target := target.(type) {
+	case *ast.Identifier:
+		if target.Value != "_" {
+			bindings[target.Value] = value
+		}
+		return nil
+	case *ast.ArrayPattern:
+		return atPosition(destructureArray(target, value, bindings), target.Token)
+	case *ast.HashPattern:
+		return atPosition(destructureHash(target, value, bindings), target.Token)
+	}
+	return newError("[Error]: Cannot bind to %s", target.String())
+}
+
+func destructureArray(pattern *ast.ArrayPattern, value object.Object, bindings map[string]object.Object) object.Object {
+	array, ok := value.(*object.Array)
+	if !ok {
+		return newError("[Error]: Cannot destructure %s into %s , expected ARRAY, got=%s",
+			value.Inspect(), pattern.String(), value.Type())
+	}
+	count := len(pattern.Elements)
+	if pattern.Rest == nil && len(array.Elements) != count {
+		return newError("[Error]: Cannot destructure %s into %s , expected %d elements, got=%d",
+			array.Inspect(), pattern.String(), count, len(array.Elements))
+	}
+	if pattern.Rest != nil && len(array.Elements) < count {
+		return newError("[Error]: Cannot destructure %s into %s , expected at least %d elements, got=%d",
+			array.Inspect(), pattern.String(), count, len(array.Elements))
+	}
+	for i, el := range pattern.Elements {
+		if err := destructure(el, array.Elements[i], bindings); err != nil {
+			return err
+		}
+	}
+	if pattern.Rest != nil {
+		rest := make([]object.Object, len(array.Elements)-count)
+		copy(rest, array.Elements[count:])
+		return destructure(pattern.Rest, &object.Array{Elements: rest}, bindings)
+	}
+	return nil
+}
+
+func destructureHash(pattern *ast.HashPattern, value object.Object, bindings map[string]object.Object) object.Object {
+	var get func(name string) (object.Object, bool)
+	switch value := value.(type) {
+	case *object.Hash:
+		get = func(name string) (object.Object, bool) { return value.Get(&object.String{Value: name}) }
+	case *object.Instance:
+		get = value.Get
+	default:
+		return newError("[Error]: Cannot destructure %s into %s , expected HASH or INSTANCE, got=%s",
+			value.Inspect(), pattern.String(), value.Type())
+	}
+	for i, key := range pattern.Keys {
+		found, ok := get(key.Value)
+		if !ok {
+			return newError("[Error]: Cannot destructure %s into %s , %s has no key %s",
+				value.Inspect(), pattern.String(), value.Type(), key.Value)
+		}
+		if err := destructure(pattern.Values[i], found, bindings); err != nil {
+			return err
+		}
+	}
+	return nil
+}
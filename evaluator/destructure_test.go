@@ -0,0 +1,53 @@
+package evaluator
+
+import (
+	"testing"
+)
+
+func TestDestructuringLet(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{`let [a, b] = [1, 2]; a + b`, int64(3)},
+		{`let [a, b, ...rest] = [1, 2, 3, 4]; rest`, "[3, 4]"},
+		{`let [a, ...rest] = [1]; rest`, "[]"},
+		{`let [_, second, _] = [1, 2, 3]; second`, int64(2)},
+		{`let [a, [b, c]] = [1, [2, 3]]; a + b + c`, int64(6)},
+		{`let {name, age} = {"name": "ann", "age": 30}; name`, "ann"},
+		{`let {name: n} = {"name": "ann"}; n`, "ann"},
+		{`let {pos: [x, y]} = {"pos": [1, 2]}; x * 10 + y`, int64(12)},
+		{`let [{id}] = [{"id": 7}]; id`, int64(7)},
+		{`struct Person { name, age }; let {name, age} = Person("bob", 40); [name, age]`, `["bob", 40]`},
+		// the rest is a copy , not a view of the array
+		{`let xs = [1, 2, 3]; let [a, ...rest] = xs; xs`, "[1, 2, 3]"},
+		{`let [a, b] = 5`, ErrorMesssage("[Error]: Cannot destructure 5 into [a, b] , expected ARRAY, got=INTEGER")},
+		{`let [a, b] = [1, 2, 3]`, ErrorMesssage("[Error]: Cannot destructure [1, 2, 3] into [a, b] , expected 2 elements, got=3")},
+		{`let [a, b, ...c] = [1]`, ErrorMesssage("[Error]: Cannot destructure [1] into [a, b, ...c] , expected at least 2 elements, got=1")},
+		{`let {name} = [1]`, ErrorMesssage("[Error]: Cannot destructure [1] into {name} , expected HASH or INSTANCE, got=ARRAY")},
+		{`let {name} = {"age": 1}`, ErrorMesssage(`[Error]: Cannot destructure {"age": 1} into {name} , HASH has no key name`)},
+		{`struct P { x }; let {y} = P(1)`, ErrorMesssage("[Error]: Cannot destructure P{x: 1} into {y} , INSTANCE has no key y")},
+		{`let [a, [b]] = [1, 2]`, ErrorMesssage("[Error]: Cannot destructure 2 into [b] , expected ARRAY, got=INTEGER")},
+		// nothing is bound when the shape does not match
+		{`let a = 0; try { let [a, [b]] = [1, 2] } catch (e) { 0 }; a`, int64(0)},
+	}
+	for _, tt := range tests {
+		testResult(t, tt.input, tt.expected)
+	}
+}
+
+func TestDestructuringParameters(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{`let f = func([a, b]) { a - b }; f([5, 2])`, int64(3)},
+		{`let f = func({x, y}, scale) { (x + y) * scale }; f({"x": 1, "y": 2}, 10)`, int64(30)},
+		{`let head = func([first, ...rest]) { first }; map([[1, 2], [3]], head)`, "[1, 3]"},
+		{`let f = func([a, b]) { a }; f([1])`, ErrorMesssage("[Error]: Cannot destructure [1] into [a, b] , expected 2 elements, got=1")},
+		{`let f = func({x}) { x }; f(1)`, ErrorMesssage("[Error]: Cannot destructure 1 into {x} , expected HASH or INSTANCE, got=INTEGER")},
+	}
+	for _, tt := range tests {
+		testResult(t, tt.input, tt.expected)
+	}
+}
@@ -1,11 +1,11 @@
 package evaluator
 
 import (
-	"bytes"
 	"fmt"
 	"khanhanh_lang/ast"
 	"khanhanh_lang/object"
-	"strconv"
+	"khanhanh_lang/token"
+	"strings"
 )
 
 // Since we dont want to create distinct , true/false object evertytime (there is only two possible value )
@@ -26,6 +26,14 @@ func newError(format string, a ...any) *object.Error {
 	return &object.Error{Message: fmt.Sprintf(format, a...)}
 }
 
+// record where an error was raised , the innermost position is kept while the error propagate
+func atPosition(obj object.Object, tok token.Token) object.Object {
+	if err, ok := obj.(*object.Error); ok && err.Pos.Line == 0 {
+		err.Pos = tok.Pos()
+	}
+	return obj
+}
+
 func isError(obj object.Object) bool {
 	if obj != nil {
 		return obj.Type() == object.ERROR_OBJ
@@ -44,7 +52,7 @@ func Eval(node ast.Node, tracker *object.Tracker) object.Object {
 		if isError(right) {
 			return right
 		}
-		return evalPrefixExpression(node.Operator, right)
+		return atPosition(evalPrefixExpression(node.Operator, right), node.Token)
 	case *ast.InfixExpression:
 		left := Eval(node.Left, tracker)
 		if isError(left) {
@@ -54,9 +62,11 @@ func Eval(node ast.Node, tracker *object.Tracker) object.Object {
 		if isError(right) {
 			return right
 		}
-		return evalInfixExpression(node.Operator, left, right)
+		return atPosition(evalInfixExpression(node.Operator, left, right, tracker.Strict()), node.Token)
 	case *ast.IntegerLiteral:
 		return &object.Integer{Value: node.Value}
+	case *ast.FloatLiteral:
+		return &object.Float{Value: node.Value}
 	case *ast.BooleanLiteral:
 		return nativeBool(node.Value)
 	case *ast.StringLiteral:
@@ -77,23 +87,94 @@ func Eval(node ast.Node, tracker *object.Tracker) object.Object {
 		if isError(val) {
 			return val
 		}
-		tracker.Set(node.Name.Value, val)
+		if err := checkAnnotation(node.Type, val, node.Target().String()); err != nil {
+			return atPosition(err, node.Token)
+		}
+		bind := tracker.Set
+		if node.IsConst() {
+			bind = tracker.SetConst
+		}
+		if node.Pattern != nil {
+			return atPosition(bindPattern(node.Pattern, val, bind), node.Token)
+		}
+		if result := bind(node.Name.Value, val); isError(result) {
+			return atPosition(result, node.Name.Token)
+		}
 	case *ast.Identifier:
-		return evalIdentifier(node, tracker)
+		return atPosition(evalIdentifier(node, tracker), node.Token)
 	case *ast.FunctionLiteral:
 		params := node.Parameters
 		body := node.Body
-		return &object.Function{Parameters: params, Env: tracker, Body: body}
+		return &object.Function{
+			Parameters:     params,
+			Env:            tracker,
+			Body:           body,
+			ParameterTypes: node.ParameterTypes,
+			ReturnType:     node.ReturnType,
+			Generator:      node.Generator,
+		}
 	case *ast.CallExpression:
 		function := Eval(node.Function, tracker)
 		if isError(function) {
 			return function
 		}
-		args := evalExpressions(node.Arguments, tracker)
+		args, named := evalArguments(node.Arguments, tracker)
 		if len(args) == 1 && isError(args[0]) {
 			return args[0]
 		}
-		return applyFunction(function, args)
+		result := applyNamed(function, args, named)
+		addFrame(result, function, node)
+		return atPosition(result, node.Token)
+	case *ast.ArrayLiteral:
+		elements := evalExpressions(node.Elements, tracker)
+		if len(elements) == 1 && isError(elements[0]) {
+			return elements[0]
+		}
+		return &object.Array{Elements: elements}
+	case *ast.HashLiteral:
+		return atPosition(evalHashLiteral(node, tracker), node.Token)
+	case *ast.IndexExpression:
+		left := Eval(node.Left, tracker)
+		if isError(left) {
+			return left
+		}
+		index := Eval(node.Index, tracker)
+		if isError(index) {
+			return index
+		}
+		return atPosition(evalIndexExpression(left, index), node.Token)
+	case *ast.MemberExpression:
+		obj := Eval(node.Object, tracker)
+		if isError(obj) {
+			return obj
+		}
+		return atPosition(evalMemberExpression(obj, node.Member.Value), node.Member.Token)
+	case *ast.ImportStatement:
+		module := atPosition(evalImport(node.Path, tracker), node.Token)
+		if isError(module) {
+			return module
+		}
+		if result := tracker.Set(node.Name(), module); isError(result) {
+			return atPosition(result, node.Token)
+		}
+	case *ast.ImportExpression:
+		return atPosition(evalImport(node.Path, tracker), node.Token)
+	case *ast.StructStatement:
+		if result := evalStructStatement(node, tracker); isError(result) {
+			return atPosition(result, node.Name.Token)
+		}
+	case *ast.ThrowStatement:
+		return evalThrowStatement(node, tracker)
+	case *ast.TryExpression:
+		return evalTryExpression(node, tracker)
+	case *ast.MatchExpression:
+		return evalMatchExpression(node, tracker)
+	case *ast.ForStatement:
+		return evalForStatement(node, tracker)
+	case *ast.YieldExpression:
+		return evalYieldExpression(node, tracker)
+	case *ast.SpreadExpression:
+		return atPosition(newError("[Error]: ... is only allowed in a call, a parameter list or an array pattern"), node.Token)
 	}
 	return nil
 
@@ -136,7 +217,7 @@ func evalBlockStatement(block *ast.BlockStatement, tracker *object.Tracker) obje
 // Evaluate scope and calling  associated function
 // -------------------------------------------------------------------------------
 func evalExpressions(exps []ast.Expression, env *object.Tracker) []object.Object {
-	var result []object.Object
+	result := []object.Object{}
 	for _, e := range exps {
 		evaluated := Eval(e, env)
 		if isError(evaluated) {
@@ -148,21 +229,38 @@ func evalExpressions(exps []ast.Expression, env *object.Tracker) []object.Object
 }
 
 func applyFunction(fn object.Object, args []object.Object) object.Object {
-	function, ok := fn.(*object.Function)
-	if !ok {
-		return newError("not a function: %s", fn.Type())
-	}
-	extendEnv := extendFunctionEnv(function, args)
-	evaluated := Eval(function.Body, extendEnv)
-	return unwrapReturnValue(evaluated)
+	return applyNamed(fn, args, nil)
 }
 
-func extendFunctionEnv(fn *object.Function, args []object.Object) *object.Tracker {
-	env := object.NewEnclosedTracker(fn.Env)
-	for paramIdx, param := range fn.Parameters {
-		env.Set(param.Value, args[paramIdx])
+// only the functions of the script and the structs accept named arguments
+func applyNamed(fn object.Object, args []object.Object, named []namedArgument) object.Object {
+	switch function := fn.(type) {
+	case *object.Function:
+		extendEnv, err := extendFunctionEnv(function, args, named)
+		if err != nil {
+			return err
+		}
+		if function.Generator {
+			return newGenerator(function, extendEnv)
+		}
+		evaluated := unwrapReturnValue(Eval(function.Body, extendEnv))
+		if isError(evaluated) {
+			return evaluated
+		}
+		if err := checkAnnotation(function.ReturnType, evaluated, "the result of "+signature(function)); err != nil {
+			return err
+		}
+		return evaluated
+	case *object.Builtin:
+		if len(named) != 0 {
+			return newError("[Error]: %s does not take named arguments", function.Name)
+		}
+		return function.Fn(args...)
+	case *object.Struct:
+		return newInstance(function, args, named)
+	default:
+		return newError("not a function: %s", fn.Type())
 	}
-	return env
 }
 
 func unwrapReturnValue(obj object.Object) object.Object {
@@ -186,24 +284,18 @@ func evalPrefixExpression(operator string, right object.Object) object.Object {
 }
 
 func evalMinusPrefix(right object.Object) object.Object {
-	if right.Type() != object.INTEGER_OBJ {
+	switch right := right.(type) {
+	case *object.Integer:
+		return &object.Integer{Value: -right.Value}
+	case *object.Float:
+		return &object.Float{Value: -right.Value}
+	default:
 		return newError("[Error]: Unknown operator -%s", right.Type())
 	}
-	value := right.(*object.Integer).Value
-	return &object.Integer{Value: -value}
 }
 
 func evalBangPrefix(right object.Object) object.Object {
-	switch right {
-	case TRUE:
-		return FALSE
-	case FALSE:
-		return TRUE
-	case NIL:
-		return TRUE
-	default:
-		return FALSE
-	}
+	return nativeBool(!isTruthy(right))
 }
 
 func evalIfExpression(ie *ast.IfExpression, tracker *object.Tracker) object.Object {
@@ -219,13 +311,13 @@ func evalIfExpression(ie *ast.IfExpression, tracker *object.Tracker) object.Obje
 		return NIL
 	}
 }
+
+// false and nil are falsy , every other value is truthy , whether it is one of the singletons or not
 func isTruthy(obj object.Object) bool {
-	switch obj {
-	case NIL:
-		return false
-	case TRUE:
-		return true
-	case FALSE:
+	switch obj := obj.(type) {
+	case *object.Boolean:
+		return obj.Value
+	case *object.Nil:
 		return false
 	default:
 		return true
@@ -233,80 +325,196 @@ func isTruthy(obj object.Object) bool {
 }
 
 func evalIdentifier(node *ast.Identifier, tracker *object.Tracker) object.Object {
-	val, ok := tracker.Get(node.Value)
-	if !ok {
-		return newError("[Error]: Identifier not found: " + node.Value)
+	if val, ok := tracker.Get(node.Value); ok {
+		return val
+	}
+	// builtins come last , so a script can shadow them
+	if builtin, ok := hostBuiltin(node.Value, tracker.Host()); ok {
+		return builtin
 	}
-	return val
+	if builtin, ok := builtins[node.Value]; ok {
+		return builtin
+	}
+	return newError("[Error]: Identifier not found: " + node.Value)
 }
 
 // ---------------------------------------------------------------------
-// INFIX EXPRESSION
-func evalInfixExpression(operator string, left, right object.Object) object.Object {
+// INDEX AND MEMBER
+// index out of range evaluate to nil
+func evalIndexExpression(left, index object.Object) object.Object {
 	switch {
-	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
-		return evalIntOperator(operator, left, right)
-	case left.Type() == object.BOOLEAN_OBJ && right.Type() == object.BOOLEAN_OBJ:
-		return evalBoolOperator(operator, left, right)
-	case left.Type() == object.STRING_OBJ:
-		return evalStringOperator(operator, left, right)
-
+	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
+		elements := left.(*object.Array).Elements
+		i := index.(*object.Integer).Value
+		if i < 0 || i >= int64(len(elements)) {
+			return NIL
+		}
+		return elements[i]
+	case left.Type() == object.HASH_OBJ:
+		key, ok := index.(object.Hashable)
+		if !ok {
+			return newError("[Error]: Unusable as hash key: %s", index.Type())
+		}
+		value, ok := left.(*object.Hash).Get(key)
+		if !ok {
+			return NIL
+		}
+		return value
+	case left.Type() == object.STRING_OBJ && index.Type() == object.INTEGER_OBJ:
+		// index count in character , not in byte
+		runes := []rune(left.(*object.String).Value)
+		i := index.(*object.Integer).Value
+		if i < 0 || i >= int64(len(runes)) {
+			return NIL
+		}
+		return &object.String{Value: string(runes[i])}
 	default:
-		return newError("[Error]: Mismatch %s %s %s", left.Type(), operator, right.Type())
+		return newError("[Error]: Index operator not supported: %s[%s]", left.Type(), index.Type())
 	}
 }
 
-// boolean operator
-func evalBoolOperator(operator string, left, right object.Object) object.Object {
-	leftValue := left.(*object.Boolean).Value
-	rightValue := right.(*object.Boolean).Value
-
-	switch operator {
-	case "==":
-		return nativeBool(leftValue == rightValue)
-	case "!=":
-		return nativeBool(leftValue != rightValue)
-	default:
-		return newError("[Error]: Unknown %s %s %s", left.Type(), operator, right.Type())
+func evalHashLiteral(node *ast.HashLiteral, tracker *object.Tracker) object.Object {
+	hash := object.NewHash()
+	for i, keyNode := range node.Keys {
+		key := Eval(keyNode, tracker)
+		if isError(key) {
+			return key
+		}
+		hashKey, ok := key.(object.Hashable)
+		if !ok {
+			return newError("[Error]: Unusable as hash key: %s", key.Type())
+		}
+		value := Eval(node.Values[i], tracker)
+		if isError(value) {
+			return value
+		}
+		hash.Set(hashKey, value)
 	}
+	return hash
 }
 
-// string operator
-func evalStringOperator(operator string, left, right object.Object) object.Object {
-	leftValue := left.(*object.String).Value
-	var rightValue string
-	switch right := right.(type) {
-	case *object.String:
-		rightValue = right.Value
-	case *object.Boolean:
+func evalMemberExpression(obj object.Object, name string) object.Object {
+	switch obj := obj.(type) {
+	case *object.Instance:
+		return evalInstanceMember(obj, name)
+	case *object.Exception:
+		return evalExceptionMember(obj, name)
+	case *object.Module:
+		member, ok := obj.Members[name]
+		if !ok {
+			return newError("[Error]: Module %s has no member %s", obj.Name, name)
+		}
+		return member
+	case *object.Hash:
+		// h.name is h["name"] , a missing key give the method of that name or nil as h["name"] would
+		if value, ok := obj.Get(&object.String{Value: name}); ok {
+			return value
+		}
+	}
+	if bound, ok := method(obj, name); ok {
+		return bound
+	}
+	if obj.Type() == object.HASH_OBJ {
 		return NIL
-	case *object.Integer:
-		rightValue = strconv.Itoa(int(right.Value))
+	}
+	return newError("[Error]: %s has no member %s", obj.Type(), name)
+}
+
+// ---------------------------------------------------------------------
+// INFIX EXPRESSION
+// What every operator do with every pair of operand types , swapping the operands never change what is allowed
+//
+//	number + - * / number         arithmetic , an integer meeting a float is promoted to a float
+//	number < > <= >= number       comparison , exact between an integer and a float
+//	string|array < > <= >= same   ordering , see compare
+//	string + string               concatenation
+//	string + int|float|bool|nil   concatenation with the text of the other operand , on either side ,
+//	                              "a" + 1 is "a1" and 1 + "a" is "1a"
+//	string * int                  repetition , on either side , a count below 1 give ""
+//	any == != any                 structural equality , see equal , values of different types are never
+//	                              equal , "5" == 5 is false , except an integer and a float
+//
+// Every other pair is an error , Mismatch for operands of different types and Unknown operator for the same type
+// In strict mode ( Tracker.SetStrict ) a value is never converted to a string , "a" + 1 is a Mismatch too
+func evalInfixExpression(operator string, left, right object.Object, strict bool) object.Object {
+	switch {
+	case operator == "==":
+		return nativeBool(equal(left, right))
+	case operator == "!=":
+		return nativeBool(!equal(left, right))
+	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
+		return evalIntOperator(operator, left, right)
+	case isNumber(left) && isNumber(right):
+		// at least one float , the integer is promoted
+		return evalFloatOperator(operator, left, right)
+	case isComparison(operator) && left.Type() == right.Type() && orderRank(left) >= 0:
+		return evalComparison(operator, left, right)
+	case left.Type() == object.STRING_OBJ || right.Type() == object.STRING_OBJ:
+		return evalStringOperator(operator, left, right, strict)
 	default:
-		return NIL
+		return operatorError(operator, left, right)
+	}
+}
+
+func operatorError(operator string, left, right object.Object) *object.Error {
+	if left.Type() == right.Type() {
+		return newError("[Error]: Unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
+	return newError("[Error]: Mismatch %s %s %s", left.Type(), operator, right.Type())
+}
+
+// string operator , at least one of the operands is a string
+func evalStringOperator(operator string, left, right object.Object, strict bool) object.Object {
+	leftString, leftOk := left.(*object.String)
+	rightString, rightOk := right.(*object.String)
 
 	switch operator {
 	case "+":
-		return &object.String{Value: leftValue + rightValue}
+		switch {
+		case leftOk && rightOk:
+			return &object.String{Value: leftString.Value + rightString.Value}
+		case !strict && hasText(left) && hasText(right):
+			return &object.String{Value: left.Inspect() + right.Inspect()}
+		}
 	case "*":
-		n, err := strconv.Atoi(rightValue)
-		if err != nil {
-			return NIL
+		if count, ok := right.(*object.Integer); ok && leftOk {
+			return repeat(leftString.Value, count.Value)
 		}
-		var out bytes.Buffer
-		for i := 0; i < n; i++ {
-			out.WriteString(leftValue)
+		if count, ok := left.(*object.Integer); ok && rightOk {
+			return repeat(rightString.Value, count.Value)
 		}
-		return &object.String{Value: out.String()}
-	case "==":
-		return nativeBool(leftValue == rightValue)
-	case "!=":
-		return nativeBool(leftValue != rightValue)
-	default:
-		return newError("[Error]: Unknown operator %s", operator)
 	}
+	return operatorError(operator, left, right)
+}
 
+// values a string can be concatenated with , the others have no obvious text
+func hasText(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.String, *object.Integer, *object.Float, *object.Boolean, *object.Nil:
+		return true
+	}
+	return false
+}
+
+// the longest string a repeat build , in bytes
+const maxRepeatLength = 1 << 28
+
+func repeat(s string, count int64) object.Object {
+	if count < 1 {
+		return &object.String{Value: ""}
+	}
+	if err := checkRepeatLength(s, count); err != nil {
+		return err
+	}
+	return &object.String{Value: strings.Repeat(s, int(count))}
+}
+
+// error when count copies of s are longer than maxRepeatLength , compared by division so nothing overflow
+func checkRepeatLength(s string, count int64) *object.Error {
+	if len(s) > 0 && count > maxRepeatLength/int64(len(s)) {
+		return newError("[Error]: String repeat too long: %d copies of %d bytes , the limit is %d bytes", count, len(s), maxRepeatLength)
+	}
+	return nil
 }
 
 // Integer operator
@@ -321,20 +529,58 @@ func evalIntOperator(operator string, left, right object.Object) object.Object {
 	case "*":
 		return &object.Integer{Value: leftValue * rightValue}
 	case "/":
+		if rightValue == 0 {
+			return newError("[Error]: Division by zero: %d / 0", leftValue)
+		}
 		return &object.Integer{Value: leftValue / rightValue}
-	case "==":
-		return nativeBool(leftValue == rightValue)
-	case "!=":
-		return nativeBool(leftValue != rightValue)
-	case "<":
-		return nativeBool(leftValue < rightValue)
-	case ">":
-		return nativeBool(leftValue > rightValue)
-	case "<=":
-		return nativeBool(leftValue <= rightValue)
-	case ">=":
-		return nativeBool(leftValue >= rightValue)
+	case "<", ">", "<=", ">=":
+		// an integer is compared exactly , not rounded to a float , NaN is neither below nor above anything
+		order, ok := compareNumbers(left, right)
+		return nativeBool(ok && orderHolds(operator, order))
+	default:
+		return operatorError(operator, left, right)
+	}
+}
+
+// NUMBERS
+// ---------------------------------------------------------------------
+// every numeric representation , new ones only need to be added here and in toFloat
+func isNumber(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Integer, *object.Float:
+		return true
+	}
+	return false
+}
+
+func toFloat(obj object.Object) float64 {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return float64(obj.Value)
+	case *object.Float:
+		return obj.Value
+	}
+	return 0
+}
+
+// Float operator
+func evalFloatOperator(operator string, left, right object.Object) object.Object {
+	leftValue := toFloat(left)
+	rightValue := toFloat(right)
+	switch operator {
+	case "+":
+		return &object.Float{Value: leftValue + rightValue}
+	case "-":
+		return &object.Float{Value: leftValue - rightValue}
+	case "*":
+		return &object.Float{Value: leftValue * rightValue}
+	case "/":
+		return &object.Float{Value: leftValue / rightValue}
+	case "<", ">", "<=", ">=":
+		// an integer is compared exactly , not rounded to a float , NaN is neither below nor above anything
+		order, ok := compareNumbers(left, right)
+		return nativeBool(ok && orderHolds(operator, order))
 	default:
-		return newError("[Error]: Unknwon operator %s %s %s", left.Type(), operator, right.Type())
+		return operatorError(operator, left, right)
 	}
 }
@@ -209,3 +209,120 @@ func testTypeObject(t *testing.T, obj object.Object, expect any) bool {
 	}
 	return false
 }
+
+// testResult evaluate input and compare its result with expected : an ErrorMesssage with the whole message of the
+// error , a string with the Inspect of a result that is not a string ( an array , a hash ) , anything else as
+// testTypeObject do
+func testResult(t *testing.T, input string, expected any) {
+	t.Helper()
+	result := testEval(input)
+	switch expected := expected.(type) {
+	case ErrorMesssage:
+		err, ok := result.(*object.Error)
+		if !ok {
+			t.Errorf("%s should evaluate to an error. expect=%q got=%T(%+v)", input, expected, result, result)
+			return
+		}
+		if err.Message != string(expected) {
+			t.Errorf("%s wrong error. expect=%q got=%q", input, expected, err.Message)
+		}
+		return
+	case string:
+		if _, ok := result.(*object.String); !ok && result != nil {
+			if result.Inspect() != expected {
+				t.Errorf("%s wrong result. expect=%s got=%s", input, expected, result.Inspect())
+			}
+			return
+		}
+	}
+	testTypeObject(t, result, expected)
+}
+
+func TestErrorPosition(t *testing.T) {
+	tests := []struct {
+		input  string
+		line   int
+		column int
+	}{
+		{"let a = 1;\na + true", 2, 3},
+		{"-true", 1, 1},
+		{"let f = func(x) { x + true };\n\nf(1)", 1, 21},
+		{"1 + foobar", 1, 5},
+		{"let x = 3;\nmatch x { 1 => 1 }", 2, 1},
+		{"let [a, [b]] =\n  [1, 2]", 1, 9},
+	}
+
+	for _, test := range tests {
+		err, ok := testEval(test.input).(*object.Error)
+		if !ok {
+			t.Fatalf("%q should evaluate to an error", test.input)
+		}
+		if err.Pos.Line != test.line || err.Pos.Column != test.column {
+			t.Errorf("%q error at %d:%d , want=%d:%d", test.input, err.Pos.Line, err.Pos.Column, test.line, test.column)
+		}
+	}
+}
+
+func TestArrayAndIndex(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{"[1, 2 * 2, 3 + 3][1]", int64(4)},
+		{"let a = [1, 2, 3]; a[0] + a[1] + a[2]", int64(6)},
+		{"let i = 0; [1][i]", int64(1)},
+		{"[1, 2, 3][3]", nil},
+		{"[1, 2, 3][-1]", nil},
+		{`"abc"[2]`, "c"},
+		{`"abc"[5]`, nil},
+		{"[[1, 2], [3]][0][1]", int64(2)},
+		{"1[0]", ErrorMesssage("[Error]: Index operator not supported: INTEGER[INTEGER]")},
+		{"true.x", ErrorMesssage("[Error]: BOOLEAN has no member x")},
+	}
+
+	for _, test := range tests {
+		testTypeObject(t, testEval(test.input), test.expected)
+	}
+
+	if got := testEval(`[1, "a", [true]]`).Inspect(); got != `[1, "a", [true]]` {
+		t.Errorf("wrong Inspect of array. got=%s", got)
+	}
+}
+
+func TestBuiltinShadowing(t *testing.T) {
+	testTypeObject(t, testEval(`let strings = 5; strings`), int64(5))
+	if got := testEval(`strings`).Inspect(); got != "module strings" {
+		t.Errorf("strings should be a module. got=%s", got)
+	}
+	if got := testEval(`strings.upper`).Inspect(); got != "builtin strings.upper" {
+		t.Errorf("wrong Inspect of builtin. got=%s", got)
+	}
+}
+
+func TestHashLiteral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{`{"one": 1, "two": 2}["two"]`, int64(2)},
+		{`let key = "a"; {key: 5}["a"]`, int64(5)},
+		{`{1: "int", true: "bool"}[1]`, "int"},
+		{`{1: "int", true: "bool"}[true]`, "bool"},
+		{`{"a": 1}["b"]`, nil},
+		{`{}["a"]`, nil},
+		{`{"a": 1, "a": 2}["a"]`, int64(2)},
+		{`{"1": "string", 1: "int"}["1"]`, "string"},
+		{`{"ab": 1, "a": 2, "b": 3, "": 4}[""]`, int64(4)},
+		{`{[1]: 2}`, ErrorMesssage("[Error]: Unusable as hash key: ARRAY")},
+		{`{"a": 1}[func(x) { x }]`, ErrorMesssage("[Error]: Unusable as hash key: FUNCTION")},
+	}
+
+	for _, test := range tests {
+		testTypeObject(t, testEval(test.input), test.expected)
+	}
+
+	// pairs keep the order they were written in , a repeated key keep its first position
+	if got := testEval(`{"b": 1, "a": [true], 3: "x", "b": 2}`).Inspect(); got != `{"b": 2, "a": [true], 3: "x"}` {
+		t.Errorf("wrong Inspect of hash. got=%s", got)
+	}
+}
@@ -0,0 +1,97 @@
+package evaluator
+
+import (
+	"khanhanh_lang/ast"
+	"khanhanh_lang/object"
+)
+
+// EXCEPTIONS
+// ---------------------------------------------------------------------------------
+// throw turn any value into an error , which unwind like the errors of the interpreter until a try catch it
+// The catch parameter only exist inside the catch block , it is an Exception with the members
+// message , kind , value , stack , line and column . finally run after the body and the catch whatever
+// they did , a return or a throw inside finally replace the one in progress . The error unwinding a closed
+// generator go through every catch , only its finally blocks run
+
+func evalThrowStatement(node *ast.ThrowStatement, tracker *object.Tracker) object.Object {
+	value := Eval(node.Value, tracker)
+	if isError(value) {
+		return value
+	}
+	return atPosition(throwValue(value), node.Token)
+}
+
+// a string is the message , an instance give its struct name as kind and its message field when it has one ,
+// an exception is thrown again as it was caught
+func throwValue(value object.Object) *object.Error {
+	switch value := value.(type) {
+	case *object.Exception:
+		return value.Err
+	case *object.String:
+		return &object.Error{Message: "[Error]: " + value.Value, Kind: "Error", Value: value}
+	case *object.Instance:
+		message := value.Inspect()
+		if field, ok := value.Get("message"); ok && field.Type() == object.STRING_OBJ {
+			message = field.(*object.String).Value
+		}
+		return &object.Error{Message: "[Error]: " + message, Kind: value.Struct.Name, Value: value}
+	default:
+		return &object.Error{Message: "[Error]: " + value.Inspect(), Kind: "Error", Value: value}
+	}
+}
+
+func evalTryExpression(node *ast.TryExpression, tracker *object.Tracker) object.Object {
+	result := Eval(node.Body, tracker)
+	if err, ok := result.(*object.Error); ok && node.Catch != nil && !err.Closing() {
+		catchTracker := object.NewEnclosedTracker(tracker)
+		catchTracker.Set(node.Param.Value, &object.Exception{Err: err})
+		result = Eval(node.Catch, catchTracker)
+	}
+	if node.Finally != nil {
+		final := Eval(node.Finally, tracker)
+		if final != nil && (final.Type() == object.ERROR_OBJ || final.Type() == object.RETURN_VALUE_OBJ) {
+			return final
+		}
+	}
+	if result == nil {
+		return NIL
+	}
+	return result
+}
+
+// record the call of a function the error went through
+func addFrame(result object.Object, function object.Object, node *ast.CallExpression) {
+	err, ok := result.(*object.Error)
+	if !ok {
+		return
+	}
+	if _, ok := function.(*object.Function); ok {
+		err.Stack = append(err.Stack, object.Frame{Function: node.Function.String(), Pos: node.Token.Pos()})
+	}
+}
+
+func evalExceptionMember(exception *object.Exception, name string) object.Object {
+	err := exception.Err
+	switch name {
+	case "message":
+		return &object.String{Value: exception.Message()}
+	case "kind":
+		return &object.String{Value: exception.Kind()}
+	case "value":
+		if err.Value == nil {
+			return NIL
+		}
+		return err.Value
+	case "stack":
+		frames := make([]string, len(err.Stack))
+		for i, frame := range err.Stack {
+			frames[i] = frame.String()
+		}
+		return newStringArray(frames)
+	case "line":
+		return &object.Integer{Value: int64(err.Pos.Line)}
+	case "column":
+		return &object.Integer{Value: int64(err.Pos.Column)}
+	}
+	return newError("[Error]: %s has no member %s", exception.Type(), name)
+}
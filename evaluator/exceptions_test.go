@@ -0,0 +1,71 @@
+package evaluator
+
+import (
+	"testing"
+)
+
+func TestTryCatch(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{`try { throw "boom" } catch (e) { e.message }`, "boom"},
+		{`try { throw "boom" } catch (e) { e.kind }`, "Error"},
+		{`try { 1 + true } catch (e) { e.message }`, "Mismatch INTEGER + BOOLEAN"},
+		{`try { 1 + true } catch (e) { e.kind }`, "RuntimeError"},
+		{`try { missing } catch (e) { e.message }`, "Identifier not found: missing"},
+		{`try { 1 / 0 } catch (e) { e.message }`, "Division by zero: 1 / 0"},
+		{`try { throw 42 } catch (e) { e.value + 1 }`, int64(43)},
+		{`try { 1 + true } catch (e) { e.value }`, nil},
+		{`try { 1 } catch (e) { 2 }`, int64(1)},
+		{`try { } catch (e) { 2 }`, nil},
+		{`let r = try { throw "x" } catch (e) { "caught" }; r`, "caught"},
+		{`struct NotFound { path, message }; try { throw NotFound("a.txt", "no a.txt") } catch (e) { [e.kind, e.message, e.value.path] }`, `["NotFound", "no a.txt", "a.txt"]`},
+		{`struct Oops { code }; try { throw Oops(1) } catch (e) { e.message }`, "Oops{code: 1}"},
+		// rethrow keep the original error
+		{`try { try { 1 + true } catch (e) { throw e } } catch (e) { e.kind }`, "RuntimeError"},
+		// the catch parameter only exist in the catch block
+		{`try { throw 1 } catch (e) { 1 }; e`, ErrorMesssage("[Error]: Identifier not found: e")},
+		{`try { throw "boom" } finally { 1 }`, ErrorMesssage("[Error]: boom")},
+		{`throw "up"`, ErrorMesssage("[Error]: up")},
+		{`try { throw "a" } catch (e) { throw "b" }`, ErrorMesssage("[Error]: b")},
+		{`try { throw 1 } catch (e) { e.nope }`, ErrorMesssage("[Error]: EXCEPTION has no member nope")},
+	}
+	for _, tt := range tests {
+		testResult(t, tt.input, tt.expected)
+	}
+}
+
+func TestFinally(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		// finally run on a normal end , a throw and a return
+		{`let log = func() { try { 1 } finally { throw "ran" } }; try { log() } catch (e) { e.message }`, "ran"},
+		{`try { try { throw "x" } finally { throw "finally" } } catch (e) { e.message }`, "finally"},
+		{`let f = func() { try { return 1 } finally { throw "after return" } }; try { f() } catch (e) { e.message }`, "after return"},
+		// a value of finally does not replace the result
+		{`try { 1 } finally { 2 }`, int64(1)},
+		{`try { throw "x" } catch (e) { 2 } finally { 3 }`, int64(2)},
+		// a return in finally replace the one in progress
+		{`let f = func() { try { return 1 } finally { return 2 } }; f()`, int64(2)},
+		{`let f = func() { try { throw "x" } finally { return 2 } }; f()`, int64(2)},
+		{`let f = func() { try { return 1 } catch (e) { 5 } ; 10 }; f()`, int64(1)},
+	}
+	for _, tt := range tests {
+		testResult(t, tt.input, tt.expected)
+	}
+}
+
+func TestExceptionStack(t *testing.T) {
+	input := `
+let inner = func() { 1 + true };
+let outer = func() { inner() };
+try { outer() } catch (e) { [e.stack, e.line, e.column, e] }`
+	result := testEval(input).Inspect()
+	expected := `[["inner at 3:27", "outer at 4:12"], 2, 24, RuntimeError: Mismatch INTEGER + BOOLEAN]`
+	if result != expected {
+		t.Errorf("wrong exception. expect=%s got=%s", expected, result)
+	}
+}
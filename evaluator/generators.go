@@ -0,0 +1,135 @@
+package evaluator
+
+import (
+	"khanhanh_lang/ast"
+	"khanhanh_lang/object"
+)
+
+// GENERATORS
+// ---------------------------------------------------------------------------------
+// A call of a func* , or of a function whose body contain a yield , bind the arguments then return a generator
+// without running the body . gen.next(x) run the body until its next yield and return {"value": v, "done": false} ,
+// x become the value of the yield the body was suspended in . Once the body end next return its result as
+// {"value": result, "done": true} , then {"value": nil, "done": true} . gen.close() end it early , the finally
+// blocks around the suspended yield still run but no catch see it
+// for x in iterable { } run its body for each element of an array , each character of a string , each key of a
+// hash or each value of a generator , a generator the loop leave early , by a return or an error , is closed
+
+func newGenerator(fn *object.Function, env *object.Tracker) object.Object {
+	return object.NewGenerator(fn, env, func(env *object.Tracker) object.Object {
+		result := unwrapReturnValue(Eval(fn.Body, env))
+		if result == nil {
+			result = NIL
+		}
+		if isError(result) {
+			return result
+		}
+		if err := checkAnnotation(fn.ReturnType, result, "the result of "+signature(fn)); err != nil {
+			return err
+		}
+		return result
+	})
+}
+
+func evalYieldExpression(node *ast.YieldExpression, tracker *object.Tracker) object.Object {
+	value := object.Object(NIL)
+	if node.Value != nil {
+		value = Eval(node.Value, tracker)
+		if isError(value) {
+			return value
+		}
+	}
+	return atPosition(tracker.Yield(value), node.Token)
+}
+
+// gen.next() , gen.next(x)
+func generatorNext(args ...object.Object) object.Object {
+	if err := checkArgs("next", args, 1, object.GENERATOR_OBJ, ANY_OBJ); err != nil {
+		return err
+	}
+	sent := object.Object(NIL)
+	if len(args) == 2 {
+		sent = args[1]
+	}
+	value, done := args[0].(*object.Generator).Next(sent)
+	if isError(value) {
+		return value
+	}
+	if value == nil {
+		value = NIL
+	}
+	result := object.NewHash()
+	result.Set(&object.String{Value: "value"}, value)
+	result.Set(&object.String{Value: "done"}, nativeBool(done))
+	return result
+}
+
+// gen.close()
+func generatorClose(args ...object.Object) object.Object {
+	if err := checkArgs("close", args, 1, object.GENERATOR_OBJ); err != nil {
+		return err
+	}
+	if err := args[0].(*object.Generator).Close(); err != nil {
+		return err
+	}
+	return NIL
+}
+
+func evalForStatement(node *ast.ForStatement, tracker *object.Tracker) object.Object {
+	iterable := Eval(node.Iterable, tracker)
+	if isError(iterable) {
+		return iterable
+	}
+	// run the body for value in a scope of its own , the return or the error that end the loop is returned
+	turn := func(value object.Object) object.Object {
+		env := object.NewEnclosedTracker(tracker)
+		if err := bindPattern(node.Target, value, env.Set); err != nil {
+			return atPosition(err, node.Token)
+		}
+		result := Eval(node.Body, env)
+		if result != nil && (result.Type() == object.RETURN_VALUE_OBJ || result.Type() == object.ERROR_OBJ) {
+			return result
+		}
+		return nil
+	}
+
+	switch iterable := iterable.(type) {
+	case *object.Array:
+		for _, el := range iterable.Elements {
+			if result := turn(el); result != nil {
+				return result
+			}
+		}
+	case *object.String:
+		for _, r := range iterable.Value {
+			if result := turn(&object.String{Value: string(r)}); result != nil {
+				return result
+			}
+		}
+	case *object.Hash:
+		for _, pair := range iterable.Pairs() {
+			if result := turn(pair.Key); result != nil {
+				return result
+			}
+		}
+	case *object.Generator:
+		for {
+			value, done := iterable.Next(NIL)
+			if isError(value) {
+				return value
+			}
+			if done {
+				break
+			}
+			if result := turn(value); result != nil {
+				if err := iterable.Close(); err != nil {
+					return err
+				}
+				return result
+			}
+		}
+	default:
+		return atPosition(newError("[Error]: Cannot iterate over %s", iterable.Type()), node.Token)
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package evaluator
+
+import (
+	"khanhanh_lang/lexer"
+	"khanhanh_lang/object"
+	"khanhanh_lang/parser"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestGenerators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let g = func*() { yield 1; yield 2 }(); [g.next(), g.next(), g.next(), g.next()]`,
+			`[{"value": 1, "done": false}, {"value": 2, "done": false}, {"value": nil, "done": true}, {"value": nil, "done": true}]`},
+		{`let g = func(n) { yield n; n * 10 }(4); [g.next().value, g.next(), g.next()]`,
+			`[4, {"value": 40, "done": true}, {"value": nil, "done": true}]`},
+		{`let g = func*() { return 5; yield 1 }(); g.next()`, `{"value": 5, "done": true}`},
+		{`let echo = func() { let x = yield 1; let y = yield x * 2; x + y }; let g = echo();
+		  [g.next(100).value, g.next(5).value, g.next(7).value]`, "[1, 10, 12]"},
+		{`let g = func() { yield }(); [g.next().value, g.next().done]`, "[nil, true]"},
+		{`let g = func*(xs) { for x in xs { yield x * 2 } }([1, 2, 3]); [g.next().value, g.next().value, g.next().value, g.next().done]`,
+			"[2, 4, 6, true]"},
+		{`let called = func*() { yield 1 }; let g = called(); g`, "generator"},
+		{`func*(a: int) { a }`, "func*(a: int) {\na\n}"},
+		{`let f = func() { 1 }; let g = func*() { yield f() }(); g.next().value`, "1"},
+		// the arguments are bound at the call , the body only run at the first next
+		{`let g = func*(a) { throw "late" }(1); "early"`, "early"},
+		{`func*(a) { yield a }()`, "[Error]: func(a) expect 1 argument, got=0"},
+		{`let g = func*() { throw "boom" }(); try { g.next() } catch (e) { e.message }`, "boom"},
+		{`let g = func*() { throw "boom" }(); try { g.next() } catch (e) { 0 }; g.next()`, `{"value": nil, "done": true}`},
+		{`let g = func*(): int { "a" }(); g.next()`, "[Error]: Cannot use string as int for the result of func(): int"},
+		{`let g = func*() { 1 }(); g.next(1, 2)`, "[Error]: next expect 1 to 2 arguments, got=3"},
+		{`let g = func*() { yield 1 }(); g.len()`, "[Error]: GENERATOR has no member len"},
+	}
+	for _, tt := range tests {
+		if got := testEval(tt.input).Inspect(); got != tt.expected {
+			t.Errorf("%s: expected %s , got %s", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestGeneratorAlreadyRunning(t *testing.T) {
+	input := `let g = func*() { yield self.next() }; let self = g(); self.next()`
+	if got := testEval(input).Inspect(); got != "[Error]: generator already running" {
+		t.Errorf("expected an error , got %s", got)
+	}
+}
+
+func TestGeneratorClose(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let g = func*() { yield 1; yield 2 }(); g.next(); [g.close(), g.next()]`, `[nil, {"value": nil, "done": true}]`},
+		{`let g = func*() { yield 1 }(); [g.close(), g.next()]`, `[nil, {"value": nil, "done": true}]`},
+		{`let g = func*() { yield 1 }(); g.next(); g.next(); g.close()`, "nil"},
+		// finally run , catch does not
+		{`let g = func*() { try { yield 1 } catch (e) { yield "caught" } finally { yield "again" } }();
+		  g.next(); [g.close(), g.next()]`, `[nil, {"value": nil, "done": true}]`},
+		{`let g = func*() { try { yield 1 } finally { throw "in finally" } }(); g.next(); g.close()`,
+			"[Error]: in finally"},
+		{`let g = func*() { try { yield 1 } finally { return 9 } }(); g.next(); g.close()`, "nil"},
+		// an inner generator is closed with the outer one
+		{`let inner = func*() { try { yield 1; yield 2 } finally { throw "inner closed" } };
+		  let outer = func*() { for x in inner() { yield x } }();
+		  outer.next(); outer.close()`, "[Error]: inner closed"},
+	}
+	for _, tt := range tests {
+		if got := testEval(tt.input).Inspect(); got != tt.expected {
+			t.Errorf("%s: expected %s , got %s", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestForStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let g = func*(xs) { for x in xs { yield x } }; let it = g("hé"); [it.next().value, it.next().value, it.next().done]`,
+			`["h", "é", true]`},
+		{`let g = func*(h) { for k in h { yield k } }({"b": 1, "a": 2}); [g.next().value, g.next().value]`, `["b", "a"]`},
+		{`let g = func*(xs) { for [i, x] in enumerate(xs) { yield i * x } }([5, 6]); [g.next().value, g.next().value]`, "[0, 6]"},
+		{`let g = func*(xs) { for {name} in xs { yield name } }([{"name": "a"}]); g.next().value`, "a"},
+		{`let find = func(xs, y) { for x in xs { if (x > y) { return x } }; "none" }; [find([1, 5, 9], 4), find([1], 4)]`,
+			`[5, "none"]`},
+		{`let nat = func*() { let n = func*(i) { yield i; for x in n(i + 1) { yield x } }; for x in n(0) { yield x } };
+		  let first = func(g, k) { for x in g { if (x == k) { return x } } }; first(nat(), 3)`, "3"},
+		{`for x in [1] { let y = x }; y`, "[Error]: Identifier not found: y"},
+		{`let x = 1; for x in [5] { x }; x`, "1"},
+		{`for x in [1, 2] { }`, "nil"},
+		{`for x in 5 { }`, "[Error]: Cannot iterate over INTEGER"},
+		{`for [a, b] in [[1]] { }`, "[Error]: Cannot destructure [1] into [a, b] , expected 2 elements, got=1"},
+		{`for x in [1, 0] { 1 / x }`, "[Error]: Division by zero: 1 / 0"},
+		{`let g = func*() { throw "bad" }; for x in g() { }`, "[Error]: bad"},
+		{`let g = func*() { try { yield 1 } finally { throw "cleanup" } }; for x in g() { throw "body" }`,
+			"[Error]: cleanup"},
+	}
+	for _, tt := range tests {
+		got := testEval(tt.input)
+		if got == nil {
+			got = NIL
+		}
+		if got.Inspect() != tt.expected {
+			t.Errorf("%s: expected %s , got %s", tt.input, tt.expected, got.Inspect())
+		}
+	}
+}
+
+// the goroutine count drop back to before once the goroutines of the generators are gone
+func waitGoroutines(t *testing.T, before int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("goroutines leaked , %d before , %d after", before, runtime.NumGoroutine())
+		}
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestGeneratorGoroutines(t *testing.T) {
+	before := runtime.NumGoroutine()
+	tracker := object.NewTracker()
+	eval := func(input string) object.Object {
+		return Eval(parser.New(lexer.New(input)).ParseProgram(), tracker)
+	}
+	eval(`let count = func*(n) { let i = func*(k) { yield k; for x in i(k + 1) { yield x } }; for x in i(n) { yield x } }`)
+
+	// a generator is started by its first next , and its goroutine end with the body
+	eval(`let g = count(0)`)
+	if runtime.NumGoroutine() != before {
+		t.Errorf("a generator not started yet should have no goroutine")
+	}
+	eval(`let g = func*() { yield 1 }(); g.next(); g.next()`)
+	waitGoroutines(t, before)
+
+	// left early by for and closed
+	eval(`let first = func(g) { for x in g { return x } }; first(count(0)); first(count(5))`)
+	waitGoroutines(t, before)
+	eval(`let g = count(0); g.next(); g.next(); g.close()`)
+	waitGoroutines(t, before)
+
+	// dropped while suspended , by the scope holding them and by binding the name again
+	for i := 0; i < 20; i++ {
+		eval(`let f = func() { let g = count(0); g.next(); g.next() }; f()`)
+		eval(`let g = count(0); g.next(); let g = 0`)
+	}
+	waitGoroutines(t, before)
+
+	// while the session is alive , a generator it still hold keep running
+	eval(`let kept = count(0); kept.next()`)
+	runtime.GC()
+	testTypeObject(t, eval(`kept.next().value`), int64(1))
+}
+
+func TestClosedSessionGoroutines(t *testing.T) {
+	before := runtime.NumGoroutine()
+	// the generators are held by the variables of the session , their bodies can reach them
+	for i := 0; i < 20; i++ {
+		tracker := object.NewTracker()
+		Eval(parser.New(lexer.New(`let gen = func*() { let i = 0; yield gen; yield 1 }; let g = gen(); g.next()`)).ParseProgram(), tracker)
+		tracker.Close()
+	}
+	waitGoroutines(t, before)
+}
+
+func TestClosedSessionGenerator(t *testing.T) {
+	tracker := object.NewTracker()
+	program := parser.New(lexer.New(`let g = func*() { yield 1; yield 2 }(); g.next(); g`)).ParseProgram()
+	g, ok := Eval(program, tracker).(*object.Generator)
+	if !ok {
+		t.Fatalf("expected a generator")
+	}
+	// a generator held by the caller keep running as long as its session is open
+	runtime.GC()
+	if value, done := g.Next(NIL); done || value.Inspect() != "2" {
+		t.Fatalf("expected 2 , got %v %t", value, done)
+	}
+	tracker.Close()
+	for i := 0; i < 2; i++ {
+		value, done := g.Next(NIL)
+		if !done || !isError(value) || value.Inspect() != "[Error]: generator abandoned , its session is closed" {
+			t.Errorf("expected an error once the session is closed , got %v %t", value, done)
+		}
+	}
+	if err := g.Close(); err != nil {
+		t.Errorf("closing an abandoned generator should do nothing , got %s", err.Inspect())
+	}
+}
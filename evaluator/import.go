@@ -0,0 +1,94 @@
+package evaluator
+
+import (
+	"khanhanh_lang/ast"
+	"khanhanh_lang/lexer"
+	"khanhanh_lang/object"
+	"khanhanh_lang/parser"
+	"path/filepath"
+	"strings"
+)
+
+// IMPORT
+// ---------------------------------------------------------------------------------
+// A file is read through the host filesystem , so an import can't leave the sandbox either
+// It is evaluated once per run in its own tracker , every top level name become a member of the module
+// A relative path start at the directory of the importing file , ".kh" is added when the path has no extension
+
+const sourceExt = ".kh"
+
+func evalImport(pathNode ast.Expression, tracker *object.Tracker) object.Object {
+	pathObj := Eval(pathNode, tracker)
+	if isError(pathObj) {
+		return pathObj
+	}
+	str, ok := pathObj.(*object.String)
+	if !ok {
+		return newError("[Error]: import path must be STRING, got=%s", pathObj.Type())
+	}
+	fs, err := hostFS(tracker.Host())
+	if err != nil {
+		return hostError("import", err)
+	}
+
+	path := str.Value
+	if filepath.Ext(path) == "" {
+		path += sourceExt
+	}
+	if !filepath.IsAbs(path) && tracker.File() != "" {
+		path = filepath.Join(filepath.Dir(tracker.File()), path)
+	}
+	path, err = fs.Resolve(path)
+	if err != nil {
+		return hostError("import", err)
+	}
+
+	chain := tracker.ImportChain()
+	for i, file := range chain {
+		if file == path {
+			cycle := make([]string, 0, len(chain)-i+1)
+			for _, file := range chain[i:] {
+				cycle = append(cycle, fs.Rel(file))
+			}
+			cycle = append(cycle, fs.Rel(path))
+			return newError("[Error]: Circular import: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	if module, ok := tracker.Module(path); ok {
+		return module
+	}
+
+	src, err := fs.ReadFile(path)
+	if err != nil {
+		return hostError("import", err)
+	}
+	p := parser.New(lexer.New(string(src)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return newError("[Error]: import %s: %s", fs.Rel(path), strings.Join(p.Errors(), " ; "))
+	}
+
+	fileTracker := object.NewFileTracker(tracker, path)
+	if result, ok := Eval(program, fileTracker).(*object.Error); ok {
+		return moduleError(fs.Rel(path), result)
+	}
+	module := &object.Module{Name: moduleName(path), Members: map[string]object.Object{}}
+	for _, name := range fileTracker.Names() {
+		module.Members[name], _ = fileTracker.Get(name)
+	}
+	tracker.SetModule(path, module)
+	return module
+}
+
+// an error raised while evaluating an imported file , located in that file
+func moduleError(file string, err *object.Error) *object.Error {
+	// already located by a nested import
+	if strings.HasPrefix(err.Message, "[Error]: Circular import") || err.Pos.Line == 0 {
+		return newError("%s", err.Message)
+	}
+	return newError("[Error]: %s:%d:%d: %s", file, err.Pos.Line, err.Pos.Column, strings.TrimPrefix(err.Message, "[Error]: "))
+}
+
+func moduleName(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
@@ -0,0 +1,133 @@
+package evaluator
+
+import (
+	"khanhanh_lang/host"
+	"khanhanh_lang/lexer"
+	"khanhanh_lang/object"
+	"khanhanh_lang/parser"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// write every file under a new root directory
+func writeFiles(t *testing.T, files map[string]string) string {
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// evaluate the file main.kh of root , with root as the only shared directory
+func evalMain(t *testing.T, root string) object.Object {
+	src, err := os.ReadFile(filepath.Join(root, "main.kh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker := object.NewTracker()
+	tracker.SetHost(&host.Host{FS: &host.FS{Roots: []string{root}}})
+	tracker.SetFile(filepath.Join(root, "main.kh"))
+	return Eval(parser.New(lexer.New(string(src))).ParseProgram(), tracker)
+}
+
+func TestImport(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"main.kh": `
+		import "lib/list";
+		let again = import("lib/" + "list.kh");
+		[list.twice(3), again.name, list.id, again.id]`,
+		"lib/list.kh": `
+		let util = import("../util");
+		let id = math.rand_int(1000000000);
+		let name = "list";
+		let twice = func(x) { util.double(x) }`,
+		"util.kh": `let double = func(x) { x * 2 }`,
+	})
+	result := evalMain(t, root)
+	array, ok := result.(*object.Array)
+	if !ok || len(array.Elements) != 4 {
+		t.Fatalf("wrong result. got=%s", result.Inspect())
+	}
+	testTypeObject(t, array.Elements[0], int64(6))
+	testTypeObject(t, array.Elements[1], "list")
+	// the file is evaluated once , both imports give the same module
+	if array.Elements[2].Inspect() != array.Elements[3].Inspect() {
+		t.Errorf("the module should be evaluated once. got=%s and %s", array.Elements[2].Inspect(), array.Elements[3].Inspect())
+	}
+}
+
+func TestImportScope(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"main.kh": `let secret = 1; import "a"; a.get()`,
+		"a.kh":    `let get = func() { secret }`,
+	})
+	result, ok := evalMain(t, root).(*object.Error)
+	if !ok || !strings.Contains(result.Message, "Identifier not found: secret") {
+		t.Errorf("an imported file should not see the names of the importer. got=%v", result)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	tests := []struct {
+		files  map[string]string
+		expect string
+	}{
+		{
+			map[string]string{"main.kh": `import "a"`, "a.kh": `import "b"`, "b.kh": `import "lib/c"`, "lib/c.kh": `import "../a"`},
+			"[Error]: Circular import: a.kh -> b.kh -> lib/c.kh -> a.kh",
+		},
+		{
+			map[string]string{"main.kh": `import "main"`},
+			"[Error]: Circular import: main.kh -> main.kh",
+		},
+		{
+			map[string]string{"main.kh": `import "bad"`, "bad.kh": "let a = 1;\nlet b = a + true"},
+			"[Error]: bad.kh:2:11: Mismatch INTEGER + BOOLEAN",
+		},
+		{
+			map[string]string{"main.kh": `import "bad"`, "bad.kh": "let a = ;"},
+			"[Error]: import bad.kh: 1:9: ",
+		},
+		{
+			map[string]string{"main.kh": `import "missing"`},
+			"[Error]: import: ",
+		},
+		{
+			map[string]string{"main.kh": `import("../outside")`},
+			"outside the shared directories",
+		},
+		{
+			map[string]string{"main.kh": `import(1)`},
+			"[Error]: import path must be STRING, got=INTEGER",
+		},
+		{
+			map[string]string{"main.kh": `import "a"; a.missing`, "a.kh": `let b = 1`},
+			"[Error]: Module a has no member missing",
+		},
+	}
+	for _, tt := range tests {
+		result, ok := evalMain(t, writeFiles(t, tt.files)).(*object.Error)
+		if !ok {
+			t.Errorf("%s should fail", tt.files["main.kh"])
+			continue
+		}
+		if !strings.Contains(result.Message, tt.expect) {
+			t.Errorf("%s wrong error. expect=%q got=%q", tt.files["main.kh"], tt.expect, result.Message)
+		}
+	}
+}
+
+func TestImportWithoutFilesystem(t *testing.T) {
+	result, ok := evalWithHost(`import "a"`, &host.Host{}).(*object.Error)
+	if !ok || result.Message != "[Error]: import: not allowed by the host" {
+		t.Errorf("import should need a filesystem. got=%v", result)
+	}
+}
@@ -0,0 +1,154 @@
+package evaluator
+
+import (
+	"errors"
+	"io"
+	"khanhanh_lang/host"
+	"khanhanh_lang/object"
+	"strings"
+)
+
+// IO BUILTINS
+// ---------------------------------------------------------------------------------
+// Everything a script read or write outside the interpreter go through the Host of its tracker ,
+// set by the embedding program. A tracker without Host can't print , read nor touch a file
+
+// build each builtin for the Host of the tracker looking it up
+var hostBuiltins = map[string]func(h *host.Host) object.BuiltinFunction{
+	"print":      hostPrint,
+	"eprint":     hostEprint,
+	"read_line":  hostReadLine,
+	"read_file":  hostReadFile,
+	"write_file": hostWriteFile,
+	"list_dir":   hostListDir,
+}
+
+func hostBuiltin(name string, h *host.Host) (object.Object, bool) {
+	build, ok := hostBuiltins[name]
+	if !ok {
+		return nil, false
+	}
+	return &object.Builtin{Name: name, Fn: build(h)}, true
+}
+
+func hostError(name string, err error) *object.Error {
+	return newError("[Error]: %s: %s", name, err)
+}
+
+var errNoHost = errors.New("the script has no host")
+
+// print(a, b, ...) write its arguments separated by a space , then a new line
+// strings are written as is , other values as they inspect
+func hostPrint(h *host.Host) object.BuiltinFunction {
+	return func(args ...object.Object) object.Object {
+		return printTo("print", h, args, (*host.Host).Print)
+	}
+}
+
+// eprint is print on the error output
+func hostEprint(h *host.Host) object.BuiltinFunction {
+	return func(args ...object.Object) object.Object {
+		return printTo("eprint", h, args, (*host.Host).Eprint)
+	}
+}
+
+func printTo(name string, h *host.Host, args []object.Object, write func(*host.Host, string) error) object.Object {
+	if h == nil {
+		return hostError(name, errNoHost)
+	}
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		if str, ok := arg.(*object.String); ok {
+			parts[i] = str.Value
+		} else {
+			parts[i] = arg.Inspect()
+		}
+	}
+	if err := write(h, strings.Join(parts, " ")+"\n"); err != nil {
+		return hostError(name, err)
+	}
+	return NIL
+}
+
+// read_line() is the next line of the input without its line ending , nil once the input is exhausted
+func hostReadLine(h *host.Host) object.BuiltinFunction {
+	return func(args ...object.Object) object.Object {
+		if err := checkArgs("read_line", args, 0); err != nil {
+			return err
+		}
+		if h == nil {
+			return hostError("read_line", errNoHost)
+		}
+		line, err := h.ReadLine()
+		if err == io.EOF {
+			return NIL
+		}
+		if err != nil {
+			return hostError("read_line", err)
+		}
+		return &object.String{Value: line}
+	}
+}
+
+func hostFS(h *host.Host) (*host.FS, error) {
+	if h == nil {
+		return nil, errNoHost
+	}
+	if h.FS == nil {
+		return nil, host.ErrNotAllowed
+	}
+	return h.FS, nil
+}
+
+// read_file(path) is the content of the file
+func hostReadFile(h *host.Host) object.BuiltinFunction {
+	return func(args ...object.Object) object.Object {
+		if err := checkArgs("read_file", args, 1, object.STRING_OBJ); err != nil {
+			return err
+		}
+		fs, err := hostFS(h)
+		if err != nil {
+			return hostError("read_file", err)
+		}
+		data, err := fs.ReadFile(stringArg(args, 0))
+		if err != nil {
+			return hostError("read_file", err)
+		}
+		return &object.String{Value: string(data)}
+	}
+}
+
+// write_file(path, content) create or replace the file
+func hostWriteFile(h *host.Host) object.BuiltinFunction {
+	return func(args ...object.Object) object.Object {
+		if err := checkArgs("write_file", args, 2, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+			return err
+		}
+		fs, err := hostFS(h)
+		if err != nil {
+			return hostError("write_file", err)
+		}
+		if err := fs.WriteFile(stringArg(args, 0), []byte(stringArg(args, 1))); err != nil {
+			return hostError("write_file", err)
+		}
+		return NIL
+	}
+}
+
+// list_dir(path) is the sorted array of names in the directory , sub directories end with a slash
+func hostListDir(h *host.Host) object.BuiltinFunction {
+	return func(args ...object.Object) object.Object {
+		if err := checkArgs("list_dir", args, 1, object.STRING_OBJ); err != nil {
+			return err
+		}
+		fs, err := hostFS(h)
+		if err != nil {
+			return hostError("list_dir", err)
+		}
+		names, err := fs.ReadDir(stringArg(args, 0))
+		if err != nil {
+			return hostError("list_dir", err)
+		}
+		return newStringArray(names)
+	}
+}
@@ -0,0 +1,89 @@
+package evaluator
+
+import (
+	"bytes"
+	"khanhanh_lang/host"
+	"khanhanh_lang/lexer"
+	"khanhanh_lang/object"
+	"khanhanh_lang/parser"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func evalWithHost(input string, h *host.Host) object.Object {
+	tracker := object.NewTracker()
+	tracker.SetHost(h)
+	return Eval(parser.New(lexer.New(input)).ParseProgram(), tracker)
+}
+
+func TestPrintAndReadLine(t *testing.T) {
+	var out, errs bytes.Buffer
+	h := &host.Host{Stdin: strings.NewReader("first\r\nsecond"), Stdout: &out, Stderr: &errs}
+	input := `
+	print("a", 1, [1, "b"], true);
+	print();
+	eprint("warn");
+	let f = func(x) { print(x) };
+	each(["x", "y"], f);
+	[read_line(), read_line(), read_line()]
+	`
+	result := evalWithHost(input, h)
+	if result.Inspect() != `["first", "second", nil]` {
+		t.Errorf("wrong lines. got=%s", result.Inspect())
+	}
+	if out.String() != "a 1 [1, \"b\"] true\n\nx\ny\n" {
+		t.Errorf("wrong output. got=%q", out.String())
+	}
+	if errs.String() != "warn\n" {
+		t.Errorf("wrong error output. got=%q", errs.String())
+	}
+}
+
+func TestFileBuiltins(t *testing.T) {
+	root := t.TempDir()
+	h := &host.Host{FS: &host.FS{Roots: []string{root}}}
+	input := `
+	write_file("a.txt", "hello");
+	[read_file("a.txt"), list_dir(".")]
+	`
+	result := evalWithHost(input, h)
+	if result.Inspect() != `["hello", ["a.txt"]]` {
+		t.Errorf("wrong result. got=%s", result.Inspect())
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "a.txt")); string(data) != "hello" {
+		t.Errorf("wrong file content. got=%q", data)
+	}
+}
+
+func TestIOWithoutCapability(t *testing.T) {
+	root := t.TempDir()
+	tests := []struct {
+		input  string
+		host   *host.Host
+		expect string
+	}{
+		{`print("hi")`, nil, "[Error]: print: the script has no host"},
+		{`read_line()`, &host.Host{}, "[Error]: read_line: not allowed by the host"},
+		{`read_file("a")`, &host.Host{}, "[Error]: read_file: not allowed by the host"},
+		{`read_file("../a")`, &host.Host{FS: &host.FS{Roots: []string{root}}}, "outside the shared directories"},
+		{`write_file("a", "b")`, &host.Host{FS: &host.FS{Roots: []string{root}, ReadOnly: true}}, "the filesystem is read only"},
+		{`list_dir(1)`, &host.Host{}, "[Error]: argument 1 of list_dir must be STRING, got=INTEGER"},
+	}
+	for _, tt := range tests {
+		result, ok := evalWithHost(tt.input, tt.host).(*object.Error)
+		if !ok {
+			t.Errorf("%s should fail", tt.input)
+			continue
+		}
+		if !strings.Contains(result.Message, tt.expect) {
+			t.Errorf("%s wrong error. expect=%q got=%q", tt.input, tt.expect, result.Message)
+		}
+	}
+}
+
+func TestIOShadowing(t *testing.T) {
+	result := evalWithHost(`let print = func(x) { x * 2 }; print(2)`, nil)
+	testTypeObject(t, result, int64(4))
+}
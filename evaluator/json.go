@@ -0,0 +1,226 @@
+package evaluator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"khanhanh_lang/object"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// JSON BUILTINS
+// ---------------------------------------------------------------------------------
+// JSON objects become hashes , arrays become arrays and null become nil
+// Hashes keep their order both ways , so parse then stringify give back the same document
+
+func init() {
+	register("json_parse", builtinJSONParse)
+	register("json_stringify", builtinJSONStringify)
+}
+
+// PARSE
+// ---------------------------------------------------------------------------------
+func builtinJSONParse(args ...object.Object) object.Object {
+	if err := checkArgs("json_parse", args, 1, object.STRING_OBJ); err != nil {
+		return err
+	}
+	dec := json.NewDecoder(strings.NewReader(stringArg(args, 0)))
+	dec.UseNumber()
+	value, err := decodeJSON(dec)
+	if err == nil {
+		if _, next := dec.Token(); next != io.EOF {
+			err = fmt.Errorf("unexpected data after the value at byte %d", dec.InputOffset())
+		}
+	}
+	if err != nil {
+		var syntax *json.SyntaxError
+		if errors.As(err, &syntax) {
+			err = fmt.Errorf("%s at byte %d", syntax, syntax.Offset)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			err = errors.New("unexpected end of input")
+		}
+		return newError("[Error]: json_parse: %s", err)
+	}
+	return value
+}
+
+// decode the next value of dec , reading object and array tokens one by one to keep the key order
+func decodeJSON(dec *json.Decoder) (object.Object, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok := tok.(type) {
+	case json.Delim:
+		if tok == '[' {
+			elements := []object.Object{}
+			for dec.More() {
+				el, err := decodeJSON(dec)
+				if err != nil {
+					return nil, err
+				}
+				elements = append(elements, el)
+			}
+			_, err := dec.Token() // ]
+			return &object.Array{Elements: elements}, err
+		}
+		hash := object.NewHash()
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			hash.Set(&object.String{Value: key.(string)}, value)
+		}
+		_, err := dec.Token() // }
+		return hash, err
+	case string:
+		return &object.String{Value: tok}, nil
+	case json.Number:
+		if n, err := strconv.ParseInt(string(tok), 10, 64); err == nil {
+			return &object.Integer{Value: n}, nil
+		}
+		f, err := strconv.ParseFloat(string(tok), 64)
+		if err != nil {
+			return nil, fmt.Errorf("number %s out of range", tok)
+		}
+		return &object.Float{Value: f}, nil
+	case bool:
+		return nativeBool(tok), nil
+	default:
+		return NIL, nil
+	}
+}
+
+// STRINGIFY
+// ---------------------------------------------------------------------------------
+// json_stringify(value) is compact , json_stringify(value, indent) put each element on its own line ,
+// indent is a number of spaces or the string to indent with , at most maxJSONIndent long
+func builtinJSONStringify(args ...object.Object) object.Object {
+	if err := checkArgs("json_stringify", args, 1, ANY_OBJ, ANY_OBJ); err != nil {
+		return err
+	}
+	indent := ""
+	if len(args) == 2 {
+		switch arg := args[1].(type) {
+		case *object.Integer:
+			if arg.Value < 0 {
+				return newError("[Error]: json_stringify indent must not be negative, got=%d", arg.Value)
+			}
+			if arg.Value > maxJSONIndent {
+				return newError("[Error]: json_stringify indent must be at most %d, got=%d", maxJSONIndent, arg.Value)
+			}
+			indent = strings.Repeat(" ", int(arg.Value))
+		case *object.String:
+			if n := utf8.RuneCountInString(arg.Value); n > maxJSONIndent {
+				return newError("[Error]: json_stringify indent must be at most %d characters, got=%d", maxJSONIndent, n)
+			}
+			indent = arg.Value
+		default:
+			return newError("[Error]: argument 2 of json_stringify must be INTEGER or STRING, got=%s", arg.Type())
+		}
+	}
+
+	enc := &jsonEncoder{visiting: map[object.Object]bool{}}
+	if err := enc.encode(args[0]); err != nil {
+		return newError("[Error]: json_stringify: %s", err)
+	}
+	if indent == "" {
+		return &object.String{Value: enc.buf.String()}
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, enc.buf.Bytes(), "", indent); err != nil {
+		return newError("[Error]: json_stringify: %s", err)
+	}
+	return &object.String{Value: out.String()}
+}
+
+// the widest indent , each level of nesting repeat it
+const maxJSONIndent = 10
+
+type jsonEncoder struct {
+	buf      bytes.Buffer
+	visiting map[object.Object]bool // arrays and hashes being encoded , to detect a value containing itself
+}
+
+func (enc *jsonEncoder) encode(obj object.Object) error {
+	switch obj := obj.(type) {
+	case *object.Nil:
+		enc.buf.WriteString("null")
+	case *object.Boolean:
+		enc.buf.WriteString(strconv.FormatBool(obj.Value))
+	case *object.Integer:
+		enc.buf.WriteString(strconv.FormatInt(obj.Value, 10))
+	case *object.Float:
+		if math.IsNaN(obj.Value) || math.IsInf(obj.Value, 0) {
+			return fmt.Errorf("cannot encode float %s", obj.Inspect())
+		}
+		enc.buf.WriteString(obj.Inspect())
+	case *object.String:
+		enc.buf.WriteString(quoteJSON(obj.Value))
+	case *object.Array:
+		if err := enc.enter(obj); err != nil {
+			return err
+		}
+		enc.buf.WriteByte('[')
+		for i, el := range obj.Elements {
+			if i > 0 {
+				enc.buf.WriteByte(',')
+			}
+			if err := enc.encode(el); err != nil {
+				return err
+			}
+		}
+		enc.buf.WriteByte(']')
+		delete(enc.visiting, obj)
+	case *object.Hash:
+		if err := enc.enter(obj); err != nil {
+			return err
+		}
+		enc.buf.WriteByte('{')
+		for i, pair := range obj.Pairs() {
+			if i > 0 {
+				enc.buf.WriteByte(',')
+			}
+			// JSON keys are strings , other keys are written as they print
+			key := pair.Key.Inspect()
+			enc.buf.WriteString(quoteJSON(key))
+			enc.buf.WriteByte(':')
+			if err := enc.encode(pair.Value); err != nil {
+				return err
+			}
+		}
+		enc.buf.WriteByte('}')
+		delete(enc.visiting, obj)
+	default:
+		return fmt.Errorf("cannot encode %s", obj.Type())
+	}
+	return nil
+}
+
+func (enc *jsonEncoder) enter(obj object.Object) error {
+	if enc.visiting[obj] {
+		return fmt.Errorf("cycle detected , the %s contains itself", obj.Type())
+	}
+	enc.visiting[obj] = true
+	return nil
+}
+
+// JSON string , without escaping <, > and & as encoding/json does by default
+func quoteJSON(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
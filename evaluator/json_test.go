@@ -0,0 +1,125 @@
+package evaluator
+
+import (
+	"khanhanh_lang/object"
+	"strings"
+	"testing"
+)
+
+func TestJSONParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"b": 1, "a": [true, null, 1.5, "x"], "c": {}}`, `{"b": 1, "a": [true, nil, 1.5, "x"], "c": {}}`},
+		{`[]`, `[]`},
+		{` 42 `, `42`},
+		{`-1.25e2`, `-125.0`},
+		{`"hé"`, `hé`},
+		{`{"a": 1, "a": 2}`, `{"a": 2}`},
+		{`99999999999999999999`, `1e+20`},
+	}
+
+	for _, test := range tests {
+		got := evalWith("json_parse(s)", map[string]string{"s": test.input})
+		if got.Type() == object.ERROR_OBJ {
+			t.Errorf("%s unexpected error: %s", test.input, got.Inspect())
+			continue
+		}
+		if got.Inspect() != test.expected {
+			t.Errorf("%s wrong value. expected=%s , got=%s", test.input, test.expected, got.Inspect())
+		}
+	}
+	testTypeObject(t, testEval(`json_parse("[1, 2]")[1]`), int64(2))
+}
+
+func TestJSONParseErrors(t *testing.T) {
+	// the wording of a syntax error come from encoding/json , only its position is checked
+	tests := []struct {
+		input  string
+		prefix string
+		suffix string
+	}{
+		{`{"a": }`, "[Error]: json_parse: ", "at byte 7"},
+		{`[1, x]`, "[Error]: json_parse: invalid character 'x'", "at byte 5"},
+		{`[1, 2`, "[Error]: json_parse: ", "at byte 5"},
+		{`1 2`, "[Error]: json_parse: unexpected data after the value at byte 3", ""},
+		{``, "[Error]: json_parse: unexpected end of input", ""},
+	}
+
+	for _, test := range tests {
+		err, ok := evalWith("json_parse(s)", map[string]string{"s": test.input}).(*object.Error)
+		if !ok {
+			t.Errorf("%q should evaluate to an error", test.input)
+			continue
+		}
+		if !strings.HasPrefix(err.Message, test.prefix) || !strings.HasSuffix(err.Message, test.suffix) {
+			t.Errorf("%q wrong error. expected=%q...%q , got=%q", test.input, test.prefix, test.suffix, err.Message)
+		}
+	}
+}
+
+func TestJSONStringify(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`json_stringify({"b": 1, "a": [true, 1.5, "<x>"], 3: {}})`, `{"b":1,"a":[true,1.5,"<x>"],"3":{}}`},
+		{`json_stringify([])`, `[]`},
+		{`json_stringify(2.0)`, `2.0`},
+		{`json_stringify(if (false) { 1 })`, `null`},
+		{`json_stringify({"a": [1, 2]}, 2)`, "{\n  \"a\": [\n    1,\n    2\n  ]\n}"},
+		{`json_stringify([1], 10)`, "[\n          1\n]"},
+	}
+
+	for _, test := range tests {
+		got := testEval(test.input)
+		testTypeObject(t, got, test.expected)
+	}
+
+	testTypeObject(t, evalWith("json_stringify([1], tab)", map[string]string{"tab": "\t"}), "[\n\t1\n]")
+
+	// order is kept through a round trip
+	doc := `{"z":1,"y":[{"b":null,"a":"s"}],"x":0.5}`
+	testTypeObject(t, evalWith("json_stringify(json_parse(s))", map[string]string{"s": doc}), doc)
+}
+
+func TestJSONStringifyErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`json_stringify(func(x) { x })`, "[Error]: json_stringify: cannot encode FUNCTION"},
+		{`json_stringify({"f": [strings.upper]})`, "[Error]: json_stringify: cannot encode BUILTIN"},
+		{`json_stringify(math)`, "[Error]: json_stringify: cannot encode MODULE"},
+		{`json_stringify(1, -1)`, "[Error]: json_stringify indent must not be negative, got=-1"},
+		{`json_stringify(1, 11)`, "[Error]: json_stringify indent must be at most 10, got=11"},
+		{`json_stringify(1, 9223372036854775807)`, "[Error]: json_stringify indent must be at most 10, got=9223372036854775807"},
+		{`json_stringify(1, "ééééééééééé")`, "[Error]: json_stringify indent must be at most 10 characters, got=11"},
+		{`json_stringify(1, true)`, "[Error]: argument 2 of json_stringify must be INTEGER or STRING, got=BOOLEAN"},
+	}
+
+	for _, test := range tests {
+		err, ok := testEval(test.input).(*object.Error)
+		if !ok {
+			t.Errorf("%q should evaluate to an error", test.input)
+			continue
+		}
+		if err.Message != test.expected {
+			t.Errorf("%q wrong error. expected=%q , got=%q", test.input, test.expected, err.Message)
+		}
+	}
+
+	// a value containing itself can only be built from Go for now
+	array := &object.Array{}
+	hash := object.NewHash()
+	hash.Set(&object.String{Value: "self"}, array)
+	array.Elements = []object.Object{hash}
+	err, ok := builtinJSONStringify(array).(*object.Error)
+	if !ok || err.Message != "[Error]: json_stringify: cycle detected , the ARRAY contains itself" {
+		t.Errorf("cycle should be reported. got=%v", builtinJSONStringify(array))
+	}
+	// the same value twice is not a cycle
+	shared := &object.Array{Elements: []object.Object{&object.Integer{Value: 1}}}
+	testTypeObject(t, builtinJSONStringify(&object.Array{Elements: []object.Object{shared, shared}}), "[[1],[1]]")
+}
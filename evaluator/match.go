@@ -0,0 +1,134 @@
+package evaluator
+
+import (
+	"khanhanh_lang/ast"
+	"khanhanh_lang/object"
+)
+
+// MATCH
+// ---------------------------------------------------------------------------------
+// The arms are tried in order , the first whose pattern match the subject and whose guard is truthy
+// give the value of the match . Each arm bind its names in its own tracker , so a failed arm leave nothing behind
+// A literal match a value of the same type that is equal to it ( 1 and 1.0 are both numbers and match ) ,
+// _ match anything , a name match anything and bind it , an array pattern match an array of the same length
+// ( at least as long with ...rest ) and a hash pattern match a hash having every key of the pattern
+
+func evalMatchExpression(node *ast.MatchExpression, tracker *object.Tracker) object.Object {
+	subject := Eval(node.Subject, tracker)
+	if isError(subject) {
+		return subject
+	}
+	for _, arm := range node.Arms {
+		armTracker := object.NewEnclosedTracker(tracker)
+		matched, err := matchPattern(arm.Pattern, subject, armTracker)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			continue
+		}
+		if arm.Guard != nil {
+			guard := Eval(arm.Guard, armTracker)
+			if isError(guard) {
+				return guard
+			}
+			if !isTruthy(guard) {
+				continue
+			}
+		}
+		result := Eval(arm.Body, armTracker)
+		if result == nil {
+			return NIL
+		}
+		return result
+	}
+	return atPosition(newError("[Error]: No match for %s", subject.Inspect()), node.Token)
+}
+
+// check value against pattern , binding the names of the pattern into tracker
+// the error is only set when evaluating a literal of the pattern failed
+func matchPattern(pattern ast.Expression, value object.Object, tracker *object.Tracker) (bool, *object.Error) {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		if pattern.Value != "_" {
+			tracker.Set(pattern.Value, value)
+		}
+		return true, nil
+	case *ast.ArrayLiteral:
+		return matchArray(pattern, value, tracker)
+	case *ast.HashLiteral:
+		return matchHash(pattern, value, tracker)
+	default:
+		literal := Eval(pattern, tracker)
+		if err, ok := literal.(*object.Error); ok {
+			return false, err
+		}
+		return sameKind(literal, value) && equal(literal, value), nil
+	}
+}
+
+func matchArray(pattern *ast.ArrayLiteral, value object.Object, tracker *object.Tracker) (bool, *object.Error) {
+	array, ok := value.(*object.Array)
+	if !ok {
+		return false, nil
+	}
+	elements := pattern.Elements
+	var rest *ast.SpreadExpression
+	if len(elements) > 0 {
+		rest, _ = elements[len(elements)-1].(*ast.SpreadExpression)
+	}
+	if rest != nil {
+		elements = elements[:len(elements)-1]
+		if len(array.Elements) < len(elements) {
+			return false, nil
+		}
+	} else if len(array.Elements) != len(elements) {
+		return false, nil
+	}
+	for i, el := range elements {
+		matched, err := matchPattern(el, array.Elements[i], tracker)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	if rest != nil {
+		remaining := make([]object.Object, len(array.Elements)-len(elements))
+		copy(remaining, array.Elements[len(elements):])
+		return matchPattern(rest.Value, &object.Array{Elements: remaining}, tracker)
+	}
+	return true, nil
+}
+
+func matchHash(pattern *ast.HashLiteral, value object.Object, tracker *object.Tracker) (bool, *object.Error) {
+	hash, ok := value.(*object.Hash)
+	if !ok {
+		return false, nil
+	}
+	for i, keyNode := range pattern.Keys {
+		key := Eval(keyNode, tracker)
+		if err, ok := key.(*object.Error); ok {
+			return false, err
+		}
+		hashable, ok := key.(object.Hashable)
+		if !ok {
+			return false, nil
+		}
+		found, ok := hash.Get(hashable)
+		if !ok {
+			return false, nil
+		}
+		matched, err := matchPattern(pattern.Values[i], found, tracker)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// a literal pattern only match a value of its own type , except that integers and floats are all numbers
+func sameKind(left, right object.Object) bool {
+	if left.Type() == right.Type() {
+		return true
+	}
+	return isNumber(left) && isNumber(right)
+}
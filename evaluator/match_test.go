@@ -0,0 +1,58 @@
+package evaluator
+
+import (
+	"testing"
+)
+
+func TestMatchExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		// literals
+		{`match 0 { 0 => "zero", _ => "other" }`, "zero"},
+		{`match 5 { 0 => "zero", _ => "other" }`, "other"},
+		{`match "a" { "a" => 1, "b" => 2 }`, int64(1)},
+		{`match -1 { -1 => "minus one", _ => "other" }`, "minus one"},
+		{`match 2.0 { 2 => "two", _ => "other" }`, "two"},
+		{`match true { true => 1, false => 2 }`, int64(1)},
+		// a literal only match its own type
+		{`match "1" { 1 => "number", _ => "other" }`, "other"},
+		{`match 1 { true => "bool", _ => "other" }`, "other"},
+		// binding
+		{`match 7 { n => n * 2 }`, int64(14)},
+		{`let n = 1; match 7 { n => n }; n`, int64(1)},
+		// guards
+		{`match 20 { n if n > 10 => "big", n => "small" }`, "big"},
+		{`match 3 { n if n > 10 => "big", n => "small" }`, "small"},
+		{`match 3 { _ if false => 1, _ => 2 }`, int64(2)},
+		// arrays
+		{`match [1, 2, 3] { [] => "empty", [x, ...rest] => rest }`, "[2, 3]"},
+		{`match [] { [] => "empty", [x, ...rest] => rest }`, "empty"},
+		{`match [1] { [x, ...rest] => rest }`, "[]"},
+		{`match [1, 2] { [a] => "one", [a, b] => a + b }`, int64(3)},
+		{`match [1, [2, 3]] { [a, [b, c]] => a + b + c }`, int64(6)},
+		{`match [0, 5] { [1, x] => x, [0, x] => -x }`, int64(-5)},
+		{`match "ab" { [a, b] => "array", _ => "other" }`, "other"},
+		// hashes
+		{`match {"k": 1, "other": 2} { {"k": v} => v }`, int64(1)},
+		{`match {"other": 2} { {"k": v} => v, _ => "no k" }`, "no k"},
+		{`match {"k": 1} { {"k": 2} => "two", {"k": 1} => "one" }`, "one"},
+		{`match {"point": [1, 2]} { {"point": [x, y]} => x + y }`, int64(3)},
+		// block bodies and return
+		{`match 1 { 1 => { let a = 2; a * 3 } }`, int64(6)},
+		{`let f = func(x) { match x { 0 => { return "early" }, _ => 1 }; "late" }; f(0)`, "early"},
+		{`match 1 { 1 => {} }`, nil},
+		// names bound by an arm do not leak
+		{`match [1] { [leaked] => 1 }; leaked`, ErrorMesssage("[Error]: Identifier not found: leaked")},
+		// a failed arm leave nothing behind for the next one
+		{`match [1, 2] { [a, 3] => 1, [b, 2] => a }`, ErrorMesssage("[Error]: Identifier not found: a")},
+		{`match 3 { 1 => "one", 2 => "two" }`, ErrorMesssage("[Error]: No match for 3")},
+		{`match missing { _ => 1 }`, ErrorMesssage("[Error]: Identifier not found: missing")},
+		{`match 1 { n if n + true => 1 }`, ErrorMesssage("[Error]: Mismatch INTEGER + BOOLEAN")},
+		{`[...[1]]`, ErrorMesssage("[Error]: ... is only allowed in a call, a parameter list or an array pattern")},
+	}
+	for _, tt := range tests {
+		testResult(t, tt.input, tt.expected)
+	}
+}
@@ -0,0 +1,263 @@
+package evaluator
+
+import (
+	"khanhanh_lang/object"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// MATH MODULE
+// ---------------------------------------------------------------------------------
+// Functions accept every numeric type , an integer stay an integer when the result can be one
+// ( abs(-2) is 2 , abs(-2.5) is 2.5 ) , floor , ceil and round always give an integer
+
+// source of rand_int and rand_float , seed(n) replace it so a script can be replayed
+// the scripts of several sessions can run at once , every use hold randomLock
+var (
+	randomLock sync.Mutex
+	random     = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+func init() {
+	module := newModule("math", map[string]object.BuiltinFunction{
+		"abs":        mathAbs,
+		"min":        mathMin,
+		"max":        mathMax,
+		"pow":        mathPow,
+		"sqrt":       mathSqrt,
+		"floor":      mathFloor,
+		"ceil":       mathCeil,
+		"round":      mathRound,
+		"clamp":      mathClamp,
+		"gcd":        mathGcd,
+		"rand_int":   mathRandInt,
+		"rand_float": mathRandFloat,
+		"seed":       mathSeed,
+	})
+	module.Members["PI"] = &object.Float{Value: math.Pi}
+	module.Members["E"] = &object.Float{Value: math.E}
+	builtins["math"] = module
+}
+
+func intArg(args []object.Object, i int) int64 {
+	return args[i].(*object.Integer).Value
+}
+
+func allIntegers(args []object.Object) bool {
+	for _, arg := range args {
+		if arg.Type() != object.INTEGER_OBJ {
+			return false
+		}
+	}
+	return true
+}
+
+func mathAbs(args ...object.Object) object.Object {
+	if err := checkArgs("math.abs", args, 1, NUMBER_OBJ); err != nil {
+		return err
+	}
+	if n, ok := args[0].(*object.Integer); ok {
+		if n.Value == math.MinInt64 {
+			return newError("[Error]: math.abs result does not fit an integer: %d", n.Value)
+		}
+		if n.Value < 0 {
+			return &object.Integer{Value: -n.Value}
+		}
+		return n
+	}
+	return &object.Float{Value: math.Abs(toFloat(args[0]))}
+}
+
+// min and max take at least one number , or a single array of numbers , and give back the winner unchanged
+func mathMin(args ...object.Object) object.Object {
+	return extremum("math.min", args, func(a, b float64) bool { return a < b })
+}
+
+func mathMax(args ...object.Object) object.Object {
+	return extremum("math.max", args, func(a, b float64) bool { return a > b })
+}
+
+func extremum(name string, args []object.Object, better func(a, b float64) bool) object.Object {
+	if len(args) == 1 {
+		if array, ok := args[0].(*object.Array); ok {
+			args = array.Elements
+		}
+	}
+	if len(args) == 0 {
+		return newError("[Error]: %s expect at least 1 number, got=0", name)
+	}
+	var result object.Object
+	for i, arg := range args {
+		if !isNumber(arg) {
+			return newError("[Error]: argument %d of %s must be NUMBER, got=%s", i+1, name, arg.Type())
+		}
+		if result == nil || better(toFloat(arg), toFloat(result)) {
+			result = arg
+		}
+	}
+	return result
+}
+
+// pow of two integers with a non negative exponent is an integer , or an error when it does not fit one , a float otherwise
+func mathPow(args ...object.Object) object.Object {
+	if err := checkArgs("math.pow", args, 2, NUMBER_OBJ, NUMBER_OBJ); err != nil {
+		return err
+	}
+	if allIntegers(args) && intArg(args, 1) >= 0 {
+		base, exp := intArg(args, 0), intArg(args, 1)
+		result, overflow := int64(1), false
+		for exp > 0 && !overflow {
+			if exp&1 == 1 {
+				result, overflow = multiply(result, base)
+			}
+			exp >>= 1
+			// square only while a bit is left , the last square may overflow for nothing
+			if exp > 0 && !overflow {
+				base, overflow = multiply(base, base)
+			}
+		}
+		if overflow {
+			return newError("[Error]: math.pow result does not fit an integer: %d, %d", intArg(args, 0), intArg(args, 1))
+		}
+		return &object.Integer{Value: result}
+	}
+	return &object.Float{Value: math.Pow(toFloat(args[0]), toFloat(args[1]))}
+}
+
+// a * b and whether it overflow
+func multiply(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, false
+	}
+	product := a * b
+	// MinInt64 / -1 is MinInt64 again , the division alone miss it
+	if product/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return product, true
+	}
+	return product, false
+}
+
+func mathSqrt(args ...object.Object) object.Object {
+	if err := checkArgs("math.sqrt", args, 1, NUMBER_OBJ); err != nil {
+		return err
+	}
+	x := toFloat(args[0])
+	if x < 0 {
+		return newError("[Error]: math.sqrt of a negative number: %s", args[0].Inspect())
+	}
+	return &object.Float{Value: math.Sqrt(x)}
+}
+
+func mathFloor(args ...object.Object) object.Object {
+	return toInteger("math.floor", args, math.Floor)
+}
+
+func mathCeil(args ...object.Object) object.Object {
+	return toInteger("math.ceil", args, math.Ceil)
+}
+
+// round half away from zero , round(2.5) is 3 and round(-2.5) is -3
+func mathRound(args ...object.Object) object.Object {
+	return toInteger("math.round", args, math.Round)
+}
+
+func toInteger(name string, args []object.Object, fn func(float64) float64) object.Object {
+	if err := checkArgs(name, args, 1, NUMBER_OBJ); err != nil {
+		return err
+	}
+	if n, ok := args[0].(*object.Integer); ok {
+		return n
+	}
+	x := fn(toFloat(args[0]))
+	if math.IsNaN(x) || x < math.MinInt64 || x >= math.MaxInt64 {
+		return newError("[Error]: %s result does not fit an integer: %s", name, args[0].Inspect())
+	}
+	return &object.Integer{Value: int64(x)}
+}
+
+// clamp(x, low, high) is x kept inside [low, high]
+func mathClamp(args ...object.Object) object.Object {
+	if err := checkArgs("math.clamp", args, 3, NUMBER_OBJ, NUMBER_OBJ, NUMBER_OBJ); err != nil {
+		return err
+	}
+	x, low, high := args[0], args[1], args[2]
+	if toFloat(low) > toFloat(high) {
+		return newError("[Error]: math.clamp low %s is greater than high %s", low.Inspect(), high.Inspect())
+	}
+	result := x
+	if toFloat(x) < toFloat(low) {
+		result = low
+	} else if toFloat(x) > toFloat(high) {
+		result = high
+	}
+	if !allIntegers(args) {
+		return &object.Float{Value: toFloat(result)}
+	}
+	return result
+}
+
+// greatest common divisor , never negative
+func mathGcd(args ...object.Object) object.Object {
+	if err := checkArgs("math.gcd", args, 2, object.INTEGER_OBJ, object.INTEGER_OBJ); err != nil {
+		return err
+	}
+	a, b := intArg(args, 0), intArg(args, 1)
+	for b != 0 {
+		a, b = b, a%b
+	}
+	if a == math.MinInt64 {
+		return newError("[Error]: math.gcd result does not fit an integer: %d, %d", intArg(args, 0), intArg(args, 1))
+	}
+	if a < 0 {
+		a = -a
+	}
+	return &object.Integer{Value: a}
+}
+
+// rand_int(n) is in [0, n) , rand_int(low, high) in [low, high)
+func mathRandInt(args ...object.Object) object.Object {
+	if err := checkArgs("math.rand_int", args, 1, object.INTEGER_OBJ, object.INTEGER_OBJ); err != nil {
+		return err
+	}
+	low, high := int64(0), intArg(args, 0)
+	if len(args) == 2 {
+		low, high = intArg(args, 0), intArg(args, 1)
+	}
+	if low >= high {
+		return newError("[Error]: math.rand_int empty range [%d, %d)", low, high)
+	}
+	randomLock.Lock()
+	defer randomLock.Unlock()
+	// high - low overflow past half of the integers , draw among all of them until one fall in the range
+	if span := uint64(high) - uint64(low); span > math.MaxInt64 {
+		for {
+			if n := int64(random.Uint64()); low <= n && n < high {
+				return &object.Integer{Value: n}
+			}
+		}
+	}
+	return &object.Integer{Value: low + random.Int63n(high-low)}
+}
+
+// rand_float() is in [0, 1)
+func mathRandFloat(args ...object.Object) object.Object {
+	if err := checkArgs("math.rand_float", args, 0); err != nil {
+		return err
+	}
+	randomLock.Lock()
+	defer randomLock.Unlock()
+	return &object.Float{Value: random.Float64()}
+}
+
+// seed(n) restart the random numbers , the same seed always give the same numbers
+func mathSeed(args ...object.Object) object.Object {
+	if err := checkArgs("math.seed", args, 1, object.INTEGER_OBJ); err != nil {
+		return err
+	}
+	randomLock.Lock()
+	defer randomLock.Unlock()
+	random = rand.New(rand.NewSource(intArg(args, 0)))
+	return NIL
+}
@@ -0,0 +1,157 @@
+package evaluator
+
+import (
+	"khanhanh_lang/object"
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestMath(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{"math.abs(-3)", int64(3)},
+		{"math.abs(3)", int64(3)},
+		{"math.abs(-2.5)", 2.5},
+		{"math.min(3, 1, 2)", int64(1)},
+		{"math.min(3, 1.5)", 1.5},
+		{"math.max([1, 7, 3])", int64(7)},
+		{"math.max(-1)", int64(-1)},
+		{"math.pow(2, 10)", int64(1024)},
+		{"math.pow(3, 0)", int64(1)},
+		{"math.pow(2, 62)", int64(1) << 62},
+		{"math.pow(-2, 63)", int64(-1) << 63},
+		{"math.pow(-1, 9223372036854775807)", int64(-1)},
+		{"math.pow(0, 100)", int64(0)},
+		{"math.pow(2, -1)", 0.5},
+		{"math.pow(4, 0.5)", 2.0},
+		{"math.sqrt(16)", 4.0},
+		{"math.sqrt(2.25)", 1.5},
+		{"math.floor(2.7)", int64(2)},
+		{"math.floor(-2.2)", int64(-3)},
+		{"math.ceil(2.1)", int64(3)},
+		{"math.round(2.5)", int64(3)},
+		{"math.round(-2.5)", int64(-3)},
+		{"math.round(7)", int64(7)},
+		{"math.clamp(15, 0, 10)", int64(10)},
+		{"math.clamp(-5, 0, 10)", int64(0)},
+		{"math.clamp(5, 0, 10)", int64(5)},
+		{"math.clamp(5, 0, 2.5)", 2.5},
+		{"math.gcd(12, 18)", int64(6)},
+		{"math.gcd(-4, 6)", int64(2)},
+		{"math.gcd(0, 0)", int64(0)},
+		{"math.PI", math.Pi},
+		{"math.E", math.E},
+		{"1.5 + 1", 2.5},
+		{"2 * 0.25", 0.5},
+		{"-1.5", -1.5},
+		{"1 < 1.5", true},
+		{"2.0 == 2", true},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		if expect, ok := test.expected.(float64); ok {
+			f, ok := evaluated.(*object.Float)
+			if !ok {
+				t.Errorf("%q is not a float. got=%T (%+v)", test.input, evaluated, evaluated)
+				continue
+			}
+			if math.Abs(f.Value-expect) > 1e-12 {
+				t.Errorf("%q wrong value. got=%v , want=%v", test.input, f.Value, expect)
+			}
+			continue
+		}
+		testTypeObject(t, evaluated, test.expected)
+	}
+}
+
+func TestMathErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`math.abs("a")`, "[Error]: argument 1 of math.abs must be NUMBER, got=STRING"},
+		{"math.min()", "[Error]: math.min expect at least 1 number, got=0"},
+		{"math.max([1, true])", "[Error]: argument 2 of math.max must be NUMBER, got=BOOLEAN"},
+		{"math.sqrt(-1)", "[Error]: math.sqrt of a negative number: -1"},
+		{"math.clamp(1, 5, 0)", "[Error]: math.clamp low 5 is greater than high 0"},
+		{"math.gcd(1.5, 2)", "[Error]: argument 1 of math.gcd must be INTEGER, got=FLOAT"},
+		{"math.rand_int(0)", "[Error]: math.rand_int empty range [0, 0)"},
+		{"math.abs(-9223372036854775807 - 1)", "[Error]: math.abs result does not fit an integer: -9223372036854775808"},
+		{"math.pow(2, 64)", "[Error]: math.pow result does not fit an integer: 2, 64"},
+		{"math.pow(2, 63)", "[Error]: math.pow result does not fit an integer: 2, 63"},
+		{"math.pow(3, 41)", "[Error]: math.pow result does not fit an integer: 3, 41"},
+		{"math.gcd(-9223372036854775807 - 1, 0)", "[Error]: math.gcd result does not fit an integer: -9223372036854775808, 0"},
+		{"math.rand_float(1)", "[Error]: math.rand_float expect 0 arguments, got=1"},
+	}
+
+	for _, test := range tests {
+		err, ok := testEval(test.input).(*object.Error)
+		if !ok {
+			t.Errorf("%q should evaluate to an error", test.input)
+			continue
+		}
+		if err.Message != test.expected {
+			t.Errorf("%q wrong error. expected=%q , got=%q", test.input, test.expected, err.Message)
+		}
+	}
+}
+
+func TestMathSeed(t *testing.T) {
+	input := `math.seed(42); [math.rand_int(100), math.rand_int(-5, 5), math.rand_float()]`
+	first := testEval(input).Inspect()
+	second := testEval(input).Inspect()
+	if first != second {
+		t.Errorf("the same seed should give the same numbers. got=%s then %s", first, second)
+	}
+	if other := testEval(`math.seed(7); [math.rand_int(100), math.rand_int(-5, 5), math.rand_float()]`).Inspect(); other == first {
+		t.Errorf("another seed should give other numbers. got=%s twice", first)
+	}
+
+	testEval("math.seed(1)")
+	for i := 0; i < 100; i++ {
+		n := testEval("math.rand_int(-3, 3)").(*object.Integer).Value
+		if n < -3 || n >= 3 {
+			t.Fatalf("math.rand_int(-3, 3) out of range. got=%d", n)
+		}
+		f := testEval("math.rand_float()").(*object.Float).Value
+		if f < 0 || f >= 1 {
+			t.Fatalf("math.rand_float() out of range. got=%v", f)
+		}
+	}
+}
+
+func TestMathRandomWideRange(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		if _, ok := testEval("math.rand_int(-9223372036854775807 - 1, 9223372036854775807)").(*object.Integer); !ok {
+			t.Fatalf("a range wider than half of the integers should still give one")
+		}
+	}
+}
+
+// sessions running side by side share the random source
+func TestMathRandomConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				testEval("math.seed(3); [math.rand_int(10), math.rand_float()]")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestFloatInspect(t *testing.T) {
+	tests := map[string]string{"2.0": "2.0", "1.5 * 3": "4.5", "0.1 + 0.2": "0.30000000000000004", "-0.5": "-0.5"}
+	for input, expected := range tests {
+		if got := testEval(input).Inspect(); got != expected {
+			t.Errorf("%q wrong Inspect. got=%s , want=%s", input, got, expected)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package evaluator
+
+import (
+	"khanhanh_lang/object"
+	"sort"
+	"unicode/utf8"
+)
+
+// METHODS
+// ---------------------------------------------------------------------------------
+// value.method(args) call the method of the value type with the value as first argument ,
+// so "a,b".split(",") is strings.split("a,b", ",") and [1, 2].map(f) is map([1, 2], f)
+// A hash key with the same name as a method hide it , h.keys is the value at "keys" when there is one
+
+var methods = map[object.ObjectType]map[string]object.BuiltinFunction{}
+
+func init() {
+	methods[object.STRING_OBJ] = map[string]object.BuiltinFunction{
+		"len":         methodLen,
+		"split":       stringsSplit,
+		"trim":        stringsTrim,
+		"replace":     stringsReplace,
+		"contains":    stringsContains,
+		"index":       stringsIndex,
+		"upper":       stringsUpper,
+		"lower":       stringsLower,
+		"starts_with": stringsStartsWith,
+		"ends_with":   stringsEndsWith,
+		"repeat":      stringsRepeat,
+		"chars":       stringsChars,
+		"substr":      stringsSubstr,
+	}
+	methods[object.ARRAY_OBJ] = map[string]object.BuiltinFunction{
+		"len":       methodLen,
+		"first":     arrayFirst,
+		"last":      arrayLast,
+		"reverse":   arrayReverse,
+		"join":      stringsJoin,
+		"map":       builtinMap,
+		"filter":    builtinFilter,
+		"reduce":    builtinReduce,
+		"each":      builtinEach,
+		"sort":      builtinSort,
+		"sort_by":   builtinSortBy,
+		"any":       builtinAny,
+		"all":       builtinAll,
+		"enumerate": builtinEnumerate,
+	}
+	methods[object.HASH_OBJ] = map[string]object.BuiltinFunction{
+		"len":    methodLen,
+		"keys":   hashKeys,
+		"values": hashValues,
+		"has":    hashHas,
+	}
+	methods[object.GENERATOR_OBJ] = map[string]object.BuiltinFunction{
+		"next":  generatorNext,
+		"close": generatorClose,
+	}
+	number := map[string]object.BuiltinFunction{
+		"abs":   mathAbs,
+		"pow":   mathPow,
+		"sqrt":  mathSqrt,
+		"floor": mathFloor,
+		"ceil":  mathCeil,
+		"round": mathRound,
+		"clamp": mathClamp,
+	}
+	methods[object.INTEGER_OBJ] = number
+	methods[object.FLOAT_OBJ] = number
+}
+
+// Methods return the method names of the type , sorted , used for completion
+func Methods(t object.ObjectType) []string {
+	names := make([]string, 0, len(methods[t]))
+	for name := range methods[t] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// the method name of obj , bound to obj
+func method(obj object.Object, name string) (object.Object, bool) {
+	fn, ok := methods[obj.Type()][name]
+	if !ok {
+		return nil, false
+	}
+	bound := func(args ...object.Object) object.Object {
+		return fn(append([]object.Object{obj}, args...)...)
+	}
+	return &object.Builtin{Name: name, Fn: bound}, true
+}
+
+// len of a string count its characters , not its bytes
+func methodLen(args ...object.Object) object.Object {
+	if err := checkArgs("len", args, 1, ANY_OBJ); err != nil {
+		return err
+	}
+	switch arg := args[0].(type) {
+	case *object.String:
+		return &object.Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
+	case *object.Array:
+		return &object.Integer{Value: int64(len(arg.Elements))}
+	case *object.Hash:
+		return &object.Integer{Value: int64(arg.Len())}
+	}
+	return newError("[Error]: len not supported for %s", args[0].Type())
+}
+
+// first and last are nil for an empty array
+func arrayFirst(args ...object.Object) object.Object {
+	if err := checkArgs("first", args, 1, object.ARRAY_OBJ); err != nil {
+		return err
+	}
+	if elements := arrayArg(args, 0); len(elements) != 0 {
+		return elements[0]
+	}
+	return NIL
+}
+
+func arrayLast(args ...object.Object) object.Object {
+	if err := checkArgs("last", args, 1, object.ARRAY_OBJ); err != nil {
+		return err
+	}
+	if elements := arrayArg(args, 0); len(elements) != 0 {
+		return elements[len(elements)-1]
+	}
+	return NIL
+}
+
+// reverse is a reversed copy , the array itself is left unchanged
+func arrayReverse(args ...object.Object) object.Object {
+	if err := checkArgs("reverse", args, 1, object.ARRAY_OBJ); err != nil {
+		return err
+	}
+	elements := arrayArg(args, 0)
+	result := make([]object.Object, len(elements))
+	for i, el := range elements {
+		result[len(elements)-1-i] = el
+	}
+	return &object.Array{Elements: result}
+}
+
+// keys and values keep the order of the hash
+func hashKeys(args ...object.Object) object.Object {
+	if err := checkArgs("keys", args, 1, object.HASH_OBJ); err != nil {
+		return err
+	}
+	result := []object.Object{}
+	for _, pair := range args[0].(*object.Hash).Pairs() {
+		result = append(result, pair.Key)
+	}
+	return &object.Array{Elements: result}
+}
+
+func hashValues(args ...object.Object) object.Object {
+	if err := checkArgs("values", args, 1, object.HASH_OBJ); err != nil {
+		return err
+	}
+	result := []object.Object{}
+	for _, pair := range args[0].(*object.Hash).Pairs() {
+		result = append(result, pair.Value)
+	}
+	return &object.Array{Elements: result}
+}
+
+func hashHas(args ...object.Object) object.Object {
+	if err := checkArgs("has", args, 2, object.HASH_OBJ, ANY_OBJ); err != nil {
+		return err
+	}
+	key, ok := args[1].(object.Hashable)
+	if !ok {
+		return newError("[Error]: Unusable as hash key: %s", args[1].Type())
+	}
+	_, found := args[0].(*object.Hash).Get(key)
+	return nativeBool(found)
+}
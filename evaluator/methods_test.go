@@ -0,0 +1,49 @@
+package evaluator
+
+import "testing"
+
+func TestMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{`"abc".upper()`, "ABC"},
+		{`"héllo".len()`, int64(5)},
+		{`"a,b,c".split(",").len()`, int64(3)},
+		{`"  x ".trim().repeat(2)`, "xx"},
+		{`[1, 2].len()`, int64(2)},
+		{`[1, 2, 3].map(func(x) { x * 2 }).reverse().first()`, int64(6)},
+		{`[].last()`, nil},
+		{`["a", "b"].join("-")`, "a-b"},
+		{`[1, 2, 3].reduce(func(a, b) { a + b })`, int64(6)},
+		{`let up = "abc".upper; up()`, "ABC"},
+		{`(-2).abs()`, int64(2)},
+		{`2.5.floor()`, int64(2)},
+		{`{"a": 1, "b": 2}.keys().join("")`, "ab"},
+		{`{"a": 1}.values()[0]`, int64(1)},
+		{`{"a": 1}.has("a")`, true},
+		{`{"a": 1}.len()`, int64(1)},
+		{`true.len()`, ErrorMesssage("[Error]: BOOLEAN has no member len")},
+		{`"abc".nope()`, ErrorMesssage("[Error]: STRING has no member nope")},
+	}
+	for _, tt := range tests {
+		testResult(t, tt.input, tt.expected)
+	}
+}
+
+func TestHashMember(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{`let h = {"name": "k", "n": {"x": 1}}; h.name`, "k"},
+		{`let h = {"n": {"x": 1}}; h.n.x`, int64(1)},
+		{`{"a": 1}.missing`, nil},
+		// a key hide the method with the same name
+		{`{"len": 10}.len`, int64(10)},
+		{`let h = {"f": func(x) { x + 1 }}; h.f(1)`, int64(2)},
+	}
+	for _, tt := range tests {
+		testResult(t, tt.input, tt.expected)
+	}
+}
@@ -0,0 +1,189 @@
+package evaluator
+
+import (
+	"khanhanh_lang/lexer"
+	"khanhanh_lang/object"
+	"khanhanh_lang/parser"
+	"strings"
+	"testing"
+)
+
+var tableOperators = []string{"+", "-", "*", "/", "==", "!=", "<", ">", "<=", ">="}
+
+// every pair of operand types , the results follow the order of tableOperators ,
+// mismatch and unknown stand for the Mismatch and Unknown operator errors
+var operatorTable = []struct {
+	left, right string
+	results     string
+}{
+	{`6`, `6`, "12 0 36 1 true false false false true true"},
+	{`6`, `1.5`, "7.5 4.5 9.0 4.0 false true false true false true"},
+	{`6`, `"a"`, "6a mismatch aaaaaa mismatch false true mismatch mismatch mismatch mismatch"},
+	{`6`, `true`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`6`, `if (false) { 1 }`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`6`, `[1]`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`6`, `{"k": 1}`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`6`, `func() {}`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`1.5`, `6`, "7.5 -4.5 9.0 0.25 false true true false true false"},
+	{`1.5`, `1.5`, "3.0 0.0 2.25 1.0 true false false false true true"},
+	{`1.5`, `"a"`, "1.5a mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`1.5`, `true`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`1.5`, `if (false) { 1 }`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`1.5`, `[1]`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`1.5`, `{"k": 1}`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`1.5`, `func() {}`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`"a"`, `6`, "a6 mismatch aaaaaa mismatch false true mismatch mismatch mismatch mismatch"},
+	{`"a"`, `1.5`, "a1.5 mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`"a"`, `"a"`, "aa unknown unknown unknown true false false false true true"},
+	{`"a"`, `true`, "atrue mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`"a"`, `if (false) { 1 }`, "anil mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`"a"`, `[1]`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`"a"`, `{"k": 1}`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`"a"`, `func() {}`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`true`, `6`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`true`, `1.5`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`true`, `"a"`, "truea mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`true`, `true`, "unknown unknown unknown unknown true false unknown unknown unknown unknown"},
+	{`true`, `if (false) { 1 }`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`true`, `[1]`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`true`, `{"k": 1}`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`true`, `func() {}`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`if (false) { 1 }`, `6`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`if (false) { 1 }`, `1.5`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`if (false) { 1 }`, `"a"`, "nila mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`if (false) { 1 }`, `true`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`if (false) { 1 }`, `if (false) { 1 }`, "unknown unknown unknown unknown true false unknown unknown unknown unknown"},
+	{`if (false) { 1 }`, `[1]`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`if (false) { 1 }`, `{"k": 1}`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`if (false) { 1 }`, `func() {}`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`[1]`, `6`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`[1]`, `1.5`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`[1]`, `"a"`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`[1]`, `true`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`[1]`, `if (false) { 1 }`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`[1]`, `[1]`, "unknown unknown unknown unknown true false false false true true"},
+	{`[1]`, `{"k": 1}`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`[1]`, `func() {}`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`{"k": 1}`, `6`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`{"k": 1}`, `1.5`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`{"k": 1}`, `"a"`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`{"k": 1}`, `true`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`{"k": 1}`, `if (false) { 1 }`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`{"k": 1}`, `[1]`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`{"k": 1}`, `{"k": 1}`, "unknown unknown unknown unknown true false unknown unknown unknown unknown"},
+	{`{"k": 1}`, `func() {}`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`func() {}`, `6`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`func() {}`, `1.5`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`func() {}`, `"a"`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`func() {}`, `true`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`func() {}`, `if (false) { 1 }`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`func() {}`, `[1]`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`func() {}`, `{"k": 1}`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`func() {}`, `func() {}`, "unknown unknown unknown unknown false true unknown unknown unknown unknown"},
+}
+
+// the rows strict mode change , the others give the same results in both modes
+var strictOperatorTable = []struct {
+	left, right string
+	results     string
+}{
+	{`6`, `"a"`, "mismatch mismatch aaaaaa mismatch false true mismatch mismatch mismatch mismatch"},
+	{`1.5`, `"a"`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`"a"`, `6`, "mismatch mismatch aaaaaa mismatch false true mismatch mismatch mismatch mismatch"},
+	{`"a"`, `1.5`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`"a"`, `true`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`"a"`, `if (false) { 1 }`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`true`, `"a"`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+	{`if (false) { 1 }`, `"a"`, "mismatch mismatch mismatch mismatch false true mismatch mismatch mismatch mismatch"},
+}
+
+func TestOperatorTable(t *testing.T) {
+	for _, row := range operatorTable {
+		testOperatorRow(t, row.left, row.right, row.results, false)
+	}
+}
+
+func TestStrictOperatorTable(t *testing.T) {
+	strict := map[[2]string]string{}
+	for _, row := range strictOperatorTable {
+		strict[[2]string{row.left, row.right}] = row.results
+	}
+	for _, row := range operatorTable {
+		results, ok := strict[[2]string{row.left, row.right}]
+		if !ok {
+			results = row.results
+		}
+		testOperatorRow(t, row.left, row.right, results, true)
+	}
+}
+
+func testOperatorRow(t *testing.T, left, right, results string, strict bool) {
+	t.Helper()
+	expected := strings.Split(results, " ")
+	for i, operator := range tableOperators {
+		input := "let l = " + left + "; let r = " + right + "; l " + operator + " r"
+		tracker := object.NewTracker()
+		tracker.SetStrict(strict)
+		got := operatorResult(Eval(parser.New(lexer.New(input)).ParseProgram(), tracker))
+		if got != expected[i] {
+			t.Errorf("%s %s %s (strict=%t) : expected %s , got %s", left, operator, right, strict, expected[i], got)
+		}
+	}
+}
+
+func operatorResult(obj object.Object) string {
+	if err, ok := obj.(*object.Error); ok {
+		switch {
+		case strings.HasPrefix(err.Message, "[Error]: Mismatch "):
+			return "mismatch"
+		case strings.HasPrefix(err.Message, "[Error]: Unknown operator: "):
+			return "unknown"
+		}
+		return err.Message
+	}
+	return obj.Inspect()
+}
+
+func TestMixedOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{`"5" == 5`, false},
+		{`5 == "5"`, false},
+		{`"5" != 5`, true},
+		{`1 == 1.0`, true},
+		{`let n = if (false) { 1 }; n == {}["k"]`, true},
+		{`true == 1`, false},
+		{`let f = func() {}; f == f`, true},
+		{`"a" + 5`, "a5"},
+		{`5 + "a"`, "5a"},
+		{`"x = " + 2.0`, "x = 2.0"},
+		{`"ok: " + true`, "ok: true"},
+		{`3 * "ab"`, "ababab"},
+		{`"ab" * 0`, ""},
+		{`"ab" * -2`, ""},
+		{`"" * 9223372036854775807`, ""},
+		{`"a" * 9223372036854775807`, ErrorMesssage("[Error]: String repeat too long: 9223372036854775807 copies of 1 bytes , the limit is 268435456 bytes")},
+		{`"ab" * 5000000000000000000`, ErrorMesssage("[Error]: String repeat too long: 5000000000000000000 copies of 2 bytes , the limit is 268435456 bytes")},
+		{`"a" + true == "atrue"`, true},
+		{`"a" * "x"`, ErrorMesssage("[Error]: Unknown operator: STRING * STRING")},
+		{`"a" + [1]`, ErrorMesssage("[Error]: Mismatch STRING + ARRAY")},
+		{`"a" - 1`, ErrorMesssage("[Error]: Mismatch STRING - INTEGER")},
+		{`"a" * 1.5`, ErrorMesssage("[Error]: Mismatch STRING * FLOAT")},
+	}
+	for _, tt := range tests {
+		testResult(t, tt.input, tt.expected)
+	}
+}
+
+func TestStrictModeIsInherited(t *testing.T) {
+	input := `let f = func(x) { "n" + x }; [1].map(f)`
+	tracker := object.NewTracker()
+	tracker.SetStrict(true)
+	result := Eval(parser.New(lexer.New(input)).ParseProgram(), tracker)
+	err, ok := result.(*object.Error)
+	if !ok || err.Message != "[Error]: Mismatch STRING + INTEGER" {
+		t.Fatalf("expected a Mismatch error in strict mode , got %s", result.Inspect())
+	}
+}
@@ -0,0 +1,188 @@
+package evaluator
+
+import (
+	"khanhanh_lang/object"
+	"strings"
+	"unicode/utf8"
+)
+
+// STRINGS MODULE
+// ---------------------------------------------------------------------------------
+// Positions and lengths count characters (runes) , never bytes , so "héllo" has 5 characters
+
+func init() {
+	builtins["strings"] = newModule("strings", map[string]object.BuiltinFunction{
+		"split":       stringsSplit,
+		"join":        stringsJoin,
+		"trim":        stringsTrim,
+		"replace":     stringsReplace,
+		"contains":    stringsContains,
+		"index":       stringsIndex,
+		"upper":       stringsUpper,
+		"lower":       stringsLower,
+		"starts_with": stringsStartsWith,
+		"ends_with":   stringsEndsWith,
+		"repeat":      stringsRepeat,
+		"chars":       stringsChars,
+		"substr":      stringsSubstr,
+	})
+}
+
+func newStringArray(values []string) *object.Array {
+	elements := make([]object.Object, len(values))
+	for i, value := range values {
+		elements[i] = &object.String{Value: value}
+	}
+	return &object.Array{Elements: elements}
+}
+
+func stringArg(args []object.Object, i int) string {
+	return args[i].(*object.String).Value
+}
+
+// split(s, sep) , an empty separator split after each character
+func stringsSplit(args ...object.Object) object.Object {
+	if err := checkArgs("strings.split", args, 2, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+		return err
+	}
+	return newStringArray(strings.Split(stringArg(args, 0), stringArg(args, 1)))
+}
+
+// join(array, sep) , every element must be a string
+func stringsJoin(args ...object.Object) object.Object {
+	if err := checkArgs("strings.join", args, 2, object.ARRAY_OBJ, object.STRING_OBJ); err != nil {
+		return err
+	}
+	elements := args[0].(*object.Array).Elements
+	values := make([]string, len(elements))
+	for i, el := range elements {
+		s, ok := el.(*object.String)
+		if !ok {
+			return newError("[Error]: strings.join element %d must be STRING, got=%s", i, el.Type())
+		}
+		values[i] = s.Value
+	}
+	return &object.String{Value: strings.Join(values, stringArg(args, 1))}
+}
+
+// trim(s) remove the surrounding white spaces , trim(s, cutset) the surrounding characters of cutset
+func stringsTrim(args ...object.Object) object.Object {
+	if err := checkArgs("strings.trim", args, 1, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+		return err
+	}
+	if len(args) == 1 {
+		return &object.String{Value: strings.TrimSpace(stringArg(args, 0))}
+	}
+	return &object.String{Value: strings.Trim(stringArg(args, 0), stringArg(args, 1))}
+}
+
+// replace(s, old, new) replace every occurrence , replace(s, old, new, n) only the first n
+func stringsReplace(args ...object.Object) object.Object {
+	if err := checkArgs("strings.replace", args, 3, object.STRING_OBJ, object.STRING_OBJ, object.STRING_OBJ, object.INTEGER_OBJ); err != nil {
+		return err
+	}
+	n := -1
+	if len(args) == 4 {
+		n = int(args[3].(*object.Integer).Value)
+	}
+	return &object.String{Value: strings.Replace(stringArg(args, 0), stringArg(args, 1), stringArg(args, 2), n)}
+}
+
+func stringsContains(args ...object.Object) object.Object {
+	if err := checkArgs("strings.contains", args, 2, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+		return err
+	}
+	return nativeBool(strings.Contains(stringArg(args, 0), stringArg(args, 1)))
+}
+
+// index(s, sub) is the character position of the first sub in s , -1 when s does not contain it
+func stringsIndex(args ...object.Object) object.Object {
+	if err := checkArgs("strings.index", args, 2, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+		return err
+	}
+	s := stringArg(args, 0)
+	i := strings.Index(s, stringArg(args, 1))
+	if i < 0 {
+		return &object.Integer{Value: -1}
+	}
+	return &object.Integer{Value: int64(utf8.RuneCountInString(s[:i]))}
+}
+
+func stringsUpper(args ...object.Object) object.Object {
+	if err := checkArgs("strings.upper", args, 1, object.STRING_OBJ); err != nil {
+		return err
+	}
+	return &object.String{Value: strings.ToUpper(stringArg(args, 0))}
+}
+
+func stringsLower(args ...object.Object) object.Object {
+	if err := checkArgs("strings.lower", args, 1, object.STRING_OBJ); err != nil {
+		return err
+	}
+	return &object.String{Value: strings.ToLower(stringArg(args, 0))}
+}
+
+func stringsStartsWith(args ...object.Object) object.Object {
+	if err := checkArgs("strings.starts_with", args, 2, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+		return err
+	}
+	return nativeBool(strings.HasPrefix(stringArg(args, 0), stringArg(args, 1)))
+}
+
+func stringsEndsWith(args ...object.Object) object.Object {
+	if err := checkArgs("strings.ends_with", args, 2, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+		return err
+	}
+	return nativeBool(strings.HasSuffix(stringArg(args, 0), stringArg(args, 1)))
+}
+
+func stringsRepeat(args ...object.Object) object.Object {
+	if err := checkArgs("strings.repeat", args, 2, object.STRING_OBJ, object.INTEGER_OBJ); err != nil {
+		return err
+	}
+	n := args[1].(*object.Integer).Value
+	if n < 0 {
+		return newError("[Error]: strings.repeat count must not be negative, got=%d", n)
+	}
+	if err := checkRepeatLength(stringArg(args, 0), n); err != nil {
+		return err
+	}
+	return &object.String{Value: strings.Repeat(stringArg(args, 0), int(n))}
+}
+
+// chars(s) is the array of the characters of s
+func stringsChars(args ...object.Object) object.Object {
+	if err := checkArgs("strings.chars", args, 1, object.STRING_OBJ); err != nil {
+		return err
+	}
+	runes := []rune(stringArg(args, 0))
+	values := make([]string, len(runes))
+	for i, r := range runes {
+		values[i] = string(r)
+	}
+	return newStringArray(values)
+}
+
+// substr(s, start) is s from the character start , substr(s, start, length) at most length characters of it
+func stringsSubstr(args ...object.Object) object.Object {
+	if err := checkArgs("strings.substr", args, 2, object.STRING_OBJ, object.INTEGER_OBJ, object.INTEGER_OBJ); err != nil {
+		return err
+	}
+	runes := []rune(stringArg(args, 0))
+	start := args[1].(*object.Integer).Value
+	if start < 0 || start > int64(len(runes)) {
+		return newError("[Error]: strings.substr start %d out of range [0, %d]", start, len(runes))
+	}
+	end := int64(len(runes))
+	if len(args) == 3 {
+		length := args[2].(*object.Integer).Value
+		if length < 0 {
+			return newError("[Error]: strings.substr length must not be negative, got=%d", length)
+		}
+		// against the characters left , start + length could overflow
+		if length < end-start {
+			end = start + length
+		}
+	}
+	return &object.String{Value: string(runes[start:end])}
+}
@@ -0,0 +1,127 @@
+package evaluator
+
+import (
+	"khanhanh_lang/lexer"
+	"khanhanh_lang/object"
+	"khanhanh_lang/parser"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// evaluate input with the given strings bound , so the test inputs need no escaping
+func evalWith(input string, vars map[string]string) object.Object {
+	tracker := object.NewTracker()
+	for name, value := range vars {
+		tracker.Set(name, &object.String{Value: value})
+	}
+	return Eval(parser.New(lexer.New(input)).ParseProgram(), tracker)
+}
+
+func stringValues(t *testing.T, obj object.Object) []string {
+	array, ok := obj.(*object.Array)
+	if !ok {
+		t.Fatalf("object is not an array. got=%T (%+v)", obj, obj)
+	}
+	values := []string{}
+	for _, el := range array.Elements {
+		values = append(values, el.(*object.String).Value)
+	}
+	return values
+}
+
+var stringSamples = []string{"", "hello world", "  padded\t\n", "héllo wörld", "🥲 🥳 🥲", "a,b,,c", "ÅÇ ß İ"}
+var stringSubs = []string{"", "o", "l", "wör", "🥳", ",", "zz"}
+
+func TestStringsAgainstGo(t *testing.T) {
+	for _, s := range stringSamples {
+		vars := map[string]string{"s": s}
+		testTypeObject(t, evalWith("strings.upper(s)", vars), strings.ToUpper(s))
+		testTypeObject(t, evalWith("strings.lower(s)", vars), strings.ToLower(s))
+		testTypeObject(t, evalWith("strings.trim(s)", vars), strings.TrimSpace(s))
+		testTypeObject(t, evalWith("strings.repeat(s, 3)", vars), strings.Repeat(s, 3))
+
+		chars := stringValues(t, evalWith("strings.chars(s)", vars))
+		if len(chars) != utf8.RuneCountInString(s) || strings.Join(chars, "") != s {
+			t.Errorf("strings.chars(%q) wrong. got=%q", s, chars)
+		}
+
+		for _, sub := range stringSubs {
+			vars := map[string]string{"s": s, "sub": sub}
+			testTypeObject(t, evalWith("strings.contains(s, sub)", vars), strings.Contains(s, sub))
+			testTypeObject(t, evalWith("strings.starts_with(s, sub)", vars), strings.HasPrefix(s, sub))
+			testTypeObject(t, evalWith("strings.ends_with(s, sub)", vars), strings.HasSuffix(s, sub))
+			testTypeObject(t, evalWith(`strings.replace(s, sub, "_")`, vars), strings.ReplaceAll(s, sub, "_"))
+			testTypeObject(t, evalWith(`strings.replace(s, sub, "_", 1)`, vars), strings.Replace(s, sub, "_", 1))
+			testTypeObject(t, evalWith("strings.trim(s, sub)", vars), strings.Trim(s, sub))
+
+			split := stringValues(t, evalWith("strings.split(s, sub)", vars))
+			if strings.Join(split, "|") != strings.Join(strings.Split(s, sub), "|") {
+				t.Errorf("strings.split(%q, %q) wrong. got=%q", s, sub, split)
+			}
+			joined := evalWith("strings.join(strings.split(s, sub), sub)", vars)
+			testTypeObject(t, joined, s)
+
+			// index count characters , so it is the byte index of Go converted to runes
+			want := int64(-1)
+			if i := strings.Index(s, sub); i >= 0 {
+				want = int64(utf8.RuneCountInString(s[:i]))
+			}
+			testTypeObject(t, evalWith("strings.index(s, sub)", vars), want)
+		}
+	}
+}
+
+func TestStringsRuneAware(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{`strings.index("héllo", "l")`, int64(2)},
+		{`strings.substr("héllo wörld", 6)`, "wörld"},
+		{`strings.substr("héllo wörld", 1, 4)`, "éllo"},
+		{`strings.substr("🥲🥳", 1, 10)`, "🥳"},
+		{`strings.substr("abc", 3)`, ""},
+		{`strings.substr("abc", 1, 9223372036854775807)`, "bc"},
+		{`strings.chars("🥲🥳")[1]`, "🥳"},
+		{`"héllo"[1]`, "é"},
+		{`strings.join([], ",")`, ""},
+	}
+
+	for _, test := range tests {
+		testTypeObject(t, testEval(test.input), test.expected)
+	}
+	chars := stringValues(t, testEval(`strings.split("hé", "")`))
+	if strings.Join(chars, "|") != "h|é" {
+		t.Errorf("an empty separator should split characters. got=%q", chars)
+	}
+}
+
+func TestStringsErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`strings.upper()`, "[Error]: strings.upper expect 1 argument, got=0"},
+		{`strings.upper(1)`, "[Error]: argument 1 of strings.upper must be STRING, got=INTEGER"},
+		{`strings.split("a")`, "[Error]: strings.split expect 2 arguments, got=1"},
+		{`strings.trim("a", "b", "c")`, "[Error]: strings.trim expect 1 to 2 arguments, got=3"},
+		{`strings.join(["a", 1], ",")`, "[Error]: strings.join element 1 must be STRING, got=INTEGER"},
+		{`strings.repeat("a", -1)`, "[Error]: strings.repeat count must not be negative, got=-1"},
+		{`strings.repeat("ab", 5000000000000000000)`, "[Error]: String repeat too long: 5000000000000000000 copies of 2 bytes , the limit is 268435456 bytes"},
+		{`strings.substr("abc", 4)`, "[Error]: strings.substr start 4 out of range [0, 3]"},
+		{`strings.substr("abc", 0, -1)`, "[Error]: strings.substr length must not be negative, got=-1"},
+		{`strings.nope("a")`, "[Error]: Module strings has no member nope"},
+	}
+
+	for _, test := range tests {
+		err, ok := testEval(test.input).(*object.Error)
+		if !ok {
+			t.Errorf("%q should evaluate to an error", test.input)
+			continue
+		}
+		if err.Message != test.expected {
+			t.Errorf("%q wrong error. expected=%q , got=%q", test.input, test.expected, err.Message)
+		}
+	}
+}
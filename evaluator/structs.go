@@ -0,0 +1,83 @@
+package evaluator
+
+import (
+	"khanhanh_lang/ast"
+	"khanhanh_lang/object"
+)
+
+// STRUCTS
+// ---------------------------------------------------------------------------------
+// struct Point { x, y } bind Point to a constructor taking the fields in order , Point(1, 2)
+// Instances are values : p.with({"x": 3}) is a copy with x replaced , p itself never change ,
+// and two instances are equal when they come from the same struct with equal fields
+
+func init() {
+	methods[object.INSTANCE_OBJ] = map[string]object.BuiltinFunction{
+		"with": instanceWith,
+	}
+}
+
+func evalStructStatement(node *ast.StructStatement, tracker *object.Tracker) object.Object {
+	fields := make([]string, len(node.Fields))
+	for i, field := range node.Fields {
+		fields[i] = field.Value
+	}
+	return tracker.Set(node.Name.Value, &object.Struct{Name: node.Name.Value, Fields: fields})
+}
+
+// the fields are given in order , then by name as Point(1, y: 2)
+func newInstance(s *object.Struct, args []object.Object, named []namedArgument) object.Object {
+	if len(args) > len(s.Fields) {
+		return newError("[Error]: %s expect %d fields, got=%d", s.Name, len(s.Fields), len(args))
+	}
+	values := make([]object.Object, len(s.Fields))
+	copy(values, args)
+	for _, arg := range named {
+		i := s.Field(arg.name)
+		if i < 0 {
+			return newError("[Error]: %s has no field %s", s.Name, arg.name)
+		}
+		if values[i] != nil {
+			return newError("[Error]: %s got field %s twice", s.Name, arg.name)
+		}
+		values[i] = arg.value
+	}
+	for i, value := range values {
+		if value == nil {
+			return newError("[Error]: %s missing field %s", s.Name, s.Fields[i])
+		}
+	}
+	return &object.Instance{Struct: s, Values: values}
+}
+
+func evalInstanceMember(instance *object.Instance, name string) object.Object {
+	if value, ok := instance.Get(name); ok {
+		return value
+	}
+	if bound, ok := method(instance, name); ok {
+		return bound
+	}
+	return newError("[Error]: %s has no field %s", instance.Struct.Name, name)
+}
+
+// with(changes) is a copy of the instance , changes is a hash from field names to their new value
+func instanceWith(args ...object.Object) object.Object {
+	if err := checkArgs("with", args, 2, object.INSTANCE_OBJ, object.HASH_OBJ); err != nil {
+		return err
+	}
+	instance := args[0].(*object.Instance)
+	values := make([]object.Object, len(instance.Values))
+	copy(values, instance.Values)
+	for _, pair := range args[1].(*object.Hash).Pairs() {
+		name, ok := pair.Key.(*object.String)
+		if !ok {
+			return newError("[Error]: %s field name must be STRING, got=%s", instance.Struct.Name, pair.Key.Type())
+		}
+		i := instance.Struct.Field(name.Value)
+		if i < 0 {
+			return newError("[Error]: %s has no field %s", instance.Struct.Name, name.Value)
+		}
+		values[i] = pair.Value
+	}
+	return &object.Instance{Struct: instance.Struct, Values: values}
+}
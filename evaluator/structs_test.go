@@ -0,0 +1,48 @@
+package evaluator
+
+import "testing"
+
+func TestStruct(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{"struct Point { x, y }; Point(1, 2).x", int64(1)},
+		{"struct Point { x, y }; let p = Point(1, 2); p.x + p.y", int64(3)},
+		{"struct Point { x, y }; let p = Point(1, 2); let q = p.with({\"x\": 5}); [p.x, q.x, q.y]", "[1, 5, 2]"},
+		{"struct Point { x, y }; Point(1, 2) == Point(1, 2)", true},
+		{"struct Point { x, y }; Point(1, 2) == Point(1, 3)", false},
+		{"struct Point { x, y }; Point(1, 2) != Point(2, 1)", true},
+		{"struct Point { x, y }; Point(1, 2.0) == Point(1.0, 2)", true},
+		{"struct A { v }; struct B { v }; A(1) == B(1)", false},
+		{"struct Line { from, to }; struct P { x }; Line(P(1), P(2)) == Line(P(1), P(2))", true},
+		{"struct Box { v }; Box([1]) == Box([1])", true},
+		{"struct Unit {}; Unit() == Unit()", true},
+		{"struct Box { v }; map([1, 2], Box)[1].v", int64(2)},
+		{"struct Point { x, y }; Point(1)", ErrorMesssage("[Error]: Point missing field y")},
+		{"struct Point { x, y }; Point(1, 2, 3)", ErrorMesssage("[Error]: Point expect 2 fields, got=3")},
+		{"struct Point { x, y }; Point(1, 2).z", ErrorMesssage("[Error]: Point has no field z")},
+		{"struct Point { x, y }; Point(1, 2).with({\"z\": 1})", ErrorMesssage("[Error]: Point has no field z")},
+		{"struct Point { x, y }; Point(1, 2) + Point(1, 2)", ErrorMesssage("[Error]: Unknown operator: INSTANCE + INSTANCE")},
+	}
+	for _, tt := range tests {
+		testResult(t, tt.input, tt.expected)
+	}
+}
+
+func TestStructInspect(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`struct Point { x, y }; Point(1, 2)`, "Point{x: 1, y: 2}"},
+		{`struct Named { name }; Named("a")`, `Named{name: "a"}`},
+		{`struct Point { x, y }; Point`, "struct Point { x, y }"},
+		{`struct Unit {}; [Unit, Unit()]`, "[struct Unit {}, Unit{}]"},
+	}
+	for _, tt := range tests {
+		if got := testEval(tt.input).Inspect(); got != tt.expected {
+			t.Errorf("%s wrong Inspect. expect=%s got=%s", tt.input, tt.expected, got)
+		}
+	}
+}
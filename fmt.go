@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"khanhanh_lang/format"
+	"os"
+)
+
+// fmt subcommand
+// print the canonical form of each file ( or stdin ) , -w rewrite the files in place ,
+// --check only list the files that are not formatted and exit with 1 , for CI
+func runFmt(args []string) int {
+	flags := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	write := flags.Bool("w", false, "write result to the source file instead of stdout")
+	check := flags.Bool("check", false, "list files whose formatting differs and exit with status 1")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: khanhanh_lang fmt [-w | --check] [file ...]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *write && *check {
+		fmt.Fprintln(os.Stderr, "fmt: -w and --check cannot be used together")
+		return 2
+	}
+
+	if flags.NArg() == 0 {
+		if *write {
+			fmt.Fprintln(os.Stderr, "fmt: cannot use -w with standard input")
+			return 2
+		}
+		src, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return formatFile("<stdin>", src, false, *check)
+	}
+
+	status := 0
+	for _, path := range flags.Args() {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			status = 1
+			continue
+		}
+		if code := formatFile(path, src, *write, *check); code != 0 {
+			status = code
+		}
+	}
+	return status
+}
+
+func formatFile(path string, src []byte, write, check bool) int {
+	result, err := format.Source(src)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s:\n%s\n", path, err)
+		return 1
+	}
+	changed := !bytes.Equal(src, result)
+	switch {
+	case check:
+		if changed {
+			fmt.Println(path)
+			return 1
+		}
+	case write:
+		if changed {
+			if err := os.WriteFile(path, result, 0644); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+		}
+	default:
+		os.Stdout.Write(result)
+	}
+	return 0
+}
@@ -0,0 +1,582 @@
+package format
+
+// Canonical pretty printer , turn an ast.Program back into source that the parser accept
+// Output is stable : formatting already formatted source give back the same bytes
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"khanhanh_lang/ast"
+	"khanhanh_lang/lexer"
+	"khanhanh_lang/parser"
+	"khanhanh_lang/token"
+	"strconv"
+	"strings"
+)
+
+const indentation = "    "
+
+// Source parse src and return it in canonical form , or the parser errors when src is not valid
+func Source(src []byte) ([]byte, error) {
+	p := parser.New(lexer.New(string(src)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return nil, errors.New(strings.Join(p.Errors(), "\n"))
+	}
+	var out bytes.Buffer
+	if err := Program(&out, program); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// Program write the canonical source of program to w , comments in program.Comments are put back
+// next to the statement they belong to
+func Program(w io.Writer, program *ast.Program) error {
+	p := &printer{comments: program.Comments, blockStart: true}
+	p.statements(program.Statements, 0)
+	p.flushComments(-1)
+	_, err := w.Write(p.out.Bytes())
+	return err
+}
+
+type printer struct {
+	out    bytes.Buffer
+	indent int
+
+	comments []*ast.Comment // comments not printed yet
+	lastLine int            // last source line printed , used to keep blank lines and attach trailing comments
+	maxLine  int            // highest source line seen while printing the current statement
+
+	blockStart bool // nothing printed yet in the current block
+}
+
+// HELPER
+// ---------------------------------------------------------------------------------
+func (p *printer) write(s string) {
+	p.out.WriteString(s)
+}
+
+func (p *printer) newline() {
+	p.out.WriteString("\n")
+}
+
+func (p *printer) writeIndent() {
+	p.out.WriteString(strings.Repeat(indentation, p.indent))
+}
+
+// remember the source line of a token that has been printed
+func (p *printer) mark(tok token.Token) {
+	if tok.Line > p.maxLine {
+		p.maxLine = tok.Line
+	}
+}
+
+// keep a single blank line where the source had one or more
+func (p *printer) separate(line int) {
+	if !p.blockStart && line > p.lastLine+1 && p.lastLine > 0 {
+		p.newline()
+	}
+	p.blockStart = false
+}
+
+// COMMENTS
+// ---------------------------------------------------------------------------------
+// Print every pending comment that start before line on its own line , -1 flush all of them
+func (p *printer) flushComments(line int) {
+	for len(p.comments) > 0 {
+		comment := p.comments[0]
+		if line >= 0 && comment.Token.Line >= line {
+			return
+		}
+		p.separate(comment.Token.Line)
+		p.writeIndent()
+		p.write(strings.TrimRight(comment.Text, " \t\r"))
+		p.newline()
+		p.lastLine = comment.Token.Line
+		p.comments = p.comments[1:]
+	}
+}
+
+// Print the pending comments up to line at the end of the current line
+func (p *printer) trailingComments(line int) {
+	for len(p.comments) > 0 && p.comments[0].Token.Line <= line {
+		p.write(" ")
+		p.write(strings.TrimRight(p.comments[0].Text, " \t\r"))
+		p.comments = p.comments[1:]
+	}
+}
+
+// last line whose comments trail a statement ending on line , a comment on the line of the closing } come after
+// it and is left to what follow the block , endLine is 0 when nothing close the statements
+func lineBefore(line, endLine int) int {
+	if endLine > 0 && line >= endLine {
+		return endLine - 1
+	}
+	return line
+}
+
+// check whether a comment is waiting to be printed before line
+func (p *printer) hasCommentBefore(line int) bool {
+	return len(p.comments) > 0 && p.comments[0].Token.Line < line
+}
+
+// STATEMENTS
+// ---------------------------------------------------------------------------------
+// Print a list of statements , endLine is the line that close the list ( } of a block ) , 0 if unknown
+func (p *printer) statements(stmts []ast.Statement, endLine int) {
+	for i, stmt := range stmts {
+		start := startLine(stmt)
+		if start > 0 {
+			p.flushComments(start)
+		}
+		p.separate(start)
+
+		p.maxLine = start
+		p.writeIndent()
+		p.statement(stmt)
+		if needSemicolon(stmt, stmts[i+1:]) {
+			p.write(";")
+		}
+		end := p.maxLine
+		if end > 0 {
+			p.trailingComments(lineBefore(end, endLine))
+		}
+		p.newline()
+		if end > p.lastLine {
+			p.lastLine = end
+		}
+	}
+	if endLine > 0 {
+		p.flushComments(endLine)
+	}
+}
+
+// first source line of a statement , 0 when the node was not produced by the parser
+func startLine(stmt ast.Statement) int {
+	switch s := stmt.(type) {
+	case *ast.LetStatement:
+		return s.Token.Line
+	case *ast.ReturnStatement:
+		return s.Token.Line
+	case *ast.ExpressionStatement:
+		return s.Token.Line
+	case *ast.ImportStatement:
+		return s.Token.Line
+	case *ast.StructStatement:
+		return s.Token.Line
+	case *ast.ThrowStatement:
+		return s.Token.Line
+	case *ast.ForStatement:
+		return s.Token.Line
+	case *ast.BlockStatement:
+		return s.Token.Line
+	}
+	return 0
+}
+
+func (p *printer) statement(stmt ast.Statement) {
+	switch s := stmt.(type) {
+	case *ast.LetStatement:
+		p.mark(s.Token)
+		p.write(s.TokenLiteral() + " ")
+		p.expression(s.Target())
+		p.annotation(s.Type)
+		p.write(" = ")
+		p.expression(s.Value)
+	case *ast.ReturnStatement:
+		p.mark(s.Token)
+		p.write("return")
+		if s.ReturnValue != nil {
+			p.write(" ")
+			p.expression(s.ReturnValue)
+		}
+	case *ast.ExpressionStatement:
+		p.mark(s.Token)
+		p.expression(s.Expression)
+	case *ast.ImportStatement:
+		p.mark(s.Token)
+		p.write("import ")
+		p.expression(s.Path)
+	case *ast.ThrowStatement:
+		p.mark(s.Token)
+		p.write("throw ")
+		p.expression(s.Value)
+	case *ast.ForStatement:
+		p.mark(s.Token)
+		p.write("for ")
+		p.expression(s.Target)
+		p.write(" in ")
+		p.expression(s.Iterable)
+		p.write(" ")
+		p.block(s.Body)
+	case *ast.StructStatement:
+		p.mark(s.Token)
+		p.write("struct " + s.Name.Value + " {")
+		for i, field := range s.Fields {
+			p.mark(field.Token)
+			if i > 0 {
+				p.write(",")
+			}
+			p.write(" " + field.Value)
+		}
+		if len(s.Fields) != 0 {
+			p.write(" ")
+		}
+		p.mark(s.Rbrace)
+		p.write("}")
+	case *ast.BlockStatement:
+		p.block(s)
+	}
+}
+
+// Every statement end with a semicolon , except a block , a for , and an if , a try or a match that is not followed by
+// something the parser would take as the rest of the expression ( the parser keep parsing `if (x) {1} -1` as a subtraction )
+func needSemicolon(stmt ast.Statement, rest []ast.Statement) bool {
+	es, ok := stmt.(*ast.ExpressionStatement)
+	if !ok {
+		switch stmt.(type) {
+		case *ast.BlockStatement, *ast.ForStatement:
+			return false
+		}
+		return true
+	}
+	switch es.Expression.(type) {
+	case *ast.IfExpression, *ast.TryExpression, *ast.MatchExpression:
+	default:
+		return true
+	}
+	if len(rest) == 0 {
+		return false
+	}
+	scratch := &printer{}
+	scratch.statement(rest[0])
+	next := scratch.out.String()
+	return strings.HasPrefix(next, "-") || strings.HasPrefix(next, "(") || strings.HasPrefix(next, "[")
+}
+
+// : type after an annotated name , nothing when typ is nil
+func (p *printer) annotation(typ *ast.Type) {
+	if typ == nil {
+		return
+	}
+	p.mark(typ.Token)
+	p.mark(typ.Close)
+	p.write(": " + typ.String())
+}
+
+func (p *printer) block(b *ast.BlockStatement) {
+	p.mark(b.Token)
+	if len(b.Statements) == 0 && !p.hasCommentBefore(b.Rbrace.Line) {
+		p.write("{}")
+		p.mark(b.Rbrace)
+		return
+	}
+	p.write("{")
+	p.newline()
+	p.indent++
+
+	outerLast, outerMax := p.lastLine, p.maxLine
+	p.lastLine = b.Token.Line
+	p.blockStart = true
+	p.statements(b.Statements, b.Rbrace.Line)
+	p.blockStart = false
+	p.lastLine = outerLast
+	if p.maxLine < outerMax {
+		p.maxLine = outerMax
+	}
+
+	p.indent--
+	p.writeIndent()
+	p.write("}")
+	p.mark(b.Rbrace)
+}
+
+// EXPRESSIONS
+// ---------------------------------------------------------------------------------
+// literals and identifiers never need parentheses
+const highest = parser.INDEX + 1
+
+// precedence of an already parsed expression
+func precedenceOf(exp ast.Expression) int {
+	switch e := exp.(type) {
+	case *ast.InfixExpression:
+		return parser.Precedence(token.TokenType(e.Operator))
+	case *ast.PrefixExpression, *ast.SpreadExpression:
+		return parser.PREFIX
+	case *ast.CallExpression:
+		return parser.CALL
+	case *ast.IndexExpression, *ast.MemberExpression:
+		return parser.INDEX
+	case *ast.YieldExpression:
+		// its value take everything after it
+		return parser.LOWEST
+	default:
+		return highest
+	}
+}
+
+// Print an operand of an operator with precedence prec , adding parentheses only where they are needed
+// Operators are left associative so the right operand also need them on equal precedence
+func (p *printer) operand(exp ast.Expression, prec int, right bool) {
+	own := precedenceOf(exp)
+	if own < prec || (right && own == prec) {
+		p.write("(")
+		p.expression(exp)
+		p.write(")")
+		return
+	}
+	p.expression(exp)
+}
+
+func (p *printer) expression(exp ast.Expression) {
+	switch e := exp.(type) {
+	case *ast.Identifier:
+		p.mark(e.Token)
+		p.write(e.Value)
+	case *ast.IntegerLiteral:
+		p.mark(e.Token)
+		p.write(strconv.FormatInt(e.Value, 10))
+	case *ast.FloatLiteral:
+		p.mark(e.Token)
+		p.write(formatFloat(e.Value))
+	case *ast.StringLiteral:
+		p.mark(e.Token)
+		p.write(`"` + e.Value + `"`)
+	case *ast.BooleanLiteral:
+		p.mark(e.Token)
+		p.write(strconv.FormatBool(e.Value))
+	case *ast.PrefixExpression:
+		p.mark(e.Token)
+		p.write(e.Operator)
+		p.operand(e.Right, parser.PREFIX, false)
+	case *ast.InfixExpression:
+		p.mark(e.Token)
+		own := precedenceOf(e)
+		p.operand(e.Left, own, false)
+		p.write(" " + e.Operator + " ")
+		p.operand(e.Right, own, true)
+	case *ast.IfExpression:
+		p.mark(e.Token)
+		p.write("if (")
+		p.expression(e.Condition)
+		p.write(") ")
+		p.block(e.Consequence)
+		if e.Alternative != nil {
+			p.write(" else ")
+			p.block(e.Alternative)
+		}
+	case *ast.TryExpression:
+		p.mark(e.Token)
+		p.write("try ")
+		p.block(e.Body)
+		if e.Catch != nil {
+			p.write(" catch (")
+			p.mark(e.Param.Token)
+			p.write(e.Param.Value + ") ")
+			p.block(e.Catch)
+		}
+		if e.Finally != nil {
+			p.write(" finally ")
+			p.block(e.Finally)
+		}
+	case *ast.MatchExpression:
+		p.match(e)
+	case *ast.DefaultParameter:
+		p.expression(e.Target)
+		p.mark(e.Token)
+		p.write(" = ")
+		p.expression(e.Value)
+	case *ast.NamedArgument:
+		p.mark(e.Token)
+		p.write(e.Name.Value + ": ")
+		p.expression(e.Value)
+	case *ast.ArrayPattern:
+		p.mark(e.Token)
+		p.write("[")
+		for i, el := range e.Elements {
+			if i > 0 {
+				p.write(", ")
+			}
+			p.expression(el)
+		}
+		if e.Rest != nil {
+			if len(e.Elements) > 0 {
+				p.write(", ")
+			}
+			p.mark(e.Rest.Token)
+			p.write("..." + e.Rest.Value)
+		}
+		p.write("]")
+		p.mark(e.Rbracket)
+	case *ast.HashPattern:
+		p.mark(e.Token)
+		p.write("{")
+		for i, key := range e.Keys {
+			if i > 0 {
+				p.write(", ")
+			}
+			p.mark(key.Token)
+			p.write(key.Value)
+			if !e.IsShorthand(i) {
+				p.write(": ")
+				p.expression(e.Values[i])
+			}
+		}
+		p.write("}")
+		p.mark(e.Rbrace)
+	case *ast.SpreadExpression:
+		p.mark(e.Token)
+		p.write("...")
+		p.operand(e.Value, parser.PREFIX, false)
+	case *ast.FunctionLiteral:
+		// a generator is printed func* , also when a yield is what made it one
+		p.mark(e.Token)
+		if e.Generator {
+			p.write("func*(")
+		} else {
+			p.write("func(")
+		}
+		for i, param := range e.Parameters {
+			if i > 0 {
+				p.write(", ")
+			}
+			// the annotation go between the name and the default
+			if def, ok := param.(*ast.DefaultParameter); ok && e.ParameterType(i) != nil {
+				p.expression(def.Target)
+				p.annotation(e.ParameterType(i))
+				p.mark(def.Token)
+				p.write(" = ")
+				p.expression(def.Value)
+				continue
+			}
+			p.expression(param)
+			p.annotation(e.ParameterType(i))
+		}
+		p.write(")")
+		p.annotation(e.ReturnType)
+		p.write(" ")
+		p.block(e.Body)
+	case *ast.YieldExpression:
+		p.mark(e.Token)
+		p.write("yield")
+		if e.Value != nil {
+			p.write(" ")
+			p.expression(e.Value)
+		}
+	case *ast.ImportExpression:
+		p.mark(e.Token)
+		p.write("import(")
+		p.expression(e.Path)
+		p.write(")")
+	case *ast.CallExpression:
+		p.mark(e.Token)
+		p.operand(e.Function, parser.CALL, false)
+		p.write("(")
+		for i, arg := range e.Arguments {
+			if i > 0 {
+				p.write(", ")
+			}
+			p.expression(arg)
+		}
+		p.write(")")
+	case *ast.ArrayLiteral:
+		p.mark(e.Token)
+		p.write("[")
+		for i, el := range e.Elements {
+			if i > 0 {
+				p.write(", ")
+			}
+			p.expression(el)
+		}
+		p.write("]")
+		p.mark(e.Rbracket)
+	case *ast.IndexExpression:
+		p.mark(e.Token)
+		p.operand(e.Left, parser.INDEX, false)
+		p.write("[")
+		p.expression(e.Index)
+		p.write("]")
+		p.mark(e.Rbracket)
+	case *ast.HashLiteral:
+		p.mark(e.Token)
+		p.write("{")
+		for i, key := range e.Keys {
+			if i > 0 {
+				p.write(", ")
+			}
+			p.expression(key)
+			p.write(": ")
+			p.expression(e.Values[i])
+		}
+		p.write("}")
+		p.mark(e.Rbrace)
+	case *ast.MemberExpression:
+		p.mark(e.Token)
+		p.operand(e.Object, parser.INDEX, false)
+		p.write("." + e.Member.Value)
+	}
+}
+
+// Print each arm of a match on its own line , separated by commas
+func (p *printer) match(e *ast.MatchExpression) {
+	p.mark(e.Token)
+	p.write("match ")
+	p.expression(e.Subject)
+	if len(e.Arms) == 0 && !p.hasCommentBefore(e.Rbrace.Line) {
+		p.write(" {}")
+		p.mark(e.Rbrace)
+		return
+	}
+	p.write(" {")
+	p.newline()
+	p.indent++
+
+	outerLast, outerMax := p.lastLine, p.maxLine
+	p.lastLine = e.Token.Line
+	p.blockStart = true
+	for i, arm := range e.Arms {
+		p.flushComments(arm.Arrow.Line)
+		p.separate(arm.Arrow.Line)
+		p.maxLine = arm.Arrow.Line
+		p.writeIndent()
+		p.expression(arm.Pattern)
+		if arm.Guard != nil {
+			p.write(" if ")
+			p.expression(arm.Guard)
+		}
+		p.write(" => ")
+		p.statement(arm.Body)
+		if i < len(e.Arms)-1 {
+			p.write(",")
+		}
+		end := p.maxLine
+		p.trailingComments(lineBefore(end, e.Rbrace.Line))
+		p.newline()
+		if end > p.lastLine {
+			p.lastLine = end
+		}
+		if end > outerMax {
+			outerMax = end
+		}
+	}
+	p.flushComments(e.Rbrace.Line)
+	p.blockStart = false
+	p.lastLine = outerLast
+	p.maxLine = outerMax
+
+	p.indent--
+	p.writeIndent()
+	p.write("}")
+	p.mark(e.Rbrace)
+}
+
+// shortest form that read back to the same value , always with a dot so it stay a float
+func formatFloat(value float64) string {
+	text := strconv.FormatFloat(value, 'f', -1, 64)
+	if !strings.Contains(text, ".") {
+		text += ".0"
+	}
+	return text
+}
@@ -0,0 +1,209 @@
+package format
+
+import (
+	"khanhanh_lang/evaluator"
+	"khanhanh_lang/lexer"
+	"khanhanh_lang/object"
+	"khanhanh_lang/parser"
+	"testing"
+)
+
+func TestSource(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x=5", "let x = 5;\n"},
+		{"return  10", "return 10;\n"},
+		{"5+2*10", "5 + 2 * 10;\n"},
+		{"(5+2)*10", "(5 + 2) * 10;\n"},
+		{"1-(2-3)", "1 - (2 - 3);\n"},
+		{"(1-2)-3", "1 - 2 - 3;\n"},
+		{"-(5+5)", "-(5 + 5);\n"},
+		{"--10", "--10;\n"},
+		{"!(1<2)==false", "!(1 < 2) == false;\n"},
+		{`"hello"   + "world"`, "\"hello\" + \"world\";\n"},
+		{"add(1,2*3,add(4,5))", "add(1, 2 * 3, add(4, 5));\n"},
+		{"func(x,y){x+y}(1,2)", "func(x, y) {\n    x + y;\n}(1, 2);\n"},
+		{"let f = func(){}", "let f = func() {};\n"},
+		{
+			"if(1<2){10}else{20}",
+			"if (1 < 2) {\n    10;\n} else {\n    20;\n}\n",
+		},
+		{
+			"if(x){1}; -1",
+			"if (x) {\n    1;\n};\n-1;\n",
+		},
+		{
+			"if(x){1}; [1,2][0]",
+			"if (x) {\n    1;\n};\n[1, 2][0];\n",
+		},
+		{"1.50*2.0+ 0.25", "1.5 * 2.0 + 0.25;\n"},
+		{`let h={"a":1,2:[3],"c":{}}`, "let h = {\"a\": 1, 2: [3], \"c\": {}};\n"},
+		{`{"a":1}["a"]`, "{\"a\": 1}[\"a\"];\n"},
+		{"let a=[1,2*3,[]]", "let a = [1, 2 * 3, []];\n"},
+		{"(a[1])[2]+ -b[0]", "a[1][2] + -b[0];\n"},
+		{"(1+2)[0]", "(1 + 2)[0];\n"},
+		{"strings.split(s,\",\")", "strings.split(s, \",\");\n"},
+		{"(-a).b", "(-a).b;\n"},
+		{"try{f()}catch(e){e.message}finally{g()}", "try {\n    f();\n} catch (e) {\n    e.message;\n} finally {\n    g();\n}\n"},
+		{"try{1}finally{}; -1", "try {\n    1;\n} finally {};\n-1;\n"},
+		{"throw  \"x\"", "throw \"x\";\n"},
+		{"struct Point{x,y}", "struct Point { x, y };\n"},
+		{"struct Unit{ }", "struct Unit {};\n"},
+		{`import   "lib/list"`, "import \"lib/list\";\n"},
+		{`let m=import ( "lib/"+name ).f(1)`, "let m = import(\"lib/\" + name).f(1);\n"},
+		{
+			"match x{0=>\"zero\",[a,...rest]=>rest,{\"k\":v}if v>1=>{v},-1=>1,_=>2}",
+			"match x {\n    0 => \"zero\",\n    [a, ...rest] => rest,\n    {\"k\": v} if v > 1 => {\n        v;\n    },\n    -1 => 1,\n    _ => 2\n}\n",
+		},
+		{"match x{}; -1", "match x {};\n-1;\n"},
+		{"let[a,[b],...rest]=xs", "let [a, [b], ...rest] = xs;\n"},
+		{"const  LIMIT=10", "const LIMIT = 10;\n"},
+		{"const{a,b:[c]}=h", "const {a, b: [c]} = h;\n"},
+		{"let{name,age:[x,_]}=p", "let {name, age: [x, _]} = p;\n"},
+		{"func([...all],{k}){all}", "func([...all], {k}) {\n    all;\n};\n"},
+		{"func(a,b=1+2,...rest){a}", "func(a, b = 1 + 2, ...rest) {\n    a;\n};\n"},
+		{"f(1,...xs,by:2)", "f(1, ...xs, by: 2);\n"},
+		{"let x:int=5", "let x: int = 5;\n"},
+		{"let h :hash<string,array<int>> = {}", "let h: hash<string, array<int>> = {};\n"},
+		{"func(a:int,b:float=1,...rest:array<string>):bool{a}", "func(a: int, b: float = 1, ...rest: array<string>): bool {\n    a;\n};\n"},
+		{"func*(){yield}", "func*() {\n    yield;\n};\n"},
+		{"func(){let x=yield 1+2; f(yield,{yield:1}) ;yield(yield x)*2}",
+			"func*() {\n    let x = yield 1 + 2;\n    f(yield, {yield: 1});\n    yield (yield x) * 2;\n};\n"},
+		{"for[i,x]in enumerate(xs){print(i)}; -1", "for [i, x] in enumerate(xs) {\n    print(i);\n}\n-1;\n"},
+		{"for k in {}{}", "for k in {} {}\n"},
+		{
+			"let add=func(x){func(y){return x+y}}",
+			"let add = func(x) {\n    func(y) {\n        return x + y;\n    };\n};\n",
+		},
+	}
+
+	for _, test := range tests {
+		got, err := Source([]byte(test.input))
+		if err != nil {
+			t.Fatalf("input %q: unexpected error %s", test.input, err)
+		}
+		if string(got) != test.expected {
+			t.Errorf("input %q: expected\n%s\ngot\n%s", test.input, test.expected, got)
+		}
+	}
+}
+
+func TestSourceComments(t *testing.T) {
+	input := `// header comment
+let a = 1; // trailing
+
+// about b
+let b = func(x) {
+   // inside
+   x   // after x
+   // end of body
+};
+
+
+b(a) // call
+// last`
+
+	expected := `// header comment
+let a = 1; // trailing
+
+// about b
+let b = func(x) {
+    // inside
+    x; // after x
+    // end of body
+};
+
+b(a); // call
+// last
+`
+	got, err := Source([]byte(input))
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if string(got) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, got)
+	}
+}
+
+// a comment after the closing } of a block on the same line belong to the statement holding the block
+func TestSourceCommentAfterBlock(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let x = if (a > 1) { "big" } else { "small" }; // end of if`,
+			"let x = if (a > 1) {\n    \"big\";\n} else {\n    \"small\";\n}; // end of if\n"},
+		{"let f = func() {\n    1 // one\n}; // end of f",
+			"let f = func() {\n    1; // one\n}; // end of f\n"},
+		{"let y = match 1 { 1 => { 2 }, _ => 3 } // end of match",
+			"let y = match 1 {\n    1 => {\n        2;\n    },\n    _ => 3\n}; // end of match\n"},
+	}
+	for _, tt := range tests {
+		got, err := Source([]byte(tt.input))
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if string(got) != tt.expected {
+			t.Errorf("expected\n%s\ngot\n%s", tt.expected, got)
+		}
+	}
+}
+
+func TestSourceIdempotent(t *testing.T) {
+	inputs := []string{
+		"let x = 5; let y = x * (2 + 3); if (x > y) { x } else { y }",
+		"// only a comment",
+		"let f = func(a, b) {\n\n  // note\n  return a - -b;\n}; f(1, 2)(3)",
+		"let e = func() { // why\n}; e()",
+		"if (true) { 1 } -2",
+		"match x {\n  // zero\n  0 => 1, // one\n\n  _ => 2\n}",
+		"let g = func() {\n  for x in xs { // each\n    yield x }\n}",
+	}
+	for _, input := range inputs {
+		first, err := Source([]byte(input))
+		if err != nil {
+			t.Fatalf("input %q: unexpected error %s", input, err)
+		}
+		second, err := Source(first)
+		if err != nil {
+			t.Fatalf("formatted %q does not parse: %s", first, err)
+		}
+		if string(first) != string(second) {
+			t.Errorf("format is not idempotent\nfirst:\n%s\nsecond:\n%s", first, second)
+		}
+	}
+}
+
+func TestSourceKeepsMeaning(t *testing.T) {
+	inputs := []string{
+		"let a = 5; let b = a * (2 + 3) - (4 - 1); b",
+		"let add = func(x) { func(y) { x + y } }; add(2)(3)",
+		"if (10 < 11) { if (9 > 2) { return 9 } return 10 }",
+		"-(-(1 - 2)) * 3",
+		"if (true) { 1 } -2",
+		"match [1, 2, 3] { [x, ...rest] if x > 0 => rest, _ => 0 }",
+	}
+	for _, input := range inputs {
+		formatted, err := Source([]byte(input))
+		if err != nil {
+			t.Fatalf("input %q: unexpected error %s", input, err)
+		}
+		before, after := eval(input), eval(string(formatted))
+		if before.Inspect() != after.Inspect() {
+			t.Errorf("input %q evaluate to %s but formatted source evaluate to %s", input, before.Inspect(), after.Inspect())
+		}
+	}
+}
+
+func TestSourceError(t *testing.T) {
+	if _, err := Source([]byte("let = 5")); err == nil {
+		t.Errorf("expected an error for invalid source")
+	}
+}
+
+func eval(input string) object.Object {
+	p := parser.New(lexer.New(input))
+	return evaluator.Eval(p.ParseProgram(), object.NewTracker())
+}
@@ -5,14 +5,14 @@ go 1.20
 require (
 	github.com/diontr00/logStack v0.1.0
 	github.com/fatih/color v1.15.0
+	github.com/mattn/go-isatty v0.0.17
+	golang.org/x/sys v0.6.0
 )
 
 require (
 	github.com/mattn/go-colorable v0.1.13 // indirect
-	github.com/mattn/go-isatty v0.0.17 // indirect
 	go.uber.org/atomic v1.7.0 // indirect
 	go.uber.org/multierr v1.6.0 // indirect
 	go.uber.org/zap v1.24.0 // indirect
-	golang.org/x/sys v0.6.0 // indirect
 	gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
 )
@@ -0,0 +1,140 @@
+package host
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FS give access to the files under its roots only
+// A path is checked once every symlink is resolved , so a link inside a root cannot lead outside of it
+type FS struct {
+	Roots    []string // directories a script may access , with everything below them
+	Dir      string   // base of relative paths , the first root when empty
+	ReadOnly bool     // refuse every write
+}
+
+// ReadFile return the content of the file at path
+func (fs *FS) ReadFile(path string) ([]byte, error) {
+	resolved, err := fs.resolve(path)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(resolved)
+}
+
+// WriteFile create or replace the file at path , its directory must already exist
+func (fs *FS) WriteFile(path string, data []byte) error {
+	if fs.ReadOnly {
+		return fmt.Errorf("%s: %w , the filesystem is read only", path, ErrNotAllowed)
+	}
+	resolved, err := fs.resolve(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(resolved, data, 0644)
+}
+
+// ReadDir return the sorted names inside the directory at path , directories end with a slash
+func (fs *FS) ReadDir(path string) ([]string, error) {
+	resolved, err := fs.resolve(path)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(resolved)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			name += "/"
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Resolve return the real absolute path of path , or an error when it is outside every root
+func (fs *FS) Resolve(path string) (string, error) {
+	return fs.resolve(path)
+}
+
+// Rel return path relative to the base of relative paths when it is below it , path unchanged otherwise
+func (fs *FS) Rel(path string) string {
+	base := fs.Dir
+	if base == "" && len(fs.Roots) != 0 {
+		base = fs.Roots[0]
+	}
+	if real, err := realPath(base); err == nil {
+		base = real
+	}
+	if rel, err := filepath.Rel(base, path); err == nil && within(base, path) {
+		return rel
+	}
+	return path
+}
+
+func (fs *FS) resolve(path string) (string, error) {
+	if len(fs.Roots) == 0 {
+		return "", fmt.Errorf("%s: %w , no directory is shared", path, ErrNotAllowed)
+	}
+	if !filepath.IsAbs(path) {
+		base := fs.Dir
+		if base == "" {
+			base = fs.Roots[0]
+		}
+		path = filepath.Join(base, path)
+	}
+	real, err := realPath(filepath.Clean(path))
+	if err != nil {
+		return "", err
+	}
+	for _, root := range fs.Roots {
+		realRoot, err := realPath(filepath.Clean(root))
+		if err != nil {
+			continue
+		}
+		if within(realRoot, real) {
+			return real, nil
+		}
+	}
+	return "", fmt.Errorf("%s: %w , it is outside the shared directories", path, ErrNotAllowed)
+}
+
+// resolve the symlinks of path , a missing last element ( a file about to be written ) is kept as is
+func realPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	real, err := filepath.EvalSymlinks(abs)
+	if err == nil {
+		return real, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	// a dangling symlink must not be followed by a later write
+	if _, lerr := os.Lstat(abs); lerr == nil {
+		return "", fmt.Errorf("%s: %w , it is a dangling link", path, ErrNotAllowed)
+	}
+	dir, err := filepath.EvalSymlinks(filepath.Dir(abs))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, filepath.Base(abs)), nil
+}
+
+func within(root, path string) bool {
+	if root == path {
+		return true
+	}
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,116 @@
+package host
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// root/
+//
+//	in.txt
+//	sub/
+//	link_in -> in.txt
+//	link_out -> ../secret.txt
+//	dir_out -> ..
+//	dangling -> ../created.txt
+//
+// secret.txt
+func sandbox(t *testing.T) (root, outside string) {
+	outside = t.TempDir()
+	root = filepath.Join(outside, "root")
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(os.MkdirAll(filepath.Join(root, "sub"), 0755))
+	must(os.WriteFile(filepath.Join(root, "in.txt"), []byte("in"), 0644))
+	must(os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0644))
+	must(os.Symlink("in.txt", filepath.Join(root, "link_in")))
+	must(os.Symlink(filepath.Join("..", "secret.txt"), filepath.Join(root, "link_out")))
+	must(os.Symlink("..", filepath.Join(root, "dir_out")))
+	must(os.Symlink(filepath.Join("..", "created.txt"), filepath.Join(root, "dangling")))
+	return root, outside
+}
+
+func TestFSStayInRoots(t *testing.T) {
+	root, outside := sandbox(t)
+	fs := &FS{Roots: []string{root}}
+
+	allowed := []string{"in.txt", "link_in", "./sub/../in.txt", filepath.Join(root, "in.txt")}
+	for _, path := range allowed {
+		if data, err := fs.ReadFile(path); err != nil || string(data) != "in" {
+			t.Errorf("%s should be readable. got=%q, %v", path, data, err)
+		}
+	}
+
+	denied := []string{
+		"../secret.txt",
+		"sub/../../secret.txt",
+		filepath.Join(outside, "secret.txt"),
+		"link_out",
+		"dir_out/secret.txt",
+		"/etc/passwd",
+	}
+	for _, path := range denied {
+		if data, err := fs.ReadFile(path); !errors.Is(err, ErrNotAllowed) {
+			t.Errorf("%s should be denied. got=%q, %v", path, data, err)
+		}
+	}
+
+	for _, path := range []string{"dangling", "dir_out/created.txt", "../created.txt"} {
+		if err := fs.WriteFile(path, []byte("x")); !errors.Is(err, ErrNotAllowed) {
+			t.Errorf("writing %s should be denied. got=%v", path, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outside, "created.txt")); !os.IsNotExist(err) {
+		t.Errorf("a file was created outside the root")
+	}
+}
+
+func TestFSWriteAndList(t *testing.T) {
+	root, _ := sandbox(t)
+	fs := &FS{Roots: []string{root}, Dir: filepath.Join(root, "sub")}
+
+	if err := fs.WriteFile("out.txt", []byte("out")); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "sub", "out.txt")); err != nil || string(data) != "out" {
+		t.Errorf("relative paths should start at Dir. got=%q, %v", data, err)
+	}
+
+	names, err := fs.ReadDir("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{"dangling", "dir_out", "in.txt", "link_in", "link_out", "sub/"}
+	if len(names) != len(expect) {
+		t.Fatalf("wrong names. got=%v", names)
+	}
+	for i := range expect {
+		if names[i] != expect[i] {
+			t.Errorf("names[%d] wrong. expect=%q got=%q", i, expect[i], names[i])
+		}
+	}
+}
+
+func TestFSReadOnlyAndEmpty(t *testing.T) {
+	root, _ := sandbox(t)
+	readOnly := &FS{Roots: []string{root}, ReadOnly: true}
+	if err := readOnly.WriteFile("in.txt", []byte("x")); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("a read only FS should refuse to write. got=%v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "in.txt")); string(data) != "in" {
+		t.Errorf("the file was modified. got=%q", data)
+	}
+	if _, err := readOnly.ReadFile("in.txt"); err != nil {
+		t.Errorf("a read only FS should still read. got=%v", err)
+	}
+
+	if _, err := (&FS{}).ReadFile(filepath.Join(root, "in.txt")); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("an FS without roots should refuse everything. got=%v", err)
+	}
+}
@@ -0,0 +1,82 @@
+// Package host describe what a script may reach outside the interpreter : the standard streams and the
+// filesystem. The embedding program decide it , a script only get what its Host allow.
+package host
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+)
+
+// ErrNotAllowed is returned for every access the Host does not allow
+var ErrNotAllowed = errors.New("not allowed by the host")
+
+// Host hold the capabilities given to a script , a nil field deny the matching access
+type Host struct {
+	Stdin  io.Reader // read_line
+	Stdout io.Writer // print
+	Stderr io.Writer // eprint
+	FS     *FS       // read_file , write_file , list_dir
+
+	stdin *bufio.Reader
+}
+
+// Unrestricted give the process standard streams and the whole filesystem , for the trusted command line
+// relative paths start at the working directory
+func Unrestricted() *Host {
+	dir, _ := os.Getwd()
+	return &Host{
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+		FS:     &FS{Roots: []string{string(os.PathSeparator)}, Dir: dir},
+	}
+}
+
+// ReadLine return the next line of Stdin without its line ending , io.EOF once Stdin is exhausted
+func (h *Host) ReadLine() (string, error) {
+	if h.Stdin == nil {
+		return "", ErrNotAllowed
+	}
+	if h.stdin == nil {
+		h.stdin = bufio.NewReader(h.Stdin)
+	}
+	line, err := h.stdin.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	if err != nil {
+		return "", err
+	}
+	line = line[:len(line)-len(lineEnding(line))]
+	return line, nil
+}
+
+func lineEnding(line string) string {
+	switch {
+	case len(line) >= 2 && line[len(line)-2:] == "\r\n":
+		return "\r\n"
+	case len(line) >= 1 && line[len(line)-1] == '\n':
+		return "\n"
+	}
+	return ""
+}
+
+// Print write text to Stdout
+func (h *Host) Print(text string) error {
+	return write(h.Stdout, text)
+}
+
+// Eprint write text to Stderr
+func (h *Host) Eprint(text string) error {
+	return write(h.Stderr, text)
+}
+
+func write(w io.Writer, text string) error {
+	if w == nil {
+		return ErrNotAllowed
+	}
+	_, err := io.WriteString(w, text)
+	return err
+}
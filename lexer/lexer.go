@@ -7,17 +7,26 @@ type Lexer struct {
 	position     int    // position in the input (point to  ch)
 	readPosition int    // read position in the input (after ch )  , always point to the next position where we're going to read from
 	ch           rune   // current character support UTF8
+	line         int    // line of ch , start at 1
+	column       int    // column of ch , start at 1
+
+	comments []token.Token // comments skipped so far , kept for tools like the formatter
 }
 
 // Create new lexer
 func New(input string) *Lexer {
-	l := &Lexer{input: input}
+	l := &Lexer{input: input, line: 1}
 	l.readChar()
 	return l
 }
 
 // read the next character and advance our position in the input string
 func (l *Lexer) readChar() {
+	if l.ch == '\n' {
+		l.line += 1
+		l.column = 0
+	}
+	l.column += 1
 	if l.readPosition >= len(l.input) {
 		l.ch = 0 // NUL
 	} else {
@@ -29,14 +38,30 @@ func (l *Lexer) readChar() {
 
 // use to tokenize the input string current char and call readChar internally to advance the readPosition to the next char
 func (l *Lexer) NextToken() token.Token {
-	var resultToken token.Token
 	l.skipWhiteSpace()
+	line, column := l.line, l.column
+	resultToken := l.readToken()
+	resultToken.Line = line
+	resultToken.Column = column
+	return resultToken
+}
+
+// Comments return every comment the lexer has skipped so far , in source order
+func (l *Lexer) Comments() []token.Token {
+	return l.comments
+}
+
+func (l *Lexer) readToken() token.Token {
+	var resultToken token.Token
 	switch l.ch {
 	case '=':
 		if l.peekChar() == '=' {
 			ch := l.ch
 			l.readChar()
 			resultToken = token.Token{Type: token.EQ, Literal: string(ch) + string(l.ch)}
+		} else if l.peekChar() == '>' {
+			l.readChar()
+			resultToken = token.Token{Type: token.ARROW, Literal: "=>"}
 		} else {
 			resultToken = newToken(token.ASSIGN, l.ch)
 		}
@@ -81,6 +106,20 @@ func (l *Lexer) NextToken() token.Token {
 		resultToken = newToken(token.LBRACE, l.ch)
 	case '}':
 		resultToken = newToken(token.RBRACE, l.ch)
+	case '[':
+		resultToken = newToken(token.LBRACKET, l.ch)
+	case ']':
+		resultToken = newToken(token.RBRACKET, l.ch)
+	case '.':
+		if l.peekChar() == '.' && l.readPosition+1 < len(l.input) && l.input[l.readPosition+1] == '.' {
+			l.readChar()
+			l.readChar()
+			resultToken = token.Token{Type: token.ELLIPSIS, Literal: "..."}
+		} else {
+			resultToken = newToken(token.DOT, l.ch)
+		}
+	case ':':
+		resultToken = newToken(token.COLON, l.ch)
 	case ';':
 		resultToken = newToken(token.SEMICOLON, l.ch)
 	case ',':
@@ -89,8 +128,16 @@ func (l *Lexer) NextToken() token.Token {
 		resultToken.Literal = ""
 		resultToken.Type = token.EOF
 	case '"':
-		resultToken.Type = token.STRING
-		resultToken.Literal = l.readString()
+		literal, terminated := l.readString()
+		if terminated {
+			resultToken.Type = token.STRING
+			resultToken.Literal = literal
+		} else {
+			// keep the opening quote , so the parser can tell an unterminated string from other illegal input
+			resultToken.Type = token.ILLEGAL
+			resultToken.Literal = `"` + literal
+			return resultToken
+		}
 
 	default:
 		if isLetter(l.ch) {
@@ -99,8 +146,12 @@ func (l *Lexer) NextToken() token.Token {
 			resultToken.Type = token.LookUpKeyword(resultToken.Literal)
 			return resultToken
 		} else if isDigit(l.ch) {
-			resultToken.Literal = l.readNumber()
+			literal, isFloat := l.readNumber()
+			resultToken.Literal = literal
 			resultToken.Type = token.INT
+			if isFloat {
+				resultToken.Type = token.FLOAT
+			}
 			return resultToken
 		} else {
 			resultToken = newToken(token.ILLEGAL, l.ch)
@@ -132,18 +183,24 @@ func (l *Lexer) readIdentifier() string {
 	return l.input[position:l.position]
 }
 
-// Read Number
-func (l *Lexer) readNumber() string {
+// Read Number , a dot followed by a digit make it a float ( 1.5 ) , otherwise the dot is left for a member access
+func (l *Lexer) readNumber() (string, bool) {
 	position := l.position
 	for isDigit(l.ch) {
 		l.readChar()
 	}
-	return l.input[position:l.position]
-
+	isFloat := l.ch == '.' && isDigit(l.peekChar())
+	if isFloat {
+		l.readChar()
+		for isDigit(l.ch) {
+			l.readChar()
+		}
+	}
+	return l.input[position:l.position], isFloat
 }
 
-// Read String literal
-func (l *Lexer) readString() string {
+// Read String literal , report false when the input end before the closing quote
+func (l *Lexer) readString() (string, bool) {
 	position := l.position + 1
 	for {
 		l.readChar()
@@ -151,20 +208,38 @@ func (l *Lexer) readString() string {
 			break
 		}
 	}
-	return l.input[position:l.position]
+	return l.input[position:l.position], l.ch == '"'
 
 }
 
-// ignore the white space in the input
+// ignore the white space and comments in the input
 func (l *Lexer) skipWhiteSpace() {
-	for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
+	for {
+		switch {
+		case l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r':
+			l.readChar()
+		case l.ch == '/' && l.peekChar() == '/':
+			l.readComment()
+		default:
+			return
+		}
+	}
+}
+
+// Read a line comment until the end of line , and record it
+func (l *Lexer) readComment() {
+	comment := token.Token{Type: token.COMMENT, Line: l.line, Column: l.column}
+	position := l.position
+	for l.ch != '\n' && l.ch != 0 {
 		l.readChar()
 	}
+	comment.Literal = l.input[position:l.position]
+	l.comments = append(l.comments, comment)
 }
 
 // Peek ahead not moving the readPosition or position
 func (l *Lexer) peekChar() rune {
-	if l.position > len(l.input) {
+	if l.readPosition >= len(l.input) {
 		return 0
 	} else {
 		return rune(l.input[l.readPosition])
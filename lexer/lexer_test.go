@@ -194,3 +194,198 @@ func TestExtendNextToken(t *testing.T) {
 	}
 
 }
+
+func TestTokenPosition(t *testing.T) {
+	input := `let x = 5;
+  x + "a b" // trailing
+// own line
+  >=`
+
+	tests := []struct {
+		expectedType   token.TokenType
+		expectedLine   int
+		expectedColumn int
+	}{
+		{token.LET, 1, 1},
+		{token.IDENT, 1, 5},
+		{token.ASSIGN, 1, 7},
+		{token.INT, 1, 9},
+		{token.SEMICOLON, 1, 10},
+		{token.IDENT, 2, 3},
+		{token.PLUS, 2, 5},
+		{token.STRING, 2, 7},
+		{token.GT_EQ, 4, 3},
+		{token.EOF, 4, 5},
+	}
+
+	lex := New(input)
+	for _, test := range tests {
+		test_token := lex.NextToken()
+		if test_token.Type != test.expectedType {
+			t.Fatalf("wrong token type , expect : %q , got %q", test.expectedType, test_token.Type)
+		}
+		if test_token.Line != test.expectedLine || test_token.Column != test.expectedColumn {
+			t.Fatalf(
+				"wrong position for %q , expect %d:%d , got %d:%d",
+				test_token.Literal,
+				test.expectedLine,
+				test.expectedColumn,
+				test_token.Line,
+				test_token.Column,
+			)
+		}
+	}
+
+	comments := lex.Comments()
+	if len(comments) != 2 {
+		t.Fatalf("expected 2 comments , got %d", len(comments))
+	}
+	if comments[0].Literal != "// trailing" || comments[0].Line != 2 || comments[0].Column != 13 {
+		t.Errorf("wrong first comment , got %q at %d:%d", comments[0].Literal, comments[0].Line, comments[0].Column)
+	}
+	if comments[1].Literal != "// own line" || comments[1].Line != 3 || comments[1].Column != 1 {
+		t.Errorf("wrong second comment , got %q at %d:%d", comments[1].Literal, comments[1].Line, comments[1].Column)
+	}
+}
+
+func TestPeekAtEndOfInput(t *testing.T) {
+	for _, input := range []string{"=", "!", "<", ">", "/"} {
+		lex := New(input)
+		lex.NextToken()
+		if tok := lex.NextToken(); tok.Type != token.EOF {
+			t.Errorf("input %q: expected EOF after the operator , got %q", input, tok.Type)
+		}
+	}
+}
+
+func TestCollectionToken(t *testing.T) {
+	input := `[1, "a"][0]; strings.split`
+	expected := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.LBRACKET, "["},
+		{token.INT, "1"},
+		{token.COMMA, ","},
+		{token.STRING, "a"},
+		{token.RBRACKET, "]"},
+		{token.LBRACKET, "["},
+		{token.INT, "0"},
+		{token.RBRACKET, "]"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "strings"},
+		{token.DOT, "."},
+		{token.IDENT, "split"},
+		{token.EOF, ""},
+	}
+
+	lex := New(input)
+	for i, test := range expected {
+		tok := lex.NextToken()
+		if tok.Type != test.expectedType || tok.Literal != test.expectedLiteral {
+			t.Fatalf("tests[%d] - expect %q %q , got %q %q", i, test.expectedType, test.expectedLiteral, tok.Type, tok.Literal)
+		}
+	}
+}
+
+func TestMatchToken(t *testing.T) {
+	input := `match x { [a, ...rest] => a, _ => 0 }; a.b; a..b`
+	expected := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.MATCH, "match"},
+		{token.IDENT, "x"},
+		{token.LBRACE, "{"},
+		{token.LBRACKET, "["},
+		{token.IDENT, "a"},
+		{token.COMMA, ","},
+		{token.ELLIPSIS, "..."},
+		{token.IDENT, "rest"},
+		{token.RBRACKET, "]"},
+		{token.ARROW, "=>"},
+		{token.IDENT, "a"},
+		{token.COMMA, ","},
+		{token.IDENT, "_"},
+		{token.ARROW, "=>"},
+		{token.INT, "0"},
+		{token.RBRACE, "}"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "a"},
+		{token.DOT, "."},
+		{token.IDENT, "b"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "a"},
+		{token.DOT, "."},
+		{token.DOT, "."},
+		{token.IDENT, "b"},
+		{token.EOF, ""},
+	}
+
+	lex := New(input)
+	for i, test := range expected {
+		tok := lex.NextToken()
+		if tok.Type != test.expectedType || tok.Literal != test.expectedLiteral {
+			t.Fatalf("tests[%d] - expect %q %q , got %q %q", i, test.expectedType, test.expectedLiteral, tok.Type, tok.Literal)
+		}
+	}
+}
+
+func TestFloatToken(t *testing.T) {
+	input := `1.5 10 0.25 3.x 4.`
+	expected := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.FLOAT, "1.5"},
+		{token.INT, "10"},
+		{token.FLOAT, "0.25"},
+		{token.INT, "3"},
+		{token.DOT, "."},
+		{token.IDENT, "x"},
+		{token.INT, "4"},
+		{token.DOT, "."},
+		{token.EOF, ""},
+	}
+
+	lex := New(input)
+	for i, test := range expected {
+		tok := lex.NextToken()
+		if tok.Type != test.expectedType || tok.Literal != test.expectedLiteral {
+			t.Fatalf("tests[%d] - expect %q %q , got %q %q", i, test.expectedType, test.expectedLiteral, tok.Type, tok.Literal)
+		}
+	}
+}
+
+func TestGeneratorTokens(t *testing.T) {
+	input := `func*() { yield 1 }; for x in xs { x }`
+	expected := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.FUNCTION, "func"},
+		{token.ASTERISK, "*"},
+		{token.LPAREN, "("},
+		{token.RPAREN, ")"},
+		{token.LBRACE, "{"},
+		{token.YIELD, "yield"},
+		{token.INT, "1"},
+		{token.RBRACE, "}"},
+		{token.SEMICOLON, ";"},
+		{token.FOR, "for"},
+		{token.IDENT, "x"},
+		{token.IN, "in"},
+		{token.IDENT, "xs"},
+		{token.LBRACE, "{"},
+		{token.IDENT, "x"},
+		{token.RBRACE, "}"},
+		{token.EOF, ""},
+	}
+	l := New(input)
+	for i, test := range expected {
+		tok := l.NextToken()
+		if tok.Type != test.expectedType || tok.Literal != test.expectedLiteral {
+			t.Fatalf("tests[%d] - expect %q %q , got %q %q", i, test.expectedType, test.expectedLiteral, tok.Type, tok.Literal)
+		}
+	}
+}
@@ -6,5 +6,25 @@ import (
 )
 
 func main() {
-	repl.Start(os.Stdin, os.Stdout)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "check":
+			os.Exit(runCheck(os.Args[2:]))
+		case "fmt":
+			os.Exit(runFmt(os.Args[2:]))
+		case "lex":
+			os.Exit(runLex(os.Args[2:]))
+		case "parse":
+			os.Exit(runParse(os.Args[2:]))
+		case "run":
+			os.Exit(runRun(os.Args[2:]))
+		}
+	}
+	config := repl.DefaultConfig()
+	for _, arg := range os.Args[1:] {
+		if arg == "--no-color" {
+			config.NoColor = true
+		}
+	}
+	repl.Run(config)
 }
@@ -0,0 +1,199 @@
+package object
+
+import "runtime"
+
+// GENERATOR
+// ------------------------------------------------------------------------
+// A call of a generator function return a Generator , its body run on a goroutine of its own one step at a time :
+// next resume the body and wait until it yield or end , so the script never run on two goroutines at once.
+// The goroutine is started by the first next and end with the body . close resume the body with an error that
+// no catch stop , only the finally blocks run while it unwind . A generator dropped while suspended is abandoned
+// once the garbage collector find it , its goroutine unwind without running anything more of the script.
+// The goroutine hold the state but never the Generator , so the Generator can be collected while the body wait ,
+// a Generator the body can still reach through a variable is abandoned when its session is closed , see
+// Tracker.Close . next on an abandoned generator is an error
+type Generator struct {
+	Function *Function
+	state    *generatorState
+}
+
+func (g *Generator) Type() ObjectType { return GENERATOR_OBJ }
+func (g *Generator) Inspect() string  { return "generator" }
+
+type generatorState struct {
+	run     func(env *Tracker) Object // evaluate the body in env and return its result
+	env     *Tracker
+	resumes chan resumption // from next and close to the suspended body
+	steps   chan step       // from the body to the waiting next
+
+	collected chan struct{}   // closed by the finalizer of the Generator
+	closed    <-chan struct{} // closed with the session
+
+	started   bool
+	running   bool // the body run , the caller wait for its next step
+	done      bool
+	abandoned bool // its session was closed while it was suspended
+	closing   bool // the body unwind after a close , written and read by the body goroutine only
+}
+
+// what resume a suspended body
+type resumption struct {
+	sent  Object // the value of the yield
+	close bool
+}
+
+// what the body hand back , a yielded value or its result when done
+type step struct {
+	value Object
+	done  bool
+}
+
+// panic unwinding the goroutine of an abandoned generator , recovered at its top
+type abandoned struct{}
+
+// Value of the error unwinding a closed generator
+var generatorClosed = &String{Value: "generator closed"}
+
+// NewGenerator return the generator of a call of fn , run evaluate the body in env the first time it is resumed
+func NewGenerator(fn *Function, env *Tracker, run func(env *Tracker) Object) *Generator {
+	state := &generatorState{
+		run:       run,
+		env:       env,
+		resumes:   make(chan resumption),
+		steps:     make(chan step),
+		collected: make(chan struct{}),
+	}
+	if env.session != nil {
+		state.closed = env.session.closed
+	}
+	env.generator = state
+	g := &Generator{Function: fn, state: state}
+	runtime.SetFinalizer(g, func(g *Generator) { close(g.state.collected) })
+	return g
+}
+
+// Next run the body until its next yield and return the yielded value , sent become the value of the yield the body
+// was suspended in , the first call start the body and ignore sent . When the body end Next return its result , or
+// the error it raised , with done , and nil with done for every later call . Once the session is closed it is an error
+func (g *Generator) Next(sent Object) (value Object, done bool) {
+	defer runtime.KeepAlive(g)
+	s := g.state
+	switch {
+	case s.abandoned:
+		return abandonedError(), true
+	case s.done:
+		return nil, true
+	case s.running:
+		return &Error{Message: "[Error]: generator already running"}, false
+	case s.sessionClosed():
+		s.abandon()
+		return abandonedError(), true
+	}
+	return s.resume(resumption{sent: sent})
+}
+
+// Close end the generator , a suspended body unwind from its yield running its finally blocks on the way ,
+// it return an error raised while unwinding and nil otherwise , closing an ended generator do nothing
+func (g *Generator) Close() Object {
+	defer runtime.KeepAlive(g)
+	s := g.state
+	switch {
+	case s.running:
+		return &Error{Message: "[Error]: generator already running"}
+	case s.done:
+		return nil
+	case !s.started:
+		s.finish()
+		return nil
+	case s.sessionClosed():
+		s.abandon()
+		return nil
+	}
+	result, _ := s.resume(resumption{close: true})
+	if err, ok := result.(*Error); ok && !err.Closing() {
+		return err
+	}
+	return nil
+}
+
+// Closing report whether the error unwind a closed generator , try let it through without catching it
+func (e *Error) Closing() bool {
+	return e.Value == generatorClosed
+}
+
+func (s *generatorState) resume(r resumption) (Object, bool) {
+	s.running = true
+	if !s.started {
+		s.started = true
+		go s.body()
+	} else {
+		select {
+		case s.resumes <- r:
+		case <-s.closed:
+			s.abandon()
+			return abandonedError(), true
+		}
+	}
+	next := <-s.steps
+	s.running = false
+	if next.done {
+		s.finish()
+	}
+	return next.value, next.done
+}
+
+func (s *generatorState) finish() {
+	s.done = true
+	s.run, s.env = nil, nil
+}
+
+func (s *generatorState) abandon() {
+	s.finish()
+	s.abandoned = true
+}
+
+func (s *generatorState) sessionClosed() bool {
+	select {
+	case <-s.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func abandonedError() *Error {
+	return &Error{Message: "[Error]: generator abandoned , its session is closed"}
+}
+
+// the goroutine of the generator
+func (s *generatorState) body() {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(abandoned); !ok {
+				panic(r)
+			}
+		}
+	}()
+	s.steps <- step{value: s.run(s.env), done: true}
+}
+
+// hand value to the waiting next and suspend the body until it is resumed
+func (s *generatorState) yield(value Object) Object {
+	if s.closing {
+		return &Error{Message: "[Error]: generator closed", Value: generatorClosed}
+	}
+	s.steps <- step{value: value}
+	var r resumption
+	select {
+	case r = <-s.resumes:
+	case <-s.collected:
+		panic(abandoned{})
+	case <-s.closed:
+		panic(abandoned{})
+	}
+	if r.close {
+		s.closing = true
+		return &Error{Message: "[Error]: generator closed", Value: generatorClosed}
+	}
+	return r.sent
+}
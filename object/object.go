@@ -6,6 +6,9 @@ import (
 	"bytes"
 	"fmt"
 	"khanhanh_lang/ast"
+	"khanhanh_lang/token"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -13,12 +16,21 @@ type ObjectType string
 
 const (
 	INTEGER_OBJ      = "INTEGER"
+	FLOAT_OBJ        = "FLOAT"
 	BOOLEAN_OBJ      = "BOOLEAN"
 	NIL_OBJ          = "NIL"
 	STRING_OBJ       = "STRING"
 	RETURN_VALUE_OBJ = "RETURN_VALUE"
 	ERROR_OBJ        = "ERROR"
 	FUNCTION_OBJ     = "FUNCTION"
+	ARRAY_OBJ        = "ARRAY"
+	BUILTIN_OBJ      = "BUILTIN"
+	MODULE_OBJ       = "MODULE"
+	HASH_OBJ         = "HASH"
+	STRUCT_OBJ       = "STRUCT"
+	INSTANCE_OBJ     = "INSTANCE"
+	EXCEPTION_OBJ    = "EXCEPTION"
+	GENERATOR_OBJ    = "GENERATOR"
 )
 
 // Every value is wrapped inside a struct , which fulfill the Object interface
@@ -37,6 +49,22 @@ type Integer struct {
 func (i *Integer) Inspect() string  { return fmt.Sprintf("%d", i.Value) }
 func (i *Integer) Type() ObjectType { return INTEGER_OBJ }
 
+// FLOAT
+// -------------------------------------------------------------------
+type Float struct {
+	Value float64
+}
+
+// always show a dot or an exponent , so 2.0 is not mistaken for the integer 2
+func (f *Float) Inspect() string {
+	text := strconv.FormatFloat(f.Value, 'g', -1, 64)
+	if !strings.ContainsAny(text, ".eIN") {
+		text += ".0"
+	}
+	return text
+}
+func (f *Float) Type() ObjectType { return FLOAT_OBJ }
+
 // BOOLEAN
 // --------------------------------------------------------------------
 type Boolean struct {
@@ -76,17 +104,59 @@ func (rv *ReturnValue) Type() ObjectType { return RETURN_VALUE_OBJ }
 // ERROR
 type Error struct {
 	Message string
+	Pos     token.Position // where the error was raised in the source , zero when unknown
+	Kind    string         // given by throw , empty for the errors raised by the interpreter
+	Value   Object         // the thrown value , nil for the errors raised by the interpreter
+	Stack   []Frame        // function calls the error went through , innermost first
 }
 
 func (e *Error) Type() ObjectType { return ERROR_OBJ }
 func (e *Error) Inspect() string  { return e.Message }
 
+// Frame is a call of a function an error went through
+type Frame struct {
+	Function string         // the called expression , as it is written
+	Pos      token.Position // the call
+}
+
+func (f Frame) String() string {
+	return fmt.Sprintf("%s at %d:%d", f.Function, f.Pos.Line, f.Pos.Column)
+}
+
+// EXCEPTION
+// ------------------------------------------------------------------------
+// an error caught by try , bound to the catch parameter as an ordinary value so it does not abort anymore
+type Exception struct {
+	Err *Error
+}
+
+func (e *Exception) Type() ObjectType { return EXCEPTION_OBJ }
+func (e *Exception) Inspect() string  { return e.Kind() + ": " + e.Message() }
+
+// Kind of the error , RuntimeError for the errors raised by the interpreter
+func (e *Exception) Kind() string {
+	if e.Err.Kind == "" {
+		return "RuntimeError"
+	}
+	return e.Err.Kind
+}
+
+// Message of the error without its [Error]: prefix
+func (e *Exception) Message() string {
+	return strings.TrimPrefix(e.Err.Message, "[Error]: ")
+}
+
 // FUNCTION
 // ------------------------------------------------------------------------
 type Function struct {
-	Parameters []*ast.Identifier
+	Parameters []ast.Expression // names and destructuring patterns
 	Body       *ast.BlockStatement
 	Env        *Tracker
+
+	ParameterTypes []*ast.Type // annotations of the parameters , as in ast.FunctionLiteral
+	ReturnType     *ast.Type
+
+	Generator bool // a call return a Generator running Body instead of running it
 }
 
 func (f *Function) Type() ObjectType { return FUNCTION_OBJ }
@@ -94,15 +164,209 @@ func (f *Function) Inspect() string {
 	var out bytes.Buffer
 
 	params := []string{}
-	for _, p := range f.Parameters {
-		params = append(params, p.String())
+	for i, p := range f.Parameters {
+		params = append(params, ast.ParameterString(p, f.ParameterType(i)))
 	}
 	out.WriteString("func")
+	if f.Generator {
+		out.WriteString("*")
+	}
 	out.WriteString("(")
 	out.WriteString(strings.Join(params, ", "))
-	out.WriteString(") {\n")
+	out.WriteString(")")
+	if f.ReturnType != nil {
+		out.WriteString(": " + f.ReturnType.String())
+	}
+	out.WriteString(" {\n")
 	out.WriteString(f.Body.String())
 	out.WriteString("\n}")
 
 	return out.String()
 }
+
+// ParameterType return the annotation of the parameter i , nil when it has none
+func (f *Function) ParameterType(i int) *ast.Type {
+	if i < len(f.ParameterTypes) {
+		return f.ParameterTypes[i]
+	}
+	return nil
+}
+
+// ARRAY
+// ------------------------------------------------------------------------
+type Array struct {
+	Elements []Object
+}
+
+func (a *Array) Type() ObjectType { return ARRAY_OBJ }
+func (a *Array) Inspect() string {
+	elements := []string{}
+	for _, el := range a.Elements {
+		elements = append(elements, inspectElement(el))
+	}
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// strings nested in a collection are quoted , so ["1"] and [1] can be told apart
+func inspectElement(obj Object) string {
+	if s, ok := obj.(*String); ok {
+		return strconv.Quote(s.Value)
+	}
+	return obj.Inspect()
+}
+
+// BUILTIN
+// ------------------------------------------------------------------------
+// function implemented in Go , it receive the already evaluated arguments
+type BuiltinFunction func(args ...Object) Object
+
+type Builtin struct {
+	Name string // qualified name used in error messages , as strings.split
+	Fn   BuiltinFunction
+}
+
+func (b *Builtin) Type() ObjectType { return BUILTIN_OBJ }
+func (b *Builtin) Inspect() string  { return "builtin " + b.Name }
+
+// MODULE
+// ------------------------------------------------------------------------
+// named group of values , whose members are accessed with module.member
+type Module struct {
+	Name    string
+	Members map[string]Object
+}
+
+func (m *Module) Type() ObjectType { return MODULE_OBJ }
+func (m *Module) Inspect() string  { return "module " + m.Name }
+
+// Names return the name of every member , sorted
+func (m *Module) Names() []string {
+	names := make([]string, 0, len(m.Members))
+	for name := range m.Members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HASH
+// ------------------------------------------------------------------------
+// Key of a value inside a hash , two values with the same content have the same key and two different values
+// never do : a string is kept whole in Text rather than digested , so no two strings can collide
+type HashKey struct {
+	Type  ObjectType
+	Value uint64
+	Text  string
+}
+
+// Hashable is implemented by the values usable as a hash key
+type Hashable interface {
+	Object
+	HashKey() HashKey
+}
+
+func (i *Integer) HashKey() HashKey { return HashKey{Type: i.Type(), Value: uint64(i.Value)} }
+
+func (b *Boolean) HashKey() HashKey {
+	if b.Value {
+		return HashKey{Type: b.Type(), Value: 1}
+	}
+	return HashKey{Type: b.Type(), Value: 0}
+}
+
+func (s *String) HashKey() HashKey { return HashKey{Type: s.Type(), Text: s.Value} }
+
+type HashPair struct {
+	Key   Object
+	Value Object
+}
+
+// Hash keep its pairs in insertion order , so it print and iterate the same way every time
+type Hash struct {
+	pairs map[HashKey]int // index in order
+	order []HashPair
+}
+
+func NewHash() *Hash {
+	return &Hash{pairs: map[HashKey]int{}}
+}
+
+func (h *Hash) Type() ObjectType { return HASH_OBJ }
+func (h *Hash) Inspect() string {
+	pairs := []string{}
+	for _, pair := range h.order {
+		pairs = append(pairs, inspectElement(pair.Key)+": "+inspectElement(pair.Value))
+	}
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// Set add a pair , or replace the value of an existing key keeping its position
+func (h *Hash) Set(key Hashable, value Object) {
+	if i, ok := h.pairs[key.HashKey()]; ok {
+		h.order[i].Value = value
+		return
+	}
+	h.pairs[key.HashKey()] = len(h.order)
+	h.order = append(h.order, HashPair{Key: key, Value: value})
+}
+
+func (h *Hash) Get(key Hashable) (Object, bool) {
+	if i, ok := h.pairs[key.HashKey()]; ok {
+		return h.order[i].Value, true
+	}
+	return nil, false
+}
+
+// Pairs return every pair in insertion order
+func (h *Hash) Pairs() []HashPair { return h.order }
+
+func (h *Hash) Len() int { return len(h.order) }
+
+// STRUCT
+// ------------------------------------------------------------------------
+// record type declared with struct Name { field, ... } , calling it build an Instance
+type Struct struct {
+	Name   string
+	Fields []string
+}
+
+func (s *Struct) Type() ObjectType { return STRUCT_OBJ }
+func (s *Struct) Inspect() string {
+	if len(s.Fields) == 0 {
+		return "struct " + s.Name + " {}"
+	}
+	return "struct " + s.Name + " { " + strings.Join(s.Fields, ", ") + " }"
+}
+
+// Field return the position of the field name , -1 when the struct has no such field
+func (s *Struct) Field(name string) int {
+	for i, field := range s.Fields {
+		if field == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Instance of a struct , Values follow the order of Struct.Fields
+type Instance struct {
+	Struct *Struct
+	Values []Object
+}
+
+func (i *Instance) Type() ObjectType { return INSTANCE_OBJ }
+func (i *Instance) Inspect() string {
+	fields := []string{}
+	for j, field := range i.Struct.Fields {
+		fields = append(fields, field+": "+inspectElement(i.Values[j]))
+	}
+	return i.Struct.Name + "{" + strings.Join(fields, ", ") + "}"
+}
+
+// Get return the value of the field name
+func (i *Instance) Get(name string) (Object, bool) {
+	if j := i.Struct.Field(name); j >= 0 {
+		return i.Values[j], true
+	}
+	return nil, false
+}
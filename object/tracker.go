@@ -1,9 +1,25 @@
 package object
 
+import (
+	"khanhanh_lang/host"
+	"sort"
+	"sync"
+)
+
 // Keep track of variable
 type Tracker struct {
-	store map[string]Object
-	outer *Tracker
+	store  map[string]Object
+	consts map[string]bool // names of store bound by const , they cannot be bound again in this tracker
+	outer  *Tracker
+	host   *host.Host // what the script may reach outside the interpreter , shared by the enclosed trackers
+	strict bool       // operators never convert a value to a string , shared by the enclosed trackers
+
+	generator *generatorState // the generator whose body run in this tracker , shared by the enclosed trackers
+	session   *session        // shared by every tracker of the session
+
+	file       string             // path of the file being evaluated , empty for the REPL or stdin
+	importedBy *Tracker           // tracker of the import that started this file , nil for the main one
+	modules    map[string]*Module // every module imported during the run , by path
 }
 
 func (t *Tracker) Get(name string) (Object, bool) {
@@ -14,19 +30,159 @@ func (t *Tracker) Get(name string) (Object, bool) {
 	}
 	return obj, ok
 }
+
+// Set bind name to val and return val , or an error without binding anything when name is a const of this tracker
+// an enclosed tracker can still bind the same name , it only hide the const inside its own scope
 func (t *Tracker) Set(name string, val Object) Object {
+	if t.consts[name] {
+		return &Error{Message: "[Error]: Cannot redefine const " + name}
+	}
+	t.store[name] = val
+	return val
+}
+
+// SetConst bind name to val like Set , then refuse every later binding of name in this tracker
+func (t *Tracker) SetConst(name string, val Object) Object {
+	if t.consts[name] {
+		return &Error{Message: "[Error]: Cannot redefine const " + name}
+	}
+	if t.consts == nil {
+		t.consts = map[string]bool{}
+	}
 	t.store[name] = val
+	t.consts[name] = true
 	return val
 }
 
+// Names return every name visible from this tracker , including the enclosing ones , sorted
+func (t *Tracker) Names() []string {
+	seen := map[string]bool{}
+	for tracker := t; tracker != nil; tracker = tracker.outer {
+		for name := range tracker.store {
+			seen[name] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Host return the capabilities given to the script , nil when it has none
+func (t *Tracker) Host() *host.Host {
+	return t.host
+}
+
+// SetHost give the capabilities of h to the script running in this tracker and every tracker it enclose
+func (t *Tracker) SetHost(h *host.Host) {
+	t.host = h
+}
+
+// Strict report whether the script run in strict mode
+func (t *Tracker) Strict() bool {
+	return t.strict
+}
+
+// SetStrict turn the strict mode on or off for the script running in this tracker and every tracker it enclose ,
+// in strict mode "a" + 1 is an error instead of "a1"
+func (t *Tracker) SetStrict(strict bool) {
+	t.strict = strict
+}
+
+// Yield suspend the generator running in this tracker until it is resumed , and return the value sent by next ,
+// or the error unwinding the body when the generator is closed
+func (t *Tracker) Yield(value Object) Object {
+	if t.generator == nil {
+		return &Error{Message: "[Error]: yield outside a generator"}
+	}
+	return t.generator.yield(value)
+}
+
+// File return the path of the file being evaluated , empty when it does not come from a file
+func (t *Tracker) File() string {
+	return t.file
+}
+
+// SetFile record the path of the file being evaluated , imports are relative to it
+func (t *Tracker) SetFile(path string) {
+	t.file = path
+}
+
+// ImportChain return the files from the main one to the one being evaluated , each importing the next
+func (t *Tracker) ImportChain() []string {
+	chain := []string{}
+	for tracker := t; tracker != nil; tracker = tracker.importedBy {
+		if tracker.file != "" {
+			chain = append([]string{tracker.file}, chain...)
+		}
+	}
+	return chain
+}
+
+// Module return the module already imported from path during this run
+func (t *Tracker) Module(path string) (*Module, bool) {
+	module, ok := t.modules[path]
+	return module, ok
+}
+
+// SetModule keep the module imported from path , so the next import of path reuse it
+func (t *Tracker) SetModule(path string, module *Module) {
+	t.modules[path] = module
+}
+
+// a session of the interpreter , from the tracker given by NewTracker to its Close
+type session struct {
+	closed chan struct{} // closed by Close , the suspended generators are then abandoned
+	once   sync.Once
+}
+
+// NewTracker return the top level tracker of a session , the caller close it once it is done with the session
 func NewTracker() *Tracker {
+	tracker := newTracker()
+	tracker.session = &session{closed: make(chan struct{})}
+	tracker.modules = map[string]*Module{}
+	return tracker
+}
+
+func newTracker() *Tracker {
 	s := make(map[string]Object)
 	return &Tracker{store: s, outer: nil}
 }
 
+// Close end the session of the tracker , every generator of the session still suspended is abandoned : its
+// goroutine unwind without running more of the script and a later next on it is an error . A generator the
+// script can no longer reach is abandoned without Close , Close is for the ones the session still hold
+func (t *Tracker) Close() {
+	if t.session != nil {
+		t.session.once.Do(func() { close(t.session.closed) })
+	}
+}
+
+// NewFileTracker return the top level tracker of the file at path imported from importer ,
+// it see none of the importer names but share its host and its imported modules
+func NewFileTracker(importer *Tracker, path string) *Tracker {
+	tracker := newTracker()
+	tracker.host = importer.host
+	tracker.strict = importer.strict
+	tracker.session = importer.session
+	tracker.file = path
+	tracker.importedBy = importer
+	tracker.modules = importer.modules
+	return tracker
+}
+
 // keeping track of enclosing environment
 func NewEnclosedTracker(outer *Tracker) *Tracker {
-	tracker := NewTracker()
+	tracker := newTracker()
 	tracker.outer = outer
+	tracker.host = outer.host
+	tracker.strict = outer.strict
+	tracker.generator = outer.generator
+	tracker.session = outer.session
+	tracker.file = outer.file
+	tracker.importedBy = outer.importedBy
+	tracker.modules = outer.modules
 	return tracker
 }
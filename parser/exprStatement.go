@@ -5,6 +5,7 @@ import (
 	"khanhanh_lang/ast"
 	"khanhanh_lang/token"
 	"strconv"
+	"strings"
 )
 
 // PRECEDENCE
@@ -19,6 +20,7 @@ const (
 	PRODUCT     // *
 	PREFIX      // -X or !X
 	CALL        // myFunction(X)
+	INDEX       // array[index] or module.member
 )
 
 // map infix operation precedence
@@ -34,6 +36,16 @@ var precedences = map[token.TokenType]int{
 	token.SLASH:    PRODUCT,
 	token.ASTERISK: PRODUCT,
 	token.LPAREN:   CALL,
+	token.LBRACKET: INDEX,
+	token.DOT:      INDEX,
+}
+
+// Precedence return the binding power of an infix operator token , LOWEST if the token is not an operator
+func Precedence(t token.TokenType) int {
+	if p, ok := precedences[t]; ok {
+		return p
+	}
+	return LOWEST
 }
 
 // Check the current token precedence
@@ -68,7 +80,20 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 
 	if err != nil {
 		msg := fmt.Sprintf("could not parse %q as integer", p.curToken.Literal)
-		p.errors = append(p.errors, msg)
+		p.errorAt(p.curToken, msg)
+		return nil
+	}
+	lit.Value = value
+	return lit
+}
+
+// The parsing function that register in prefixParseFns for token FLOAT
+func (p *Parser) parseFloatLiteral() ast.Expression {
+	lit := &ast.FloatLiteral{Token: p.curToken}
+	value, err := strconv.ParseFloat(p.curToken.Literal, 64)
+	if err != nil {
+		msg := fmt.Sprintf("could not parse %q as float", p.curToken.Literal)
+		p.errorAt(p.curToken, msg)
 		return nil
 	}
 	lit.Value = value
@@ -85,37 +110,135 @@ func (p *Parser) parseBoolean() ast.Expression {
 
 func (p *Parser) parseFunctionLiteral() ast.Expression {
 	lit := &ast.FunctionLiteral{Token: p.curToken}
+	if p.peekTokenIs(token.ASTERISK) {
+		p.nextToken()
+		lit.Generator = true
+	}
+	p.openScope()
+	defer p.closeScope()
+	// a yield in a default belong to no function
+	outer := p.function
+	p.function = nil
+	defer func() { p.function = outer }()
 	if !p.expectPeek(token.LPAREN) {
 		return nil
 	}
-	lit.Parameters = p.parseFunctionParameter()
+	lit.Parameters, lit.ParameterTypes = p.parseFunctionParameter()
+	var ok bool
+	if lit.ReturnType, ok = p.parseAnnotation(); !ok {
+		return nil
+	}
 	if !p.expectPeek(token.LBRACE) {
 		return nil
 	}
+	p.function = lit
 	lit.Body = p.parseBlockStatement()
 	return lit
 }
 
+func (p *Parser) parseArrayLiteral() ast.Expression {
+	array := &ast.ArrayLiteral{Token: p.curToken}
+	array.Elements = p.parseExpressionList(token.RBRACKET)
+	array.Rbracket = p.curToken
+	return array
+}
+
+// Parse key: value pairs until the closing brace , a trailing comma is not allowed
+func (p *Parser) parseHashLiteral() ast.Expression {
+	hash := &ast.HashLiteral{Token: p.curToken, Keys: []ast.Expression{}, Values: []ast.Expression{}}
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+		key := p.parseExpression(LOWEST)
+		if !p.expectPeek(token.COLON) {
+			return nil
+		}
+		p.nextToken()
+		value := p.parseExpression(LOWEST)
+		hash.Keys = append(hash.Keys, key)
+		hash.Values = append(hash.Values, value)
+		if p.peekTokenIs(token.RBRACE) {
+			break
+		}
+		if !p.expectPeek(token.COMMA) {
+			return nil
+		}
+		if p.peekTokenIs(token.RBRACE) {
+			p.errorAt(p.peekToken, "expected a key after , but get }")
+			return nil
+		}
+	}
+	p.nextToken()
+	hash.Rbrace = p.curToken
+	return hash
+}
+
 // construct the  slice of Parameters , by continuously iterate inside comma separated lis
-func (p *Parser) parseFunctionParameter() []*ast.Identifier {
-	identifiers := []*ast.Identifier{}
+// a parameter is a name or a destructuring pattern , no name can be bound by two parameters
+// the parameters with a default come after the others , and ...rest come last
+// each parameter may have an annotation , the types are nil when no parameter has one
+func (p *Parser) parseFunctionParameter() ([]ast.Expression, []*ast.Type) {
+	params := []ast.Expression{}
+	types := []*ast.Type{}
 	if p.peekTokenIs(token.RPAREN) {
 		p.nextToken()
-		return identifiers
+		return params, nil
 	}
+	bound := map[string]bool{}
+	withDefault := false
 	p.nextToken()
-	ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-	identifiers = append(identifiers, ident)
-	for p.peekTokenIs(token.COMMA) {
+	for {
+		if p.curTokenIs(token.ELLIPSIS) {
+			rest := &ast.SpreadExpression{Token: p.curToken}
+			if !p.expectPeek(token.IDENT) {
+				return nil, nil
+			}
+			name := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+			p.bindName(name, bound)
+			rest.Value = name
+			typ, ok := p.parseAnnotation()
+			if !ok {
+				return nil, nil
+			}
+			params, types = append(params, rest), append(types, typ)
+			if !p.peekTokenIs(token.RPAREN) {
+				p.errorAt(p.peekToken, "...rest must be the last parameter")
+				return nil, nil
+			}
+			break
+		}
+		start := p.curToken
+		param := p.parseBinding(bound)
+		typ, ok := p.parseAnnotation()
+		if !ok {
+			return nil, nil
+		}
+		types = append(types, typ)
+		if p.peekTokenIs(token.ASSIGN) {
+			p.nextToken()
+			def := &ast.DefaultParameter{Token: p.curToken, Target: param}
+			p.nextToken()
+			def.Value = p.parseExpression(LOWEST)
+			param = def
+			withDefault = true
+		} else if withDefault && param != nil {
+			p.errorAt(start, fmt.Sprintf("parameter %s need a default , it follow a parameter with a default", param.String()))
+		}
+		params = append(params, param)
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
 		p.nextToken()
 		p.nextToken()
-		ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-		identifiers = append(identifiers, ident)
 	}
 	if !p.expectPeek(token.RPAREN) {
-		return nil
+		return nil, nil
+	}
+	for _, typ := range types {
+		if typ != nil {
+			return params, types
+		}
 	}
-	return identifiers
+	return params, nil
 }
 
 //-----------------------------------------------------------------------------
@@ -184,6 +307,10 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 		block.Statements = append(block.Statements, stmt)
 		p.nextToken()
 	}
+	if p.curTokenIs(token.EOF) {
+		p.eofError(p.curToken, "expected next token : } , but get EOF")
+	}
+	block.Rbrace = p.curToken
 	return block
 }
 
@@ -207,27 +334,88 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 	exp := &ast.CallExpression{Token: p.curToken, Function: function}
 	exp.Arguments = p.parseCallArguments()
+	exp.Rparen = p.curToken
 	return exp
 }
 
+// Like parseExpressionList , but an argument can also be name: value , after every positional argument
 func (p *Parser) parseCallArguments() []ast.Expression {
 	args := []ast.Expression{}
 	if p.peekTokenIs(token.RPAREN) {
 		p.nextToken()
 		return args
 	}
+	named := map[string]bool{}
+	for {
+		p.nextToken()
+		if p.curTokenIs(token.IDENT) && p.peekTokenIs(token.COLON) {
+			arg := &ast.NamedArgument{Token: p.curToken, Name: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
+			if named[arg.Name.Value] {
+				p.errorAt(arg.Token, fmt.Sprintf("argument %s is given twice", arg.Name.Value))
+			}
+			named[arg.Name.Value] = true
+			p.nextToken()
+			p.nextToken()
+			arg.Value = p.parseExpression(LOWEST)
+			args = append(args, arg)
+		} else {
+			if len(named) != 0 {
+				p.errorAt(p.curToken, "positional argument after a named argument")
+			}
+			args = append(args, p.parseExpression(LOWEST))
+		}
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	return args
+}
+
+// Parse comma separated expressions until the end token , used by the array literal
+func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
+	list := []ast.Expression{}
+	if p.peekTokenIs(end) {
+		p.nextToken()
+		return list
+	}
 	p.nextToken()
-	args = append(args, p.parseExpression(LOWEST))
+	list = append(list, p.parseExpression(LOWEST))
 
 	for p.peekTokenIs(token.COMMA) {
 		p.nextToken()
 		p.nextToken()
-		args = append(args, p.parseExpression(LOWEST))
+		list = append(list, p.parseExpression(LOWEST))
 	}
-	if !p.expectPeek(token.RPAREN) {
+	if !p.expectPeek(end) {
 		return nil
 	}
-	return args
+	return list
+}
+
+// The left side is already parsed , so only the index inside the brackets remain
+func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
+	exp := &ast.IndexExpression{Token: p.curToken, Left: left}
+	p.nextToken()
+	exp.Index = p.parseExpression(LOWEST)
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+	exp.Rbracket = p.curToken
+	return exp
+}
+
+// The member after the dot is always a name , strings.split
+func (p *Parser) parseMemberExpression(object ast.Expression) ast.Expression {
+	exp := &ast.MemberExpression{Token: p.curToken, Object: object}
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	exp.Member = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	return exp
 }
 
 //--------------------------------------------------------------------------
@@ -275,5 +463,19 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 // helper to append error message
 func (p *Parser) noPrefixParsfnError(t token.TokenType) {
 	msg := fmt.Sprintf("no prefix parse function for %s found ", t)
-	p.errors = append(p.errors, msg)
+	if t == token.EOF {
+		p.eofError(p.curToken, msg)
+		return
+	}
+	p.errorAt(p.curToken, msg)
+}
+
+// The parsing function that register in prefixParseFns for token ILLEGAL , it only report the error
+func (p *Parser) parseIllegal() ast.Expression {
+	if strings.HasPrefix(p.curToken.Literal, `"`) {
+		p.eofError(p.curToken, fmt.Sprintf("unterminated string %s", p.curToken.Literal))
+		return nil
+	}
+	p.errorAt(p.curToken, fmt.Sprintf("illegal token %q", p.curToken.Literal))
+	return nil
 }
@@ -36,6 +36,21 @@ func TestIntegerLiteralExpression(t *testing.T) {
 	testIntegerLiteral(t, stmt.Expression, 5)
 }
 
+func TestFloatLiteralExpression(t *testing.T) {
+	par := New(lexer.New("3.25"))
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+	testProgramLength(t, program)
+	stmt := testExpression(t, program.Statements[0])
+	lit, ok := stmt.Expression.(*ast.FloatLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.FloatLiteral. got=%T", stmt.Expression)
+	}
+	if lit.Value != 3.25 || lit.TokenLiteral() != "3.25" {
+		t.Errorf("wrong float literal. got=%v (%q)", lit.Value, lit.TokenLiteral())
+	}
+}
+
 func TestBooleanLiteralExpression(t *testing.T) {
 	input := "true"
 	lex := lexer.New(input)
@@ -253,6 +268,18 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"add(a, b, 1, 2 * 3, 4 + 5, add(6, 7 * 8))",
 			"add(a, b, 1, (2 * 3), (4 + 5), add(6, (7 * 8)))",
 		},
+		{
+			"a * [1, 2, 3, 4][b * c] * d",
+			"((a * ([1, 2, 3, 4][(b * c)])) * d)",
+		},
+		{
+			"add(a * b[2], b[1], 2 * [1, 2][1])",
+			"add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))",
+		},
+		{
+			"-strings.len(a) + m.x",
+			"((-strings.len(a)) + m.x)",
+		},
 	}
 
 	for _, test := range tests {
@@ -362,3 +389,122 @@ func TestCallExpressionParsing(t *testing.T) {
 	testInfixExpression(t, exp.Arguments[1], 2, "*", 3)
 	testInfixExpression(t, exp.Arguments[2], 4, "+", 5)
 }
+
+// TEST COLLECTIONS
+// ----------------------------------------------------------------------------------------------------------------
+func TestArrayLiteralParsing(t *testing.T) {
+	input := "[1, 2 * 2, 3 + 3]"
+	lex := lexer.New(input)
+	par := New(lex)
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+	stmt := testExpression(t, program.Statements[0])
+	array, ok := stmt.Expression.(*ast.ArrayLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.ArrayLiteral. got=%T", stmt.Expression)
+	}
+	if len(array.Elements) != 3 {
+		t.Fatalf("wrong length of elements. got=%d", len(array.Elements))
+	}
+	testLiteralExpression(t, array.Elements[0], 1)
+	testInfixExpression(t, array.Elements[1], 2, "*", 2)
+	testInfixExpression(t, array.Elements[2], 3, "+", 3)
+	if array.Rbracket.Literal != "]" {
+		t.Errorf("array should end at ]. got=%q", array.Rbracket.Literal)
+	}
+}
+
+func TestEmptyArrayLiteralParsing(t *testing.T) {
+	par := New(lexer.New("[]"))
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+	stmt := testExpression(t, program.Statements[0])
+	array, ok := stmt.Expression.(*ast.ArrayLiteral)
+	if !ok || len(array.Elements) != 0 {
+		t.Fatalf("stmt.Expression is not an empty ast.ArrayLiteral. got=%T", stmt.Expression)
+	}
+}
+
+func TestIndexExpressionParsing(t *testing.T) {
+	par := New(lexer.New("myArray[1 + 1]"))
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+	stmt := testExpression(t, program.Statements[0])
+	exp, ok := stmt.Expression.(*ast.IndexExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.IndexExpression. got=%T", stmt.Expression)
+	}
+	if !testIdentifier(t, exp.Left, "myArray") {
+		return
+	}
+	testInfixExpression(t, exp.Index, 1, "+", 1)
+}
+
+func TestMemberExpressionParsing(t *testing.T) {
+	par := New(lexer.New("strings.split(a, b)"))
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+	stmt := testExpression(t, program.Statements[0])
+	call, ok := stmt.Expression.(*ast.CallExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.CallExpression. got=%T", stmt.Expression)
+	}
+	member, ok := call.Function.(*ast.MemberExpression)
+	if !ok {
+		t.Fatalf("call.Function is not ast.MemberExpression. got=%T", call.Function)
+	}
+	testIdentifier(t, member.Object, "strings")
+	testIdentifier(t, member.Member, "split")
+}
+
+func TestMemberExpressionError(t *testing.T) {
+	par := New(lexer.New("strings.1"))
+	par.ParseProgram()
+	if len(par.Errors()) == 0 {
+		t.Fatalf("a member must be a name , expected a parse error")
+	}
+}
+
+func TestHashLiteralParsing(t *testing.T) {
+	par := New(lexer.New(`{"one": 1, "two": 2 * 3, 3: true}`))
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+	stmt := testExpression(t, program.Statements[0])
+	hash, ok := stmt.Expression.(*ast.HashLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.HashLiteral. got=%T", stmt.Expression)
+	}
+	if len(hash.Keys) != 3 || len(hash.Values) != 3 {
+		t.Fatalf("hash should hold 3 pairs. got=%d", len(hash.Keys))
+	}
+	// pairs keep the source order
+	if hash.String() != `{"one": 1, "two": (2 * 3), 3: true}` {
+		t.Errorf("wrong hash. got=%s", hash.String())
+	}
+	testLiteralExpression(t, hash.Values[0], 1)
+	testInfixExpression(t, hash.Values[1], 2, "*", 3)
+	if hash.Rbrace.Literal != "}" {
+		t.Errorf("hash should end at }. got=%q", hash.Rbrace.Literal)
+	}
+}
+
+func TestEmptyHashLiteralParsing(t *testing.T) {
+	par := New(lexer.New("{}"))
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+	stmt := testExpression(t, program.Statements[0])
+	hash, ok := stmt.Expression.(*ast.HashLiteral)
+	if !ok || len(hash.Keys) != 0 {
+		t.Fatalf("stmt.Expression is not an empty ast.HashLiteral. got=%T", stmt.Expression)
+	}
+}
+
+func TestHashLiteralErrors(t *testing.T) {
+	for _, input := range []string{`{"a" 1}`, `{"a": 1 "b": 2}`, `{"a": 1,}`} {
+		par := New(lexer.New(input))
+		par.ParseProgram()
+		if len(par.Errors()) == 0 {
+			t.Errorf("%q should not parse", input)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package parser
+
+import (
+	"khanhanh_lang/ast"
+	"khanhanh_lang/token"
+)
+
+// for name in iterable { } , or for [i, x] in enumerate(xs) { } to destructure each element
+// the names are bound in a new scope for each turn , the iterable is parsed in the scope around
+func (p *Parser) parseForStatement() ast.Statement {
+	stmt := &ast.ForStatement{Token: p.curToken}
+	p.nextToken()
+	if stmt.Target = p.parseBinding(map[string]bool{}); stmt.Target == nil {
+		return nil
+	}
+	if !p.expectPeek(token.IN) {
+		return nil
+	}
+	p.nextToken()
+	stmt.Iterable = p.parseExpression(LOWEST)
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	p.openScope()
+	p.declare(boundNames(stmt.Target), false)
+	stmt.Body = p.parseBlockStatement()
+	p.closeScope()
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	return stmt
+}
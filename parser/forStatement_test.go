@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"khanhanh_lang/ast"
+	"khanhanh_lang/lexer"
+	"strings"
+	"testing"
+)
+
+func TestForStatement(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"for x in xs { x }", "for x in xs x"},
+		{"for [i, x] in enumerate(xs) { i + x; }", "for [i, x] in enumerate(xs) (i + x)"},
+		{`for {name} in people { print(name) };`, "for {name} in people print(name)"},
+		{"for x in range(1 + 2) {}", "for x in range((1 + 2)) "},
+	}
+	for _, tt := range tests {
+		par := New(lexer.New(tt.input))
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+		if len(program.Statements) != 1 {
+			t.Fatalf("%s: program should contain 1 statement. got=%d", tt.input, len(program.Statements))
+		}
+		stmt, ok := program.Statements[0].(*ast.ForStatement)
+		if !ok {
+			t.Fatalf("stmt is not *ast.ForStatement. got=%T", program.Statements[0])
+		}
+		if stmt.String() != tt.expect {
+			t.Errorf("wrong statement. expect=%q got=%q", tt.expect, stmt.String())
+		}
+	}
+}
+
+func TestForErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"for 1 in xs { }", "1:5: expected a name or a pattern , but get INT"},
+		{"for x of xs { }", "1:7: expected next token : IN , but get INDENT"},
+		{"for x in xs x", "1:13: expected next token : { , but get INDENT"},
+		{"for [a, a] in xs { }", "1:9: a is bound twice"},
+		{"const x = 1; for x in xs { const y = x; let y = 2 }", "1:45: cannot redefine const y"},
+	}
+	for _, tt := range tests {
+		par := New(lexer.New(tt.input))
+		par.ParseProgram()
+		errs := strings.Join(par.Errors(), "\n")
+		if !strings.Contains(errs, tt.expect) {
+			t.Errorf("%s wrong errors. expect=%q got=%q", tt.input, tt.expect, errs)
+		}
+	}
+	par := New(lexer.New("const x = 1; for x in xs { x }"))
+	par.ParseProgram()
+	checkParserErrors(t, par)
+
+	par = New(lexer.New("for x in xs {"))
+	par.ParseProgram()
+	if !par.Incomplete() {
+		t.Errorf("an unclosed for should be incomplete. errors=%v", par.Errors())
+	}
+}
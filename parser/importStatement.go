@@ -0,0 +1,45 @@
+package parser
+
+import (
+	"fmt"
+	"khanhanh_lang/ast"
+	"khanhanh_lang/lexer"
+	"khanhanh_lang/token"
+)
+
+// import "path" bind the module to the file name , which must be a valid identifier
+func (p *Parser) parseImportStatement() ast.Statement {
+	stmt := &ast.ImportStatement{Token: p.curToken}
+	p.nextToken()
+	stmt.Path = p.parseStringLiteral()
+	if name := stmt.Name(); !isIdentifier(name) {
+		p.errorAt(p.curToken, fmt.Sprintf("cannot import %q as %q , use let name = import(%q)", p.curToken.Literal, name, p.curToken.Literal))
+	} else {
+		p.declare([]*ast.Identifier{{Token: p.curToken, Value: name}}, false)
+	}
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	return stmt
+}
+
+// import(path) evaluate to the module , path may be any expression
+func (p *Parser) parseImportExpression() ast.Expression {
+	exp := &ast.ImportExpression{Token: p.curToken}
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+	p.nextToken()
+	exp.Path = p.parseExpression(LOWEST)
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	exp.Rparen = p.curToken
+	return exp
+}
+
+func isIdentifier(name string) bool {
+	l := lexer.New(name)
+	tok := l.NextToken()
+	return tok.Type == token.IDENT && tok.Literal == name && l.NextToken().Type == token.EOF
+}
@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"khanhanh_lang/ast"
+	"khanhanh_lang/lexer"
+	"strings"
+	"testing"
+)
+
+func TestImportStatement(t *testing.T) {
+	tests := []struct {
+		input string
+		path  string
+		name  string
+	}{
+		{`import "list"`, "list", "list"},
+		{`import "lib/list.kh";`, "lib/list.kh", "list"},
+		{`import "../lib/str_utils"`, "../lib/str_utils", "str_utils"},
+	}
+	for _, tt := range tests {
+		par := New(lexer.New(tt.input))
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+		if len(program.Statements) != 1 {
+			t.Fatalf("program should contain 1 statement. got=%d", len(program.Statements))
+		}
+		stmt, ok := program.Statements[0].(*ast.ImportStatement)
+		if !ok {
+			t.Fatalf("stmt is not *ast.ImportStatement. got=%T", program.Statements[0])
+		}
+		if path, ok := stmt.Path.(*ast.StringLiteral); !ok || path.Value != tt.path {
+			t.Errorf("wrong path. expect=%q got=%s", tt.path, stmt.Path)
+		}
+		if stmt.Name() != tt.name {
+			t.Errorf("wrong name. expect=%q got=%q", tt.name, stmt.Name())
+		}
+	}
+}
+
+func TestImportExpression(t *testing.T) {
+	par := New(lexer.New(`let list = import("lib/" + name).list; import(path)`))
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+	if len(program.Statements) != 2 {
+		t.Fatalf("program should contain 2 statements. got=%d", len(program.Statements))
+	}
+	if program.String() != `let list = import(("lib/" + name)).list;import(path)` {
+		t.Errorf("wrong program. got=%s", program.String())
+	}
+	stmt := testExpression(t, program.Statements[1])
+	exp, ok := stmt.Expression.(*ast.ImportExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not *ast.ImportExpression. got=%T", stmt.Expression)
+	}
+	if exp.Rparen.Literal != ")" {
+		t.Errorf("import should end at ). got=%q", exp.Rparen.Literal)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{`import "lib/my-list"`, `1:8: cannot import "lib/my-list" as "my-list" , use let name = import("lib/my-list")`},
+		{`import "lib/"`, `cannot import "lib/" as ""`},
+		{`import path`, "expected next token : ("},
+	}
+	for _, tt := range tests {
+		par := New(lexer.New(tt.input))
+		par.ParseProgram()
+		errs := strings.Join(par.Errors(), "\n")
+		if !strings.Contains(errs, tt.expect) {
+			t.Errorf("%s wrong errors. expect=%q got=%q", tt.input, tt.expect, errs)
+		}
+	}
+}
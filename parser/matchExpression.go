@@ -0,0 +1,160 @@
+package parser
+
+import (
+	"fmt"
+	"khanhanh_lang/ast"
+	"khanhanh_lang/token"
+)
+
+// match subject { pattern => body, pattern if guard => body } , a body is an expression or a block
+// the arms are comma separated without trailing comma , like the pairs of a hash
+func (p *Parser) parseMatchExpression() ast.Expression {
+	expression := &ast.MatchExpression{Token: p.curToken, Arms: []*ast.MatchArm{}}
+	p.nextToken()
+	expression.Subject = p.parseExpression(LOWEST)
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	for !p.peekTokenIs(token.RBRACE) {
+		arm := p.parseMatchArm()
+		if arm == nil {
+			return nil
+		}
+		expression.Arms = append(expression.Arms, arm)
+		if p.peekTokenIs(token.RBRACE) {
+			break
+		}
+		if !p.expectPeek(token.COMMA) {
+			return nil
+		}
+		if p.peekTokenIs(token.RBRACE) {
+			p.errorAt(p.peekToken, "expected a pattern after , but get }")
+			return nil
+		}
+	}
+	p.nextToken()
+	expression.Rbrace = p.curToken
+	p.checkBooleanArms(expression)
+	return expression
+}
+
+func (p *Parser) parseMatchArm() *ast.MatchArm {
+	p.openScope()
+	defer p.closeScope()
+	p.nextToken()
+	start := p.curToken
+	arm := &ast.MatchArm{Pattern: p.parseExpression(LOWEST)}
+	if arm.Pattern == nil {
+		return nil
+	}
+	p.checkPattern(arm.Pattern, start, map[string]bool{})
+	if p.peekTokenIs(token.IF) {
+		p.nextToken()
+		p.nextToken()
+		arm.Guard = p.parseExpression(LOWEST)
+	}
+	if !p.expectPeek(token.ARROW) {
+		return nil
+	}
+	arm.Arrow = p.curToken
+	p.nextToken()
+	if p.curTokenIs(token.LBRACE) {
+		arm.Body = p.parseBlockStatement()
+		return arm
+	}
+	body := &ast.ExpressionStatement{Token: p.curToken, Expression: p.parseExpression(LOWEST)}
+	if body.Expression == nil {
+		return nil
+	}
+	arm.Body = body
+	return arm
+}
+
+// ...value , only meaningful as the last element of an array pattern for now
+func (p *Parser) parseSpreadExpression() ast.Expression {
+	expression := &ast.SpreadExpression{Token: p.curToken}
+	p.nextToken()
+	expression.Value = p.parseExpression(PREFIX)
+	return expression
+}
+
+// PATTERNS
+// ------------------------------------------------------------------------
+// A pattern is a literal , a negative number , _ , a name to bind , or an array or hash of patterns
+// an array may end with ...name to bind the rest , a name is bound only once per pattern
+// start is the first token of the arm , where an error is reported when the pattern has no token of its own
+func (p *Parser) checkPattern(pattern ast.Expression, start token.Token, bound map[string]bool) {
+	switch pat := pattern.(type) {
+	case *ast.IntegerLiteral, *ast.FloatLiteral, *ast.StringLiteral, *ast.BooleanLiteral:
+	case *ast.PrefixExpression:
+		switch pat.Right.(type) {
+		case nil:
+			return
+		case *ast.IntegerLiteral, *ast.FloatLiteral:
+			if pat.Operator == "-" {
+				return
+			}
+		}
+		p.errorAt(pat.Token, fmt.Sprintf("invalid pattern %s", pat.String()))
+	case *ast.Identifier:
+		if pat.Value == "_" {
+			return
+		}
+		if bound[pat.Value] {
+			p.errorAt(pat.Token, fmt.Sprintf("%s is bound twice in the same pattern", pat.Value))
+		}
+		bound[pat.Value] = true
+	case *ast.ArrayLiteral:
+		for i, el := range pat.Elements {
+			if spread, ok := el.(*ast.SpreadExpression); ok {
+				if i != len(pat.Elements)-1 {
+					p.errorAt(spread.Token, "...rest must be the last element of an array pattern")
+				}
+				if spread.Value == nil {
+					continue
+				}
+				if _, ok := spread.Value.(*ast.Identifier); !ok {
+					p.errorAt(spread.Token, fmt.Sprintf("expected a name after ... , but get %s", spread.Value.String()))
+					continue
+				}
+				p.checkPattern(spread.Value, start, bound)
+				continue
+			}
+			p.checkPattern(el, start, bound)
+		}
+	case *ast.HashLiteral:
+		for i, key := range pat.Keys {
+			switch key.(type) {
+			case *ast.IntegerLiteral, *ast.StringLiteral, *ast.BooleanLiteral, nil:
+			default:
+				p.errorAt(pat.Token, fmt.Sprintf("hash pattern key must be a literal , got %s", key.String()))
+			}
+			p.checkPattern(pat.Values[i], start, bound)
+		}
+	case nil:
+	default:
+		p.errorAt(start, fmt.Sprintf("invalid pattern %s", pattern.String()))
+	}
+}
+
+// Warn when every arm test a boolean and true or false is not matched without a guard
+func (p *Parser) checkBooleanArms(expression *ast.MatchExpression) {
+	matched := map[bool]bool{}
+	for _, arm := range expression.Arms {
+		boolean, ok := arm.Pattern.(*ast.BooleanLiteral)
+		if !ok {
+			return
+		}
+		if arm.Guard == nil {
+			matched[boolean.Value] = true
+		}
+	}
+	if len(expression.Arms) == 0 {
+		return
+	}
+	for _, value := range []bool{true, false} {
+		if !matched[value] {
+			p.warningAt(expression.Token, fmt.Sprintf("match is not exhaustive , %t is not matched", value))
+		}
+	}
+}
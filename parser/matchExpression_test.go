@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"khanhanh_lang/ast"
+	"khanhanh_lang/lexer"
+	"strings"
+	"testing"
+)
+
+func TestMatchExpression(t *testing.T) {
+	input := `match x { 0 => "zero", [a, ...rest] => rest, {"k": v} => v, n if n > 10 => { n }, -1 => 1, _ => 2 }`
+	par := New(lexer.New(input))
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+	stmt := testExpression(t, program.Statements[0])
+	exp, ok := stmt.Expression.(*ast.MatchExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not *ast.MatchExpression. got=%T", stmt.Expression)
+	}
+	if exp.Subject.String() != "x" {
+		t.Errorf("wrong subject. got=%s", exp.Subject.String())
+	}
+	tests := []struct {
+		pattern string
+		guard   string
+		block   bool
+	}{
+		{"0", "", false},
+		{"[a, ...rest]", "", false},
+		{`{"k": v}`, "", false},
+		{"n", "(n > 10)", true},
+		{"(-1)", "", false},
+		{"_", "", false},
+	}
+	if len(exp.Arms) != len(tests) {
+		t.Fatalf("match should have %d arms. got=%d", len(tests), len(exp.Arms))
+	}
+	for i, tt := range tests {
+		arm := exp.Arms[i]
+		if arm.Pattern.String() != tt.pattern {
+			t.Errorf("arm %d: wrong pattern. expect=%s got=%s", i, tt.pattern, arm.Pattern.String())
+		}
+		guard := ""
+		if arm.Guard != nil {
+			guard = arm.Guard.String()
+		}
+		if guard != tt.guard {
+			t.Errorf("arm %d: wrong guard. expect=%s got=%s", i, tt.guard, guard)
+		}
+		if _, isBlock := arm.Body.(*ast.BlockStatement); isBlock != tt.block {
+			t.Errorf("arm %d: wrong body. got=%T", i, arm.Body)
+		}
+	}
+}
+
+func TestMatchErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"match x { 1 => 2, }", "1:19: expected a pattern after , but get }"},
+		{"match x { 1 2 }", "expected next token : => , but get INT"},
+		{"match x { f(1) => 2 }", "1:11: invalid pattern f(1)"},
+		{"match x { 1 + 2 => 2 }", "1:11: invalid pattern (1 + 2)"},
+		{"match x { !true => 2 }", "1:11: invalid pattern (!true)"},
+		{"match x { [a, a] => 2 }", "1:15: a is bound twice in the same pattern"},
+		{"match x { [...a, b] => 2 }", "1:12: ...rest must be the last element of an array pattern"},
+		{"match x { [...1] => 2 }", "1:12: expected a name after ... , but get 1"},
+		{"match x { {k: 1} => 2 }", "hash pattern key must be a literal , got k"},
+	}
+	for _, tt := range tests {
+		par := New(lexer.New(tt.input))
+		par.ParseProgram()
+		errs := strings.Join(par.Errors(), "\n")
+		if !strings.Contains(errs, tt.expect) {
+			t.Errorf("%s wrong errors. expect=%q got=%q", tt.input, tt.expect, errs)
+		}
+	}
+	par := New(lexer.New("match x { 1 => 2"))
+	par.ParseProgram()
+	if !par.Incomplete() {
+		t.Errorf("an unclosed match should be incomplete. errors=%v", par.Errors())
+	}
+}
+
+func TestMatchBooleanWarnings(t *testing.T) {
+	tests := []struct {
+		input    string
+		warnings []string
+	}{
+		{"match x { true => 1 }", []string{"1:1: match is not exhaustive , false is not matched"}},
+		{"match x { false => 1 }", []string{"1:1: match is not exhaustive , true is not matched"}},
+		{"match x { true => 1, false if y => 2 }", []string{"1:1: match is not exhaustive , false is not matched"}},
+		{"match x { true => 1, false => 2 }", nil},
+		{"match x { true => 1, _ => 2 }", nil},
+		{"match x { 1 => 1 }", nil},
+	}
+	for _, tt := range tests {
+		par := New(lexer.New(tt.input))
+		par.ParseProgram()
+		checkParserErrors(t, par)
+		if strings.Join(par.Warnings(), "\n") != strings.Join(tt.warnings, "\n") {
+			t.Errorf("%s wrong warnings. expect=%q got=%q", tt.input, tt.warnings, par.Warnings())
+		}
+	}
+}
@@ -17,6 +17,13 @@ type Parser struct {
 	curToken  token.Token // same as position in the lexer, but instead of point to current ch , it point to current token
 	peekToken token.Token // same as the readPosition in the lexer , but  instead of point to next ch, it point to the next token (both cur and Peek are needed for decision making)
 	errors    []string
+	warnings  []string // problems that do not stop the program from running
+
+	incomplete bool // the first error was caused by the input ending too early
+
+	scopes []scope // the scopes being parsed , innermost last
+
+	function *ast.FunctionLiteral // the function whose body is being parsed , nil outside of one , see parseYieldExpression
 
 	prefixParseFns map[token.TokenType]prefixParseFn //mechanism to check whether curToken has the associated prefixParseFn
 	infixParseFns  map[token.TokenType]infixParseFn  //mechanism to check whether curtoken has the  associated infixParseFn
@@ -40,7 +47,7 @@ func (p *Parser) registerInfix(tokenType token.TokenType, fn infixParseFn) {
 
 // Create new Parser
 func New(l *lexer.Lexer) *Parser {
-	p := &Parser{l: l, errors: []string{}}
+	p := &Parser{l: l, errors: []string{}, scopes: []scope{{}}}
 	// Read two token so the current and peak token are both set
 	p.nextToken()
 	p.nextToken()
@@ -49,6 +56,7 @@ func New(l *lexer.Lexer) *Parser {
 	p.prefixParseFns = make(map[token.TokenType]prefixParseFn)
 	p.registerPrefix(token.IDENT, p.parseIdentifier)
 	p.registerPrefix(token.INT, p.parseIntegerLiteral)
+	p.registerPrefix(token.FLOAT, p.parseFloatLiteral)
 	p.registerPrefix(token.STRING, p.parseStringLiteral)
 	p.registerPrefix(token.BANG, p.parsePrefixExpression)
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
@@ -57,6 +65,14 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerPrefix(token.LPAREN, p.parseGroupedExpression)
 	p.registerPrefix(token.IF, p.parseIfExpression)
 	p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral)
+	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)
+	p.registerPrefix(token.LBRACE, p.parseHashLiteral)
+	p.registerPrefix(token.IMPORT, p.parseImportExpression)
+	p.registerPrefix(token.TRY, p.parseTryExpression)
+	p.registerPrefix(token.MATCH, p.parseMatchExpression)
+	p.registerPrefix(token.ELLIPSIS, p.parseSpreadExpression)
+	p.registerPrefix(token.YIELD, p.parseYieldExpression)
+	p.registerPrefix(token.ILLEGAL, p.parseIllegal)
 
 	// Deal with infixes
 	p.infixParseFns = make(map[token.TokenType]infixParseFn)
@@ -71,6 +87,8 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.LT_EQ, p.parseInfixExpression)
 	p.registerInfix(token.GT_EQ, p.parseInfixExpression)
 	p.registerInfix(token.LPAREN, p.parseCallExpression)
+	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
+	p.registerInfix(token.DOT, p.parseMemberExpression)
 
 	return p
 }
@@ -88,15 +106,29 @@ func (p *Parser) ParseProgram() *ast.Program {
 		program.Statements = append(program.Statements, stmt)
 		p.nextToken()
 	}
+	for _, comment := range p.l.Comments() {
+		program.Comments = append(program.Comments, &ast.Comment{Token: comment, Text: comment.Literal})
+	}
 	return program
 }
 
 func (p *Parser) parseStatement() ast.Statement {
 	switch p.curToken.Type {
-	case token.LET:
+	case token.LET, token.CONST:
 		return p.parseLetStatement()
 	case token.RETURN:
 		return p.parseReturnStatement()
+	case token.STRUCT:
+		return p.parseStructStatement()
+	case token.THROW:
+		return p.parseThrowStatement()
+	case token.FOR:
+		return p.parseForStatement()
+	case token.IMPORT:
+		if p.peekTokenIs(token.STRING) {
+			return p.parseImportStatement()
+		}
+		return p.parseExpressionStatement()
 	default:
 		return p.parseExpressionStatement()
 	}
@@ -127,7 +159,40 @@ func (p *Parser) Errors() []string {
 	return p.errors
 }
 
+// Warnings are reported with the same line:column prefix as the errors , they do not make the program invalid
+func (p *Parser) Warnings() []string {
+	return p.warnings
+}
+
+// Incomplete report whether parsing failed only because the input ended too early
+// (unclosed brace or parenthese , trailing operator , unterminated string) , so more input may complete it
+func (p *Parser) Incomplete() bool {
+	return p.incomplete
+}
+
 func (p *Parser) peekError(t token.TokenType) {
 	msg := fmt.Sprintf("expected next token : %s , but get %s", t, p.peekToken.Type)
-	p.errors = append(p.errors, msg)
+	if p.peekTokenIs(token.EOF) {
+		p.eofError(p.peekToken, msg)
+		return
+	}
+	p.errorAt(p.peekToken, msg)
+}
+
+// record an error , prefixed by the line:column of the offending token
+func (p *Parser) errorAt(tok token.Token, msg string) {
+	p.errors = append(p.errors, fmt.Sprintf("%d:%d: %s", tok.Line, tok.Column, msg))
+}
+
+// record a warning , prefixed by the line:column of the offending token
+func (p *Parser) warningAt(tok token.Token, msg string) {
+	p.warnings = append(p.warnings, fmt.Sprintf("%d:%d: %s", tok.Line, tok.Column, msg))
+}
+
+// record an error caused by reaching the end of the input
+func (p *Parser) eofError(tok token.Token, msg string) {
+	if len(p.errors) == 0 {
+		p.incomplete = true
+	}
+	p.errorAt(tok, msg)
 }
@@ -4,6 +4,7 @@ package parser
 import (
 	"fmt"
 	"khanhanh_lang/ast"
+	"khanhanh_lang/lexer"
 	"testing"
 )
 
@@ -166,3 +167,34 @@ func checkParserErrors(t *testing.T, p *Parser) {
 	}
 	t.FailNow()
 }
+
+func TestIncomplete(t *testing.T) {
+	tests := []struct {
+		input      string
+		incomplete bool
+	}{
+		{"let add = func(x, y) {", true},
+		{"if (x > 1) { 1 } else {", true},
+		{"add(1, ", true},
+		{"(1 + 2", true},
+		{"1 +", true},
+		{"let x =", true},
+		{`"unterminated`, true},
+		{"func(x", true},
+		{"let = 5", false},
+		{"(1 2", false},
+		{"1 @", false},
+		{"let = func() {", false},
+	}
+	for _, test := range tests {
+		p := New(lexer.New(test.input))
+		p.ParseProgram()
+		if len(p.Errors()) == 0 {
+			t.Errorf("input %q: expected parser errors", test.input)
+			continue
+		}
+		if p.Incomplete() != test.incomplete {
+			t.Errorf("input %q: Incomplete() = %t , want %t (errors %v)", test.input, p.Incomplete(), test.incomplete, p.Errors())
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"fmt"
+	"khanhanh_lang/ast"
+	"khanhanh_lang/token"
+)
+
+// DESTRUCTURING PATTERNS
+// ------------------------------------------------------------------------
+// A binding is a name , an array pattern [a, [b, c], ...rest] or a hash pattern {name, age: years}
+// they are used by let and by the function parameters , bound records every name already taken so
+// the same name cannot be bound twice by one let or one parameter list
+
+func (p *Parser) parseBinding(bound map[string]bool) ast.Expression {
+	switch p.curToken.Type {
+	case token.IDENT:
+		ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		p.bindName(ident, bound)
+		return ident
+	case token.LBRACKET:
+		return p.parseArrayPattern(bound)
+	case token.LBRACE:
+		return p.parseHashPattern(bound)
+	}
+	msg := fmt.Sprintf("expected a name or a pattern , but get %s", p.curToken.Type)
+	if p.curTokenIs(token.EOF) {
+		p.eofError(p.curToken, msg)
+	} else {
+		p.errorAt(p.curToken, msg)
+	}
+	return nil
+}
+
+// _ can be repeated , it bind nothing
+func (p *Parser) bindName(ident *ast.Identifier, bound map[string]bool) {
+	if ident.Value == "_" {
+		return
+	}
+	if bound[ident.Value] {
+		p.errorAt(ident.Token, fmt.Sprintf("%s is bound twice", ident.Value))
+	}
+	bound[ident.Value] = true
+}
+
+func (p *Parser) parseArrayPattern(bound map[string]bool) ast.Expression {
+	pattern := &ast.ArrayPattern{Token: p.curToken, Elements: []ast.Expression{}}
+	for !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+		if p.curTokenIs(token.ELLIPSIS) {
+			if !p.expectPeek(token.IDENT) {
+				return nil
+			}
+			pattern.Rest = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+			p.bindName(pattern.Rest, bound)
+			if !p.peekTokenIs(token.RBRACKET) {
+				p.errorAt(p.peekToken, "...rest must be the last element of an array pattern")
+				return nil
+			}
+			break
+		}
+		el := p.parseBinding(bound)
+		if el == nil {
+			return nil
+		}
+		pattern.Elements = append(pattern.Elements, el)
+		if p.peekTokenIs(token.RBRACKET) {
+			break
+		}
+		if !p.expectPeek(token.COMMA) {
+			return nil
+		}
+		if p.peekTokenIs(token.RBRACKET) {
+			p.errorAt(p.peekToken, "expected a name after , but get ]")
+			return nil
+		}
+	}
+	p.nextToken()
+	pattern.Rbracket = p.curToken
+	return pattern
+}
+
+func (p *Parser) parseHashPattern(bound map[string]bool) ast.Expression {
+	pattern := &ast.HashPattern{Token: p.curToken, Keys: []*ast.Identifier{}, Values: []ast.Expression{}}
+	for !p.peekTokenIs(token.RBRACE) {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		key := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		var value ast.Expression
+		if p.peekTokenIs(token.COLON) {
+			p.nextToken()
+			p.nextToken()
+			value = p.parseBinding(bound)
+			if value == nil {
+				return nil
+			}
+		} else {
+			ident := &ast.Identifier{Token: key.Token, Value: key.Value}
+			p.bindName(ident, bound)
+			value = ident
+		}
+		pattern.Keys = append(pattern.Keys, key)
+		pattern.Values = append(pattern.Values, value)
+		if p.peekTokenIs(token.RBRACE) {
+			break
+		}
+		if !p.expectPeek(token.COMMA) {
+			return nil
+		}
+		if p.peekTokenIs(token.RBRACE) {
+			p.errorAt(p.peekToken, "expected a key after , but get }")
+			return nil
+		}
+	}
+	p.nextToken()
+	pattern.Rbrace = p.curToken
+	return pattern
+}
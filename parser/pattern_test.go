@@ -0,0 +1,137 @@
+package parser
+
+import (
+	"khanhanh_lang/ast"
+	"khanhanh_lang/lexer"
+	"strings"
+	"testing"
+)
+
+func TestLetPattern(t *testing.T) {
+	tests := []struct {
+		input   string
+		pattern string
+	}{
+		{"let [a, b, ...rest] = arr;", "[a, b, ...rest]"},
+		{"let [] = arr;", "[]"},
+		{"let [...all] = arr;", "[...all]"},
+		{"let [_, _, c] = arr;", "[_, _, c]"},
+		{"let {name, age} = person;", "{name, age}"},
+		{"let {name: n, pos: [x, y]} = person;", "{name: n, pos: [x, y]}"},
+		{"let [{id}, [a, [b]]] = rows;", "[{id}, [a, [b]]]"},
+	}
+	for _, tt := range tests {
+		par := New(lexer.New(tt.input))
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+		stmt, ok := program.Statements[0].(*ast.LetStatement)
+		if !ok {
+			t.Fatalf("stmt is not *ast.LetStatement. got=%T", program.Statements[0])
+		}
+		if stmt.Name != nil || stmt.Pattern == nil {
+			t.Fatalf("%s: a destructuring let should only have a Pattern. got name=%v", tt.input, stmt.Name)
+		}
+		if stmt.Pattern.String() != tt.pattern {
+			t.Errorf("%s: wrong pattern. expect=%s got=%s", tt.input, tt.pattern, stmt.Pattern.String())
+		}
+	}
+}
+
+func TestParameterPattern(t *testing.T) {
+	par := New(lexer.New("func([a, b], {name}, c) { a }"))
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+	stmt := testExpression(t, program.Statements[0])
+	function, ok := stmt.Expression.(*ast.FunctionLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression is not *ast.FunctionLiteral. got=%T", stmt.Expression)
+	}
+	expect := []string{"[a, b]", "{name}", "c"}
+	if len(function.Parameters) != len(expect) {
+		t.Fatalf("function should have %d parameters. got=%d", len(expect), len(function.Parameters))
+	}
+	for i, param := range function.Parameters {
+		if param.String() != expect[i] {
+			t.Errorf("parameter %d: expect=%s got=%s", i, expect[i], param.String())
+		}
+	}
+}
+
+func TestParameterDefaultsAndRest(t *testing.T) {
+	par := New(lexer.New("func(a, b = 10, [c] = [a], ...rest) { a }"))
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+	stmt := testExpression(t, program.Statements[0])
+	function := stmt.Expression.(*ast.FunctionLiteral)
+	expect := []string{"a", "b = 10", "[c] = [a]", "...rest"}
+	if len(function.Parameters) != len(expect) {
+		t.Fatalf("function should have %d parameters. got=%d", len(expect), len(function.Parameters))
+	}
+	for i, param := range function.Parameters {
+		if param.String() != expect[i] {
+			t.Errorf("parameter %d: expect=%s got=%s", i, expect[i], param.String())
+		}
+	}
+	if _, ok := function.Parameters[1].(*ast.DefaultParameter); !ok {
+		t.Errorf("b = 10 should be *ast.DefaultParameter. got=%T", function.Parameters[1])
+	}
+	if _, ok := function.Parameters[3].(*ast.SpreadExpression); !ok {
+		t.Errorf("...rest should be *ast.SpreadExpression. got=%T", function.Parameters[3])
+	}
+}
+
+func TestCallArguments(t *testing.T) {
+	par := New(lexer.New("f(1, ...xs, by: 2, to: g(3))"))
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+	stmt := testExpression(t, program.Statements[0])
+	call := stmt.Expression.(*ast.CallExpression)
+	expect := []string{"1", "...xs", "by: 2", "to: g(3)"}
+	if len(call.Arguments) != len(expect) {
+		t.Fatalf("call should have %d arguments. got=%d", len(expect), len(call.Arguments))
+	}
+	for i, arg := range call.Arguments {
+		if arg.String() != expect[i] {
+			t.Errorf("argument %d: expect=%s got=%s", i, expect[i], arg.String())
+		}
+	}
+	if _, ok := call.Arguments[2].(*ast.NamedArgument); !ok {
+		t.Errorf("by: 2 should be *ast.NamedArgument. got=%T", call.Arguments[2])
+	}
+}
+
+func TestPatternErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"let [a, a] = x", "1:9: a is bound twice"},
+		{"let {a, b: a} = x", "1:12: a is bound twice"},
+		{"func(a, [a]) { a }", "1:10: a is bound twice"},
+		{"let [...a, b] = x", "1:10: ...rest must be the last element of an array pattern"},
+		{"let [a, ] = x", "1:9: expected a name after , but get ]"},
+		{"let {a, } = x", "1:9: expected a key after , but get }"},
+		{"let [1] = x", "1:6: expected a name or a pattern , but get INT"},
+		{`let {"a": b} = x`, "expected next token : INDENT , but get STRING"},
+		{"func(1) { }", "1:6: expected a name or a pattern , but get INT"},
+		{"func(a = 1, b) { }", "1:13: parameter b need a default , it follow a parameter with a default"},
+		{"func(...a, b) { }", "1:10: ...rest must be the last parameter"},
+		{"func(...[a]) { }", "expected next token : INDENT , but get ["},
+		{"func(a, ...a) { }", "1:12: a is bound twice"},
+		{"f(a: 1, 2)", "1:9: positional argument after a named argument"},
+		{"f(a: 1, a: 2)", "1:9: argument a is given twice"},
+	}
+	for _, tt := range tests {
+		par := New(lexer.New(tt.input))
+		par.ParseProgram()
+		errs := strings.Join(par.Errors(), "\n")
+		if !strings.Contains(errs, tt.expect) {
+			t.Errorf("%s wrong errors. expect=%q got=%q", tt.input, tt.expect, errs)
+		}
+	}
+	par := New(lexer.New("let [a,"))
+	par.ParseProgram()
+	if !par.Incomplete() {
+		t.Errorf("an unclosed pattern should be incomplete. errors=%v", par.Errors())
+	}
+}
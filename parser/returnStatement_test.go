@@ -32,3 +32,23 @@ func TestReturnStatement(t *testing.T) {
 
 	}
 }
+
+func TestReturnWithoutSemicolonInBlock(t *testing.T) {
+	input := `if (true) { return 9 } return 10`
+
+	lex := lexer.New(input)
+	par := New(lex)
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+	if len(program.Statements) != 2 {
+		t.Fatalf("program should contain the if and the return statement. got=%d", len(program.Statements))
+	}
+	stmt := testExpression(t, program.Statements[0])
+	ifExp, ok := stmt.Expression.(*ast.IfExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.IfExpression. got=%T", stmt.Expression)
+	}
+	if len(ifExp.Consequence.Statements) != 1 || ifExp.Consequence.Rbrace.Literal != "}" {
+		t.Errorf("consequence should hold one statement and end at }. got=%q", ifExp.Consequence.String())
+	}
+}
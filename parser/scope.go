@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"fmt"
+	"khanhanh_lang/ast"
+)
+
+// SCOPES
+// ------------------------------------------------------------------------
+// The parser follow the scopes the evaluator create : the program , each function , each catch , each match arm
+// and the body of each for , the blocks of if and try share the scope around them . A name bound by const cannot
+// be bound again in its scope , so redefining it is reported before anything run . An inner scope can still hide it
+
+// names bound by const in a scope
+type scope map[string]bool
+
+func (p *Parser) openScope() {
+	p.scopes = append(p.scopes, scope{})
+}
+
+func (p *Parser) closeScope() {
+	p.scopes = p.scopes[:len(p.scopes)-1]
+}
+
+// record names bound in the current scope , constant tell whether they are bound by const
+func (p *Parser) declare(names []*ast.Identifier, constant bool) {
+	current := p.scopes[len(p.scopes)-1]
+	for _, name := range names {
+		if current[name.Value] {
+			p.errorAt(name.Token, fmt.Sprintf("cannot redefine const %s", name.Value))
+			continue
+		}
+		if constant && name.Value != "_" {
+			current[name.Value] = true
+		}
+	}
+}
+
+// every name bound by a let target , in the order they are written
+func boundNames(target ast.Expression) []*ast.Identifier {
+	switch target := target.(type) {
+	case *ast.Identifier:
+		return []*ast.Identifier{target}
+	case *ast.ArrayPattern:
+		names := []*ast.Identifier{}
+		for _, el := range target.Elements {
+			names = append(names, boundNames(el)...)
+		}
+		if target.Rest != nil {
+			names = append(names, target.Rest)
+		}
+		return names
+	case *ast.HashPattern:
+		names := []*ast.Identifier{}
+		for _, value := range target.Values {
+			names = append(names, boundNames(value)...)
+		}
+		return names
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"khanhanh_lang/ast"
+	"khanhanh_lang/lexer"
+	"strings"
+	"testing"
+)
+
+func TestConstStatement(t *testing.T) {
+	par := New(lexer.New("const LIMIT = 10; const [A, B] = pair; let x = LIMIT;"))
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+	expect := []struct {
+		text     string
+		constant bool
+	}{
+		{"const LIMIT = 10;", true},
+		{"const [A, B] = pair;", true},
+		{"let x = LIMIT;", false},
+	}
+	for i, tt := range expect {
+		stmt, ok := program.Statements[i].(*ast.LetStatement)
+		if !ok {
+			t.Fatalf("stmt is not *ast.LetStatement. got=%T", program.Statements[i])
+		}
+		if stmt.String() != tt.text || stmt.IsConst() != tt.constant {
+			t.Errorf("wrong statement. expect=%s const=%v got=%s const=%v", tt.text, tt.constant, stmt.String(), stmt.IsConst())
+		}
+	}
+}
+
+func TestConstRedefinition(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"const A = 1; let A = 2", "1:18: cannot redefine const A"},
+		{"const A = 1; const A = 2", "1:20: cannot redefine const A"},
+		{"const [A, B] = x; let {B} = y", "1:24: cannot redefine const B"},
+		{"const list = 1; import \"lib/list\"", "1:24: cannot redefine const list"},
+		{"const P = 1; struct P { x }", "1:21: cannot redefine const P"},
+		// the blocks of if and try are in the scope around them
+		{"const A = 1; if (true) { let A = 2 }", "1:30: cannot redefine const A"},
+		{"const A = 1; try { let A = 2 } finally { }", "1:24: cannot redefine const A"},
+		{"let f = func() { const A = 1; let A = 2 }", "1:35: cannot redefine const A"},
+	}
+	for _, tt := range tests {
+		par := New(lexer.New(tt.input))
+		par.ParseProgram()
+		errs := strings.Join(par.Errors(), "\n")
+		if !strings.Contains(errs, tt.expect) {
+			t.Errorf("%s wrong errors. expect=%q got=%q", tt.input, tt.expect, errs)
+		}
+	}
+}
+
+func TestConstShadowing(t *testing.T) {
+	// functions , catch blocks and match arms have their own scope
+	inputs := []string{
+		"const A = 1; let f = func(A) { let A = 2 }",
+		"const A = 1; let f = func() { let A = 2 }",
+		"const A = 1; try { 1 } catch (A) { let A = 2 }",
+		"const A = 1; match 1 { A => { let A = 2 } }",
+		"let A = 1; let A = 2; const A = 3",
+		"const _ = 1; let _ = 2",
+	}
+	for _, input := range inputs {
+		par := New(lexer.New(input))
+		par.ParseProgram()
+		checkParserErrors(t, par)
+	}
+}
@@ -0,0 +1,45 @@
+package parser
+
+import (
+	"fmt"
+	"khanhanh_lang/ast"
+	"khanhanh_lang/token"
+)
+
+// struct Name { field, field } , the fields are comma separated names without trailing comma
+func (p *Parser) parseStructStatement() ast.Statement {
+	stmt := &ast.StructStatement{Token: p.curToken, Fields: []*ast.Identifier{}}
+	if !p.expectPeek(token.IDENT) {
+		return stmt
+	}
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	p.declare([]*ast.Identifier{stmt.Name}, false)
+	if !p.expectPeek(token.LBRACE) {
+		return stmt
+	}
+	seen := map[string]bool{}
+	for !p.peekTokenIs(token.RBRACE) {
+		if !p.expectPeek(token.IDENT) {
+			return stmt
+		}
+		field := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		if seen[field.Value] {
+			p.errorAt(field.Token, fmt.Sprintf("duplicate field %s in struct %s", field.Value, stmt.Name.Value))
+		}
+		seen[field.Value] = true
+		stmt.Fields = append(stmt.Fields, field)
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return stmt
+		}
+		if p.curTokenIs(token.COMMA) && p.peekTokenIs(token.RBRACE) {
+			p.errorAt(p.peekToken, "expected a field after , but get }")
+			return stmt
+		}
+	}
+	p.nextToken()
+	stmt.Rbrace = p.curToken
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	return stmt
+}
@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"khanhanh_lang/ast"
+	"khanhanh_lang/lexer"
+	"strings"
+	"testing"
+)
+
+func TestStructStatement(t *testing.T) {
+	tests := []struct {
+		input  string
+		name   string
+		fields []string
+	}{
+		{"struct Point { x, y }", "Point", []string{"x", "y"}},
+		{"struct Unit {};", "Unit", []string{}},
+		{"struct Line {\n  from,\n  to\n}", "Line", []string{"from", "to"}},
+	}
+	for _, tt := range tests {
+		par := New(lexer.New(tt.input))
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+		if len(program.Statements) != 1 {
+			t.Fatalf("program should contain 1 statement. got=%d", len(program.Statements))
+		}
+		stmt, ok := program.Statements[0].(*ast.StructStatement)
+		if !ok {
+			t.Fatalf("stmt is not *ast.StructStatement. got=%T", program.Statements[0])
+		}
+		if stmt.Name.Value != tt.name {
+			t.Errorf("wrong name. expect=%q got=%q", tt.name, stmt.Name.Value)
+		}
+		fields := []string{}
+		for _, field := range stmt.Fields {
+			fields = append(fields, field.Value)
+		}
+		if strings.Join(fields, ",") != strings.Join(tt.fields, ",") {
+			t.Errorf("wrong fields. expect=%v got=%v", tt.fields, fields)
+		}
+		if stmt.Rbrace.Literal != "}" {
+			t.Errorf("struct should end at }. got=%q", stmt.Rbrace.Literal)
+		}
+	}
+}
+
+func TestStructErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"struct { x }", "expected next token : INDENT , but get {"},
+		{"struct P { x, }", "expected a field after , but get }"},
+		{"struct P { x y }", "expected next token : ,"},
+		{"struct P { x, x }", "1:15: duplicate field x in struct P"},
+		{"struct P { 1 }", "expected next token : INDENT , but get INT"},
+	}
+	for _, tt := range tests {
+		par := New(lexer.New(tt.input))
+		par.ParseProgram()
+		errs := strings.Join(par.Errors(), "\n")
+		if !strings.Contains(errs, tt.expect) {
+			t.Errorf("%s wrong errors. expect=%q got=%q", tt.input, tt.expect, errs)
+		}
+	}
+	par := New(lexer.New("struct P { x,"))
+	par.ParseProgram()
+	if !par.Incomplete() {
+		t.Errorf("an unclosed struct should be incomplete. errors=%v", par.Errors())
+	}
+}
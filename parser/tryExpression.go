@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"khanhanh_lang/ast"
+	"khanhanh_lang/token"
+)
+
+func (p *Parser) parseThrowStatement() ast.Statement {
+	stmt := &ast.ThrowStatement{Token: p.curToken}
+	p.nextToken()
+	stmt.Value = p.parseExpression(LOWEST)
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	return stmt
+}
+
+// try { } catch (e) { } finally { } , with at least one of catch and finally
+func (p *Parser) parseTryExpression() ast.Expression {
+	expression := &ast.TryExpression{Token: p.curToken}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	expression.Body = p.parseBlockStatement()
+
+	if p.peekTokenIs(token.CATCH) {
+		p.nextToken()
+		if !p.expectPeek(token.LPAREN) || !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		expression.Param = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		if !p.expectPeek(token.RPAREN) || !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+		p.openScope()
+		expression.Catch = p.parseBlockStatement()
+		p.closeScope()
+	}
+	if p.peekTokenIs(token.FINALLY) {
+		p.nextToken()
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+		expression.Finally = p.parseBlockStatement()
+	}
+
+	if expression.Catch == nil && expression.Finally == nil {
+		msg := "expected catch or finally after try , but get " + string(p.peekToken.Type)
+		if p.peekTokenIs(token.EOF) {
+			p.eofError(p.peekToken, msg)
+		} else {
+			p.errorAt(p.peekToken, msg)
+		}
+		return nil
+	}
+	return expression
+}
@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"khanhanh_lang/ast"
+	"khanhanh_lang/lexer"
+	"strings"
+	"testing"
+)
+
+func TestThrowStatement(t *testing.T) {
+	par := New(lexer.New(`throw "boom"; throw Error(1 + 2)`))
+	program := par.ParseProgram()
+	checkParserErrors(t, par)
+	if len(program.Statements) != 2 {
+		t.Fatalf("program should contain 2 statements. got=%d", len(program.Statements))
+	}
+	for i, expect := range []string{`throw "boom";`, `throw Error((1 + 2));`} {
+		stmt, ok := program.Statements[i].(*ast.ThrowStatement)
+		if !ok {
+			t.Fatalf("stmt is not *ast.ThrowStatement. got=%T", program.Statements[i])
+		}
+		if stmt.String() != expect {
+			t.Errorf("wrong statement. expect=%s got=%s", expect, stmt.String())
+		}
+	}
+}
+
+func TestTryExpression(t *testing.T) {
+	tests := []struct {
+		input   string
+		param   string
+		catch   bool
+		finally bool
+	}{
+		{"try { a } catch (e) { b }", "e", true, false},
+		{"try { a } finally { c }", "", false, true},
+		{"try { a } catch (err) { b } finally { c }", "err", true, true},
+	}
+	for _, tt := range tests {
+		par := New(lexer.New(tt.input))
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+		stmt := testExpression(t, program.Statements[0])
+		exp, ok := stmt.Expression.(*ast.TryExpression)
+		if !ok {
+			t.Fatalf("stmt.Expression is not *ast.TryExpression. got=%T", stmt.Expression)
+		}
+		if exp.Body == nil || len(exp.Body.Statements) != 1 {
+			t.Errorf("%s: body should hold 1 statement", tt.input)
+		}
+		if (exp.Catch != nil) != tt.catch || (exp.Finally != nil) != tt.finally {
+			t.Errorf("%s: wrong clauses. catch=%v finally=%v", tt.input, exp.Catch != nil, exp.Finally != nil)
+		}
+		if tt.catch && exp.Param.Value != tt.param {
+			t.Errorf("%s: wrong param. expect=%s got=%s", tt.input, tt.param, exp.Param.Value)
+		}
+	}
+}
+
+func TestTryErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"try { a }; b", "1:10: expected catch or finally after try , but get ;"},
+		{"try { a } catch { b }", "expected next token : ( , but get {"},
+		{"try { a } catch (1) { b }", "expected next token : INDENT , but get INT"},
+		{"try a catch (e) { b }", "expected next token : { , but get INDENT"},
+	}
+	for _, tt := range tests {
+		par := New(lexer.New(tt.input))
+		par.ParseProgram()
+		errs := strings.Join(par.Errors(), "\n")
+		if !strings.Contains(errs, tt.expect) {
+			t.Errorf("%s wrong errors. expect=%q got=%q", tt.input, tt.expect, errs)
+		}
+	}
+	par := New(lexer.New("try { a }"))
+	par.ParseProgram()
+	if !par.Incomplete() {
+		t.Errorf("a try waiting for its catch should be incomplete. errors=%v", par.Errors())
+	}
+}
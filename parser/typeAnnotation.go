@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"fmt"
+	"khanhanh_lang/ast"
+	"khanhanh_lang/token"
+)
+
+// TYPE ANNOTATIONS
+// ------------------------------------------------------------------------
+// let x: int = 5 , func(a: string, b: int = 1): bool { } , every annotation is optional
+// A type is a name followed by its arguments between < and > , only array<T> and hash<K, V> take some ,
+// any other name is kept as is , the checker and the evaluator read it as the name of a struct
+
+// number of arguments each generic type take , array and hash alone mean array<any> and hash<any, any>
+var typeArguments = map[string]int{"array": 1, "hash": 2}
+
+// parse the annotation after a : , when the peek token is a : , the type is nil when there is none
+// and ok is false when the annotation is invalid
+func (p *Parser) parseAnnotation() (typ *ast.Type, ok bool) {
+	if !p.peekTokenIs(token.COLON) {
+		return nil, true
+	}
+	p.nextToken()
+	p.nextToken()
+	typ = p.parseType()
+	return typ, typ != nil
+}
+
+func (p *Parser) parseType() *ast.Type {
+	if !p.curTokenIs(token.IDENT) && !p.curTokenIs(token.FUNCTION) {
+		msg := fmt.Sprintf("expected a type , but get %s", p.curToken.Type)
+		if p.curTokenIs(token.EOF) {
+			p.eofError(p.curToken, msg)
+		} else {
+			p.errorAt(p.curToken, msg)
+		}
+		return nil
+	}
+	typ := &ast.Type{Token: p.curToken}
+	if !p.peekTokenIs(token.LT) {
+		return typ
+	}
+	p.nextToken()
+	for {
+		p.nextToken()
+		arg := p.parseType()
+		if arg == nil {
+			return nil
+		}
+		typ.Arguments = append(typ.Arguments, arg)
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+	if !p.expectPeek(token.GT) {
+		return nil
+	}
+	typ.Close = p.curToken
+	switch want, got := typeArguments[typ.Token.Literal], len(typ.Arguments); {
+	case want == 0:
+		p.errorAt(typ.Token, fmt.Sprintf("%s does not take type arguments", typ.Token.Literal))
+	case want == 1 && got != 1:
+		p.errorAt(typ.Token, fmt.Sprintf("%s take 1 type argument , got %d", typ.Token.Literal, got))
+	case want != got:
+		p.errorAt(typ.Token, fmt.Sprintf("%s take %d type arguments , got %d", typ.Token.Literal, want, got))
+	}
+	return typ
+}
@@ -0,0 +1,118 @@
+package parser
+
+import (
+	"khanhanh_lang/ast"
+	"khanhanh_lang/lexer"
+	"strings"
+	"testing"
+)
+
+func TestLetAnnotation(t *testing.T) {
+	tests := []struct {
+		input      string
+		annotation string
+	}{
+		{"let x: int = 5;", "int"},
+		{"const NAMES: array<string> = [];", "array<string>"},
+		{"let h: hash<string, array<int>> = {};", "hash<string, array<int>>"},
+		{"let [a, b]: array<float> = pair;", "array<float>"},
+		{"let p: Point = origin;", "Point"},
+		{"let f: func = print;", "func"},
+		{"let x = 5;", ""},
+	}
+	for _, tt := range tests {
+		par := New(lexer.New(tt.input))
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+		stmt, ok := program.Statements[0].(*ast.LetStatement)
+		if !ok {
+			t.Fatalf("stmt is not *ast.LetStatement. got=%T", program.Statements[0])
+		}
+		got := ""
+		if stmt.Type != nil {
+			got = stmt.Type.String()
+		}
+		if got != tt.annotation {
+			t.Errorf("%s: wrong annotation. expect=%q got=%q", tt.input, tt.annotation, got)
+		}
+		if stmt.String() != tt.input {
+			t.Errorf("wrong statement. expect=%s got=%s", tt.input, stmt.String())
+		}
+	}
+}
+
+func TestFunctionAnnotations(t *testing.T) {
+	tests := []struct {
+		input  string
+		params []string
+		types  []string
+		result string
+	}{
+		{"func(a: string, b: int): bool { a }", []string{"a", "b"}, []string{"string", "int"}, "bool"},
+		{"func(a, b: int = 10, ...rest: array<int>) { a }", []string{"a", "b = 10", "...rest"}, []string{"", "int", "array<int>"}, ""},
+		{"func([x, y]: array<int>, {k}) { x }", []string{"[x, y]", "{k}"}, []string{"array<int>", ""}, ""},
+		{"func(): hash<string, int> { {} }", []string{}, nil, "hash<string, int>"},
+		{"func(a, b) { a }", []string{"a", "b"}, nil, ""},
+	}
+	for _, tt := range tests {
+		par := New(lexer.New(tt.input))
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+		stmt := testExpression(t, program.Statements[0])
+		function, ok := stmt.Expression.(*ast.FunctionLiteral)
+		if !ok {
+			t.Fatalf("stmt.Expression is not *ast.FunctionLiteral. got=%T", stmt.Expression)
+		}
+		if len(function.Parameters) != len(tt.params) {
+			t.Fatalf("%s: wrong number of parameters. got=%d", tt.input, len(function.Parameters))
+		}
+		// the types stay nil when no parameter is annotated
+		if (tt.types == nil) != (function.ParameterTypes == nil) {
+			t.Errorf("%s: wrong parameter types. got=%v", tt.input, function.ParameterTypes)
+		}
+		for i, param := range function.Parameters {
+			if param.String() != tt.params[i] {
+				t.Errorf("%s: parameter %d expect=%s got=%s", tt.input, i, tt.params[i], param.String())
+			}
+			got := ""
+			if typ := function.ParameterType(i); typ != nil {
+				got = typ.String()
+			}
+			if tt.types != nil && got != tt.types[i] {
+				t.Errorf("%s: type of parameter %d expect=%q got=%q", tt.input, i, tt.types[i], got)
+			}
+		}
+		result := ""
+		if function.ReturnType != nil {
+			result = function.ReturnType.String()
+		}
+		if result != tt.result {
+			t.Errorf("%s: wrong result type. expect=%q got=%q", tt.input, tt.result, result)
+		}
+	}
+}
+
+func TestAnnotationErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"let x: = 5", "1:8: expected a type , but get ="},
+		{"let x: 5 = 5", "1:8: expected a type , but get INT"},
+		{"let x: int<string> = 5", "1:8: int does not take type arguments"},
+		{"let x: array<int, int> = 5", "1:8: array take 1 type argument , got 2"},
+		{"let x: hash<int> = 5", "1:8: hash take 2 type arguments , got 1"},
+		{"let x: array<int = 5", "1:18: expected next token : > , but get ="},
+		{"func(a: ) { a }", "1:9: expected a type , but get )"},
+		{"func(a): { a }", "1:10: expected a type , but get {"},
+		{"func(...r: 1) { r }", "1:12: expected a type , but get INT"},
+	}
+	for _, tt := range tests {
+		par := New(lexer.New(tt.input))
+		par.ParseProgram()
+		errs := strings.Join(par.Errors(), "\n")
+		if !strings.Contains(errs, tt.expect) {
+			t.Errorf("%s: wrong errors. expect=%q got=%q", tt.input, tt.expect, errs)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package parser
+
+import (
+	"khanhanh_lang/ast"
+	"khanhanh_lang/token"
+)
+
+// GENERATORS
+// ------------------------------------------------------------------------
+// func*() { } is a generator , and so is every function whose body contain a yield . A yield belong to the
+// innermost function around it , it cannot appear outside a function body nor in a parameter default
+
+// tokens that cannot start the value of a yield , a yield followed by one of them is bare
+var yieldEnds = map[token.TokenType]bool{
+	token.SEMICOLON: true,
+	token.RBRACE:    true,
+	token.RPAREN:    true,
+	token.RBRACKET:  true,
+	token.COMMA:     true,
+	token.COLON:     true,
+	token.EOF:       true,
+}
+
+func (p *Parser) parseYieldExpression() ast.Expression {
+	expression := &ast.YieldExpression{Token: p.curToken}
+	if p.function == nil {
+		p.errorAt(p.curToken, "yield outside the body of a function")
+		return nil
+	}
+	p.function.Generator = true
+	if yieldEnds[p.peekToken.Type] {
+		return expression
+	}
+	p.nextToken()
+	expression.Value = p.parseExpression(LOWEST)
+	return expression
+}
@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"khanhanh_lang/ast"
+	"khanhanh_lang/lexer"
+	"strings"
+	"testing"
+)
+
+func TestGeneratorFunctions(t *testing.T) {
+	tests := []struct {
+		input     string
+		generator bool
+		expect    string
+	}{
+		{"func*() { 1 }", true, "func*() 1"},
+		{"func() { yield 1 }", true, "func*() (yield 1)"},
+		{"func() { yield; 2 }", true, "func*() yield2"},
+		{"func() { let x = yield 1 + 2; x }", true, "func*() let x = (yield (1 + 2));x"},
+		{"func() { f(yield, [yield 1]) }", true, "func*() f(yield, [(yield 1)])"},
+		{"func() { if (a) { yield a } }", true, "func*() ifa (yield a)"},
+		{"func() { func() { yield 1 } }", false, "func() func*() (yield 1)"},
+		{"func(a) { a }", false, "func(a) a"},
+	}
+	for _, tt := range tests {
+		par := New(lexer.New(tt.input))
+		program := par.ParseProgram()
+		checkParserErrors(t, par)
+		stmt := testExpression(t, program.Statements[0])
+		fn, ok := stmt.Expression.(*ast.FunctionLiteral)
+		if !ok {
+			t.Fatalf("stmt.Expression is not *ast.FunctionLiteral. got=%T", stmt.Expression)
+		}
+		if fn.Generator != tt.generator {
+			t.Errorf("%s: expect generator=%t", tt.input, tt.generator)
+		}
+		if fn.String() != tt.expect {
+			t.Errorf("%s: wrong function. expect=%q got=%q", tt.input, tt.expect, fn.String())
+		}
+	}
+}
+
+func TestYieldErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"yield 1", "1:1: yield outside the body of a function"},
+		{"let f = func(a = yield 1) { a }", "1:18: yield outside the body of a function"},
+		{"func*(a) { a } + yield", "1:18: yield outside the body of a function"},
+	}
+	for _, tt := range tests {
+		par := New(lexer.New(tt.input))
+		par.ParseProgram()
+		errs := strings.Join(par.Errors(), "\n")
+		if !strings.Contains(errs, tt.expect) {
+			t.Errorf("%s wrong errors. expect=%q got=%q", tt.input, tt.expect, errs)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package repl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"khanhanh_lang/ast"
+	"khanhanh_lang/evaluator"
+	"khanhanh_lang/host"
+	"khanhanh_lang/lexer"
+	"khanhanh_lang/object"
+	"khanhanh_lang/token"
+	"strings"
+	"time"
+)
+
+// REPL COMMANDS
+// ---------------------------------------------------------------------------------
+// Line starting with : are commands for the REPL itself , not source code
+
+type command struct {
+	name     string
+	args     string // argument shown in :help
+	help     string
+	run      func(s *session, arg string)
+	needsArg bool // the command require an argument
+}
+
+var commands []command
+
+func init() {
+	// assigned in init since :help refer to the table itself
+	commands = []command{
+		{name: "help", help: "show this help", run: (*session).cmdHelp},
+		{name: "env", help: "list the bindings of the session with their type", run: (*session).cmdEnv},
+		{name: "type", args: "<expr>", help: "show the type of the value of expr", run: (*session).cmdType, needsArg: true},
+		{name: "ast", args: "<expr>", help: "show the parsed tree of expr", run: (*session).cmdAST, needsArg: true},
+		{name: "tokens", args: "<expr>", help: "show the tokens of expr", run: (*session).cmdTokens, needsArg: true},
+		{name: "load", args: "<file>", help: "evaluate a file in the session", run: (*session).cmdLoad, needsArg: true},
+		{name: "save", args: "<file>", help: "write every successful input of the session to a file", run: (*session).cmdSave, needsArg: true},
+		{name: "reset", help: "forget every binding and input of the session", run: (*session).cmdReset},
+		{name: "time", args: "<expr>", help: "evaluate expr and show how long it took", run: (*session).cmdTime, needsArg: true},
+	}
+}
+
+// run a :command line
+func (s *session) runCommand(line string) {
+	name, arg, _ := strings.Cut(strings.TrimPrefix(line, ":"), " ")
+	arg = strings.TrimSpace(arg)
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		if cmd.needsArg && arg == "" {
+			s.printError([]string{fmt.Sprintf("usage: :%s %s", cmd.name, cmd.args)})
+			return
+		}
+		cmd.run(s, arg)
+		return
+	}
+	s.printError([]string{fmt.Sprintf("unknown command :%s , type :help for the list", name)})
+}
+
+func (s *session) cmdHelp(string) {
+	for _, cmd := range commands {
+		usage := ":" + cmd.name
+		if cmd.args != "" {
+			usage += " " + cmd.args
+		}
+		s.println(fmt.Sprintf("  %-16s %s", usage, cmd.help))
+	}
+	s.println(fmt.Sprintf("  %-16s %s", "quit", "leave the REPL"))
+}
+
+func (s *session) cmdEnv(string) {
+	for _, name := range s.tracker.Names() {
+		val, _ := s.tracker.Get(name)
+		s.println(fmt.Sprintf("%s : %s", name, val.Type()))
+	}
+}
+
+// parse arg , printing the errors when it is not valid
+func (s *session) parseArg(arg string) (*ast.Program, bool) {
+	program, errors, _, _ := parse(arg)
+	if len(errors) != 0 {
+		s.printError(errors)
+		return nil, false
+	}
+	return program, true
+}
+
+// :type evaluate in a scope enclosed by the session , so a let inside expr does not leak
+func (s *session) cmdType(arg string) {
+	program, ok := s.parseArg(arg)
+	if !ok {
+		return
+	}
+	evaluated := evaluator.Eval(program, object.NewEnclosedTracker(s.tracker))
+	if evaluated == nil {
+		s.println(object.NIL_OBJ)
+		return
+	}
+	if evaluated.Type() == object.ERROR_OBJ {
+		s.printError([]string{errorText(evaluated.(*object.Error))})
+		return
+	}
+	s.println(string(evaluated.Type()))
+}
+
+func (s *session) cmdAST(arg string) {
+	program, ok := s.parseArg(arg)
+	if !ok {
+		return
+	}
+	var out bytes.Buffer
+	ast.Fprint(&out, program)
+	s.println(strings.TrimSuffix(out.String(), "\n"))
+}
+
+func (s *session) cmdTokens(arg string) {
+	l := lexer.New(arg)
+	for {
+		tok := l.NextToken()
+		s.println(fmt.Sprintf("%d:%d\t%-8s %q", tok.Line, tok.Column, tok.Type, tok.Literal))
+		if tok.Type == token.EOF {
+			return
+		}
+	}
+}
+
+var errNoFS = errors.New("no filesystem access")
+
+// the files :load and :save reach , those the host give to the evaluated code
+func (s *session) fs() (*host.FS, error) {
+	if s.host == nil || s.host.FS == nil {
+		return nil, errNoFS
+	}
+	return s.host.FS, nil
+}
+
+func (s *session) cmdLoad(arg string) {
+	fs, err := s.fs()
+	if err != nil {
+		s.printError([]string{err.Error()})
+		return
+	}
+	src, err := fs.ReadFile(arg)
+	if err != nil {
+		s.printError([]string{err.Error()})
+		return
+	}
+	program, ok := s.parseArg(string(src))
+	if !ok {
+		return
+	}
+	s.eval(strings.TrimRight(string(src), "\n"), program)
+}
+
+func (s *session) cmdSave(arg string) {
+	content := strings.Join(s.inputs, "\n")
+	if content != "" {
+		content += "\n"
+	}
+	fs, err := s.fs()
+	if err != nil {
+		s.printError([]string{err.Error()})
+		return
+	}
+	if err := fs.WriteFile(arg, []byte(content)); err != nil {
+		s.printError([]string{err.Error()})
+		return
+	}
+	s.println(fmt.Sprintf("saved %d inputs to %s", len(s.inputs), arg))
+}
+
+func (s *session) cmdReset(string) {
+	s.tracker.Close()
+	s.tracker = s.newTracker()
+	s.inputs = nil
+}
+
+func (s *session) cmdTime(arg string) {
+	program, ok := s.parseArg(arg)
+	if !ok {
+		return
+	}
+	start := time.Now()
+	s.eval(arg, program)
+	s.println(fmt.Sprintf("took %s", time.Since(start)))
+}
@@ -0,0 +1,94 @@
+package repl
+
+import (
+	"bytes"
+	"khanhanh_lang/ast"
+	"khanhanh_lang/lexer"
+	"khanhanh_lang/parser"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// run the REPL over input , return what it wrote to Out without the prompts and what it wrote to Err
+func runCommands(input string) (string, string) {
+	var out, errs bytes.Buffer
+	Run(Config{
+		In:                 strings.NewReader(input),
+		Out:                &out,
+		Err:                &errs,
+		Prompt:             "$ ",
+		ContinuationPrompt: "$ ",
+	})
+	return strings.ReplaceAll(out.String(), "$ ", ""), errs.String()
+}
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		out    string
+		errors string
+	}{
+		{"env", "let a = 1\nlet s = \"x\"\n:env", "a : INTEGER\ns : STRING\n", ""},
+		{"env of a new session", ":env", "", ""},
+		{"type", ":type 1 + 2.5\n:type \"a\"\n:type [1]", "FLOAT\nSTRING\nARRAY\n", ""},
+		{"type does not bind", ":type let z = 1\nz\n:env", "NIL\n", "\t1:1: [Error]: Identifier not found: z\n"},
+		{"type of an error", ":type nope", "", "\t1:1: [Error]: Identifier not found: nope\n"},
+		{"type of invalid code", ":type let = 1", "", "\t1:5: expected next token : INDENT , but get =\n\t1:5: no prefix parse function for = found \n"},
+		{"tokens", ":tokens let a", "1:1\tLET      \"let\"\n1:5\tINDENT   \"a\"\n1:6\tEOF      \"\"\n", ""},
+		{"reset", "let a = 1\n:reset\na\n:env", "", "\t1:1: [Error]: Identifier not found: a\n"},
+		{"reset keep the session usable", "let a = 1\n:reset\nlet a = 2\na", "2\n", ""},
+		{"missing argument", ":type\n:ast\n:tokens", "", "\tusage: :type <expr>\n\tusage: :ast <expr>\n\tusage: :tokens <expr>\n"},
+		{"unknown", ":nope", "", "\tunknown command :nope , type :help for the list\n"},
+	}
+	for _, tt := range tests {
+		out, errs := runCommands(tt.input)
+		if out != tt.out {
+			t.Errorf("%s: wrong output. expected=%q got=%q", tt.name, tt.out, out)
+		}
+		if errs != tt.errors {
+			t.Errorf("%s: wrong errors. expected=%q got=%q", tt.name, tt.errors, errs)
+		}
+	}
+}
+
+func TestHelpCommand(t *testing.T) {
+	out, _ := runCommands(":help")
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != len(commands)+1 {
+		t.Fatalf("expected a line per command and quit. got=%q", out)
+	}
+	for i, cmd := range commands {
+		if !strings.HasPrefix(strings.TrimSpace(lines[i]), ":"+cmd.name) || !strings.HasSuffix(lines[i], cmd.help) {
+			t.Errorf("wrong help for :%s. got=%q", cmd.name, lines[i])
+		}
+	}
+	if !strings.Contains(out, "  :type <expr>     show the type of the value of expr\n") {
+		t.Errorf("the arguments of a command should be shown. got=%q", out)
+	}
+}
+
+func TestASTCommand(t *testing.T) {
+	out, errs := runCommands(":ast let x = 1 + 2 * y")
+	var expected bytes.Buffer
+	ast.Fprint(&expected, parser.New(lexer.New("let x = 1 + 2 * y")).ParseProgram())
+	if out != expected.String() || errs != "" {
+		t.Errorf("wrong tree. expected=%q got=%q , errors=%q", expected.String(), out, errs)
+	}
+	// nothing is evaluated
+	if out, _ := runCommands(":ast let x = 1\n:env"); strings.Contains(out, "x : INTEGER") {
+		t.Errorf(":ast should not bind anything. got=%q", out)
+	}
+}
+
+func TestTimeCommand(t *testing.T) {
+	out, errs := runCommands(":time let a = 20 + 1\na")
+	if !regexp.MustCompile(`^took [0-9.]+[a-zµ]+s\n21\n$`).MatchString(out) || errs != "" {
+		t.Errorf("wrong output. got=%q , errors=%q", out, errs)
+	}
+	out, errs = runCommands(":time 1 + true")
+	if !strings.HasPrefix(out, "took ") || errs != "\t1:3: [Error]: Mismatch INTEGER + BOOLEAN\n" {
+		t.Errorf("an error should still be timed. got=%q , errors=%q", out, errs)
+	}
+}
@@ -0,0 +1,76 @@
+package repl
+
+import (
+	"io"
+	"khanhanh_lang/host"
+	"khanhanh_lang/repl/lineedit"
+	"os"
+	"strings"
+
+	"github.com/diontr00/logStack"
+)
+
+// Config describe where a REPL read , write and log
+// The zero value is usable : nothing is written to the filesystem , to stdout or to a log
+type Config struct {
+	In  io.Reader // source of the input lines , the line editor is used when it is a terminal
+	Out io.Writer // prompts and results
+	Err io.Writer // parse and evaluation errors , Out when nil
+
+	// shown before a new input , the colored khanhanh_lang prompt when empty
+	Prompt string
+	// shown while the current input is not complete yet , dots as wide as the default prompt when empty
+	ContinuationPrompt string
+
+	// file keeping the history of the line editor between runs , no history is saved when empty
+	HistoryFile string
+
+	// destination of the REPL own diagnostics (failed writes , unreadable history) , discarded when nil
+	LogOutput io.Writer
+	LogLevel  logStack.Level
+
+	// never color the output , even on a terminal
+	NoColor bool
+
+	// what the evaluated code may reach , :load and :save go through its FS too : print go to Out and eprint to
+	// Err when nil , without access to the input nor to the filesystem
+	Host *host.Host
+}
+
+// DefaultConfig is the configuration of the khanhanh_lang command :
+// the process standard streams , the whole filesystem and the history file under the user config directory
+// read_line is not available , the REPL already read the standard input
+func DefaultConfig() Config {
+	config := Config{
+		In:       os.Stdin,
+		Out:      os.Stdout,
+		Err:      os.Stderr,
+		LogLevel: logStack.WarnLevel,
+		Host:     host.Unrestricted(),
+	}
+	config.Host.Stdin = nil
+	if path, err := lineedit.DefaultHistoryFile("khanhanh_lang"); err == nil {
+		config.HistoryFile = path
+	}
+	return config
+}
+
+// fill the fields left empty
+func (c Config) withDefaults() Config {
+	if c.In == nil {
+		c.In = strings.NewReader("")
+	}
+	if c.Out == nil {
+		c.Out = io.Discard
+	}
+	if c.Err == nil {
+		c.Err = c.Out
+	}
+	if c.Host == nil {
+		c.Host = &host.Host{Stdout: c.Out, Stderr: c.Err}
+	}
+	if c.LogOutput == nil {
+		c.LogOutput = io.Discard
+	}
+	return c
+}
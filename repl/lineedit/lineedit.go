@@ -0,0 +1,458 @@
+package lineedit
+
+// Minimal line editor for the REPL , Emacs keybindings , history and tab completion
+// The terminal is put in raw mode only while a line is being read , so program output stay untouched
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrNotTerminal = errors.New("lineedit: input is not a terminal")
+	ErrInterrupt   = errors.New("lineedit: interrupted") // Ctrl-C , the current line is abandoned
+)
+
+// keep the history file small enough to load instantly
+const maxHistory = 1000
+
+// Completer return the candidates for the word that end at pos in line , and the index where that word start
+type Completer func(line []rune, pos int) (start int, candidates []string)
+
+// Highlighter return the line decorated for display ( colors ) , it must not change the visible text
+type Highlighter func(line string) string
+
+type Editor struct {
+	in  *bufio.Reader
+	out io.Writer
+	fd  int // file descriptor put in raw mode , -1 when the editor is not driving a real terminal
+
+	Complete  Completer
+	Highlight Highlighter
+
+	history     []string
+	historyFile string
+
+	// state of the line being edited
+	buf         []rune
+	pos         int
+	killed      []rune // last killed text , for Ctrl-Y
+	histIdx     int    // index in history while browsing with up / down , len(history) is the line being typed
+	saved       []rune // line being typed , kept while browsing history
+	prompt      string
+	promptWidth int
+}
+
+// New create an editor reading keys from the terminal in and drawing on out
+// ErrNotTerminal is returned when in is not a terminal , the caller should then read plain lines
+func New(in *os.File, out io.Writer) (*Editor, error) {
+	fd := int(in.Fd())
+	if !isTerminal(fd) {
+		return nil, ErrNotTerminal
+	}
+	e := newEditor(in, out)
+	e.fd = fd
+	return e, nil
+}
+
+func newEditor(in io.Reader, out io.Writer) *Editor {
+	return &Editor{in: bufio.NewReader(in), out: out, fd: -1}
+}
+
+// ReadLine show prompt and let the user edit a line , the line is returned without the newline
+// Ctrl-D on an empty line return io.EOF and Ctrl-C return ErrInterrupt
+func (e *Editor) ReadLine(prompt string) (string, error) {
+	if e.fd >= 0 {
+		restore, err := makeRaw(e.fd)
+		if err != nil {
+			return "", err
+		}
+		defer restore()
+	}
+	return e.edit(prompt)
+}
+
+// HISTORY
+// ---------------------------------------------------------------------------------
+
+// DefaultHistoryFile return the history path under the user config directory
+func DefaultHistoryFile(app string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, app, "history"), nil
+}
+
+// SetHistoryFile load the history saved in path , and append every new entry to it
+func (e *Editor) SetHistoryFile(path string) error {
+	e.historyFile = path
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			e.history = append(e.history, line)
+		}
+	}
+	if len(e.history) > maxHistory {
+		e.history = e.history[len(e.history)-maxHistory:]
+		// rewrite the file so it does not grow forever
+		content := strings.Join(e.history, "\n") + "\n"
+		return os.WriteFile(path, []byte(content), 0600)
+	}
+	return nil
+}
+
+// AddHistory record a line , empty lines and repeat of the previous entry are skipped
+func (e *Editor) AddHistory(line string) error {
+	if strings.TrimSpace(line) == "" || strings.Contains(line, "\n") {
+		return nil
+	}
+	if len(e.history) > 0 && e.history[len(e.history)-1] == line {
+		return nil
+	}
+	e.history = append(e.history, line)
+	if e.historyFile == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(e.historyFile), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(e.historyFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(line + "\n")
+	return err
+}
+
+// History return the recorded lines , oldest first
+func (e *Editor) History() []string {
+	return e.history
+}
+
+// EDITING
+// ---------------------------------------------------------------------------------
+const (
+	keyCtrlA     = 1
+	keyCtrlB     = 2
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyCtrlF     = 6
+	keyCtrlH     = 8
+	keyTab       = 9
+	keyLF        = 10
+	keyCtrlK     = 11
+	keyCtrlL     = 12
+	keyCR        = 13
+	keyCtrlN     = 14
+	keyCtrlP     = 16
+	keyCtrlT     = 20
+	keyCtrlU     = 21
+	keyCtrlW     = 23
+	keyCtrlY     = 25
+	keyEscape    = 27
+	keyBackspace = 127
+)
+
+func (e *Editor) edit(prompt string) (string, error) {
+	e.buf = e.buf[:0]
+	e.pos = 0
+	e.histIdx = len(e.history)
+	e.saved = nil
+	e.prompt = prompt
+	e.promptWidth = visibleWidth(prompt)
+	e.refresh()
+
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			if err == io.EOF && len(e.buf) > 0 {
+				e.write("\r\n")
+				return string(e.buf), nil
+			}
+			return "", err
+		}
+
+		switch r {
+		case keyCR, keyLF:
+			e.pos = len(e.buf)
+			e.refresh()
+			e.write("\r\n")
+			return string(e.buf), nil
+		case keyCtrlC:
+			e.write("^C\r\n")
+			return "", ErrInterrupt
+		case keyCtrlD:
+			if len(e.buf) == 0 {
+				e.write("\r\n")
+				return "", io.EOF
+			}
+			e.deleteForward()
+		case keyCtrlA:
+			e.pos = 0
+		case keyCtrlE:
+			e.pos = len(e.buf)
+		case keyCtrlB:
+			e.moveLeft()
+		case keyCtrlF:
+			e.moveRight()
+		case keyBackspace, keyCtrlH:
+			e.deleteBackward()
+		case keyCtrlK:
+			e.kill(e.pos, len(e.buf))
+		case keyCtrlU:
+			e.kill(0, e.pos)
+		case keyCtrlW:
+			e.kill(e.wordStart(), e.pos)
+		case keyCtrlY:
+			e.insert(e.killed...)
+		case keyCtrlT:
+			e.transpose()
+		case keyCtrlP:
+			e.historyMove(-1)
+		case keyCtrlN:
+			e.historyMove(1)
+		case keyCtrlL:
+			e.write("\x1b[H\x1b[2J")
+		case keyTab:
+			e.complete()
+		case keyEscape:
+			if err := e.escape(); err != nil {
+				return "", err
+			}
+		default:
+			if unicode.IsPrint(r) {
+				e.insert(r)
+			}
+		}
+		e.refresh()
+	}
+}
+
+// Handle escape sequences : arrows , home , end , delete and Alt-b / Alt-f / Alt-d
+func (e *Editor) escape() error {
+	r, _, err := e.in.ReadRune()
+	if err != nil {
+		return err
+	}
+	switch r {
+	case 'b':
+		e.pos = e.wordStart()
+		return nil
+	case 'f':
+		e.pos = e.wordEnd()
+		return nil
+	case 'd':
+		e.kill(e.pos, e.wordEnd())
+		return nil
+	case '[', 'O':
+	default:
+		return nil
+	}
+
+	// CSI : parameters then a final letter or ~
+	var params []rune
+	for {
+		r, _, err = e.in.ReadRune()
+		if err != nil {
+			return err
+		}
+		if (r >= 'A' && r <= 'Z') || r == '~' {
+			break
+		}
+		params = append(params, r)
+	}
+	switch {
+	case r == 'A':
+		e.historyMove(-1)
+	case r == 'B':
+		e.historyMove(1)
+	case r == 'C':
+		e.moveRight()
+	case r == 'D':
+		e.moveLeft()
+	case r == 'H', r == '~' && (string(params) == "1" || string(params) == "7"):
+		e.pos = 0
+	case r == 'F', r == '~' && (string(params) == "4" || string(params) == "8"):
+		e.pos = len(e.buf)
+	case r == '~' && string(params) == "3":
+		e.deleteForward()
+	}
+	return nil
+}
+
+func (e *Editor) insert(runes ...rune) {
+	buf := make([]rune, 0, len(e.buf)+len(runes))
+	buf = append(buf, e.buf[:e.pos]...)
+	buf = append(buf, runes...)
+	buf = append(buf, e.buf[e.pos:]...)
+	e.buf = buf
+	e.pos += len(runes)
+}
+
+func (e *Editor) moveLeft() {
+	if e.pos > 0 {
+		e.pos--
+	}
+}
+
+func (e *Editor) moveRight() {
+	if e.pos < len(e.buf) {
+		e.pos++
+	}
+}
+
+func (e *Editor) deleteBackward() {
+	if e.pos > 0 {
+		e.buf = append(e.buf[:e.pos-1], e.buf[e.pos:]...)
+		e.pos--
+	}
+}
+
+func (e *Editor) deleteForward() {
+	if e.pos < len(e.buf) {
+		e.buf = append(e.buf[:e.pos], e.buf[e.pos+1:]...)
+	}
+}
+
+// remove buf[from:to] and keep it for Ctrl-Y
+func (e *Editor) kill(from, to int) {
+	if from >= to {
+		return
+	}
+	e.killed = append([]rune{}, e.buf[from:to]...)
+	e.buf = append(e.buf[:from], e.buf[to:]...)
+	e.pos = from
+}
+
+func (e *Editor) transpose() {
+	if e.pos == 0 || len(e.buf) < 2 {
+		return
+	}
+	if e.pos == len(e.buf) {
+		e.pos--
+	}
+	e.buf[e.pos-1], e.buf[e.pos] = e.buf[e.pos], e.buf[e.pos-1]
+	e.pos++
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// start of the word before the cursor
+func (e *Editor) wordStart() int {
+	i := e.pos
+	for i > 0 && !isWordRune(e.buf[i-1]) {
+		i--
+	}
+	for i > 0 && isWordRune(e.buf[i-1]) {
+		i--
+	}
+	return i
+}
+
+// end of the word after the cursor
+func (e *Editor) wordEnd() int {
+	i := e.pos
+	for i < len(e.buf) && !isWordRune(e.buf[i]) {
+		i++
+	}
+	for i < len(e.buf) && isWordRune(e.buf[i]) {
+		i++
+	}
+	return i
+}
+
+func (e *Editor) historyMove(delta int) {
+	idx := e.histIdx + delta
+	if idx < 0 || idx > len(e.history) {
+		return
+	}
+	if e.histIdx == len(e.history) {
+		e.saved = append([]rune{}, e.buf...)
+	}
+	e.histIdx = idx
+	if idx == len(e.history) {
+		e.buf = append([]rune{}, e.saved...)
+	} else {
+		e.buf = []rune(e.history[idx])
+	}
+	e.pos = len(e.buf)
+}
+
+// COMPLETION
+// ---------------------------------------------------------------------------------
+func (e *Editor) complete() {
+	if e.Complete == nil {
+		return
+	}
+	start, candidates := e.Complete(e.buf, e.pos)
+	if len(candidates) == 0 {
+		return
+	}
+	word := string(e.buf[start:e.pos])
+	prefix := commonPrefix(candidates)
+	if len(prefix) > len(word) && strings.HasPrefix(prefix, word) {
+		e.insert([]rune(prefix[len(word):])...)
+		return
+	}
+	if len(candidates) == 1 {
+		return
+	}
+	// nothing more to insert , show the choices under the line
+	sorted := append([]string{}, candidates...)
+	sort.Strings(sorted)
+	e.write("\r\n" + strings.Join(sorted, "  ") + "\r\n")
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, w := range words[1:] {
+		for !strings.HasPrefix(w, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// DRAWING
+// ---------------------------------------------------------------------------------
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*[A-Za-z]")
+
+// number of columns the text take on screen , color escape codes take none
+func visibleWidth(s string) int {
+	return len([]rune(ansiEscape.ReplaceAllString(s, "")))
+}
+
+func (e *Editor) write(s string) {
+	io.WriteString(e.out, s)
+}
+
+// redraw the whole line and put the cursor back at pos
+func (e *Editor) refresh() {
+	line := string(e.buf)
+	if e.Highlight != nil {
+		line = e.Highlight(line)
+	}
+	e.write(fmt.Sprintf("\r%s%s\x1b[K\r", e.prompt, line))
+	if column := e.promptWidth + e.pos; column > 0 {
+		e.write(fmt.Sprintf("\x1b[%dC", column))
+	}
+}
@@ -0,0 +1,131 @@
+package lineedit
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readLine(t *testing.T, e *Editor, keys string) string {
+	t.Helper()
+	e.in.Reset(strings.NewReader(keys))
+	line, err := e.ReadLine("> ")
+	if err != nil {
+		t.Fatalf("keys %q: unexpected error %s", keys, err)
+	}
+	return line
+}
+
+func TestEditing(t *testing.T) {
+	tests := []struct {
+		keys     string
+		expected string
+	}{
+		{"let x = 5\r", "let x = 5"},
+		{"abc\x02\x02X\r", "aXbc"},                 // Ctrl-B twice then insert
+		{"abc\x01X\x05Y\r", "XabcY"},               // Ctrl-A , Ctrl-E
+		{"abc\x7f\r", "ab"},                        // backspace
+		{"abc\x01\x04\r", "bc"},                    // Ctrl-D delete under cursor
+		{"hello world\x02\x02\x0b\r", "hello wor"}, // Ctrl-K
+		{"hello world\x15\r", ""},                  // Ctrl-U
+		{"foo bar\x17\r", "foo "},                  // Ctrl-W
+		{"foo bar\x17\x01\x19\r", "barfoo "},       // Ctrl-W then Ctrl-Y at start
+		{"ab\x14\r", "ba"},                         // Ctrl-T
+		{"abc\x1b[D\x1b[DX\r", "aXbc"},             // left arrow
+		{"abc\x1b[HX\x1b[FY\r", "XabcY"},           // home , end
+		{"abc\x01\x1b[3~\r", "bc"},                 // delete key
+		{"one two\x1bbX\r", "one Xtwo"},            // Alt-b
+		{"🥳x\x02\x02Y\r", "Y🥳x"},                   // runes , not bytes
+	}
+	for _, test := range tests {
+		e := newEditor(strings.NewReader(""), io.Discard)
+		if got := readLine(t, e, test.keys); got != test.expected {
+			t.Errorf("keys %q: expected %q , got %q", test.keys, test.expected, got)
+		}
+	}
+}
+
+func TestEOFAndInterrupt(t *testing.T) {
+	e := newEditor(strings.NewReader("\x04"), io.Discard)
+	if _, err := e.ReadLine("> "); err != io.EOF {
+		t.Errorf("Ctrl-D on empty line should return io.EOF , got %v", err)
+	}
+	e = newEditor(strings.NewReader("abc\x03"), io.Discard)
+	if _, err := e.ReadLine("> "); err != ErrInterrupt {
+		t.Errorf("Ctrl-C should return ErrInterrupt , got %v", err)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "history")
+	e := newEditor(strings.NewReader(""), io.Discard)
+	if err := e.SetHistoryFile(path); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	for _, line := range []string{"first", "second", "second", "", "third"} {
+		if err := e.AddHistory(line); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+	if got := strings.Join(e.History(), ","); got != "first,second,third" {
+		t.Errorf("wrong history , got %q", got)
+	}
+
+	// up twice , down once , then edit
+	if got := readLine(t, e, "\x1b[A\x1b[A\x1b[B!\r"); got != "third!" {
+		t.Errorf("expected to recall third , got %q", got)
+	}
+	if got := readLine(t, e, "new\x10\x10\x0e\x0e\r"); got != "new" {
+		t.Errorf("browsing back down should restore the typed line , got %q", got)
+	}
+
+	reloaded := newEditor(strings.NewReader(""), io.Discard)
+	if err := reloaded.SetHistoryFile(path); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if got := strings.Join(reloaded.History(), ","); got != "first,second,third" {
+		t.Errorf("history not persisted , got %q", got)
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	words := []string{"let", "length", "lemon", "return"}
+	complete := func(line []rune, pos int) (int, []string) {
+		start := pos
+		for start > 0 && isWordRune(line[start-1]) {
+			start--
+		}
+		result := []string{}
+		for _, w := range words {
+			if strings.HasPrefix(w, string(line[start:pos])) {
+				result = append(result, w)
+			}
+		}
+		return start, result
+	}
+
+	var out bytes.Buffer
+	e := newEditor(strings.NewReader(""), &out)
+	e.Complete = complete
+	if got := readLine(t, e, "ret\t\r"); got != "return" {
+		t.Errorf("single candidate should be inserted , got %q", got)
+	}
+	if got := readLine(t, e, "x = len\t\r"); got != "x = length" {
+		t.Errorf("expected completion to length , got %q", got)
+	}
+	out.Reset()
+	if got := readLine(t, e, "le\t\r"); got != "le" {
+		t.Errorf("ambiguous completion should not change the line , got %q", got)
+	}
+	if !strings.Contains(out.String(), "lemon  length  let") {
+		t.Errorf("ambiguous completion should list the candidates , got %q", out.String())
+	}
+}
+
+func TestVisibleWidth(t *testing.T) {
+	if w := visibleWidth("\x1b[36;1mab\x1b[0m> "); w != 4 {
+		t.Errorf("expected width 4 , got %d", w)
+	}
+}
@@ -0,0 +1,217 @@
+package repl
+
+import (
+	"fmt"
+	"io"
+	"khanhanh_lang/lexer"
+	"khanhanh_lang/object"
+	"khanhanh_lang/token"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+// Colors are only used when out is a terminal , so output redirected to a file or a pipe stay plain text
+// The NO_COLOR environment variable ( https://no-color.org ) disable them everywhere
+func useColor(out io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	file, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	return isTerminal(file.Fd())
+}
+
+// replaced by the tests , which have no terminal
+var isTerminal = func(fd uintptr) bool {
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// PALETTE
+// ---------------------------------------------------------------------------------
+// every color used by the REPL , painting is a no-op when the palette is disabled
+type palette struct {
+	enabled bool
+
+	keyword  *color.Color
+	str      *color.Color
+	number   *color.Color
+	boolean  *color.Color
+	null     *color.Color
+	function *color.Color
+	err      *color.Color
+	comment  *color.Color
+	name     *color.Color
+	lang     *color.Color
+}
+
+func newPalette(enabled bool) *palette {
+	p := &palette{
+		enabled:  enabled,
+		keyword:  color.New(color.FgMagenta, color.Bold),
+		str:      color.New(color.FgGreen),
+		number:   color.New(color.FgCyan),
+		boolean:  color.New(color.FgYellow),
+		null:     color.New(color.Faint),
+		function: color.New(color.FgBlue),
+		err:      color.New(color.FgRed),
+		comment:  color.New(color.Faint, color.Italic),
+		name:     color.New(color.FgCyan, color.Bold),
+		lang:     color.New(color.FgHiGreen, color.Bold),
+	}
+	// the palette decide by itself , not the global color.NoColor which only look at stdout
+	for _, c := range []*color.Color{p.keyword, p.str, p.number, p.boolean, p.null, p.function, p.err, p.comment, p.name, p.lang} {
+		c.EnableColor()
+	}
+	return p
+}
+
+func (p *palette) paint(c *color.Color, text string) string {
+	if !p.enabled || c == nil || text == "" {
+		return text
+	}
+	return c.Sprint(text)
+}
+
+// prompt shown before a new input , or while the current input is not complete yet ( same width )
+func (p *palette) prompt(continuation bool) string {
+	if continuation {
+		return fmt.Sprintf("%s ~>> ", strings.Repeat(".", len("khanhanh_lang")))
+	}
+	return fmt.Sprintf("%s_%s ~>> ", p.paint(p.name, "khanhanh"), p.paint(p.lang, "lang"))
+}
+
+// VALUES
+// ---------------------------------------------------------------------------------
+// render a value for display , strings are quoted so "1" and 1 can be told apart
+func (p *palette) value(obj object.Object) string {
+	switch obj := obj.(type) {
+	case *object.String:
+		return p.paint(p.str, strconv.Quote(obj.Value))
+	case *object.Integer, *object.Float:
+		return p.paint(p.number, obj.Inspect())
+	case *object.Boolean:
+		return p.paint(p.boolean, obj.Inspect())
+	case *object.Nil:
+		return p.paint(p.null, obj.Inspect())
+	case *object.Function:
+		return p.paint(p.function, obj.Inspect())
+	case *object.Error:
+		return p.error(obj)
+	default:
+		return obj.Inspect()
+	}
+}
+
+func (p *palette) error(err *object.Error) string {
+	return p.paint(p.err, errorText(err))
+}
+
+// message of an error prefixed by where it happened , when known
+func errorText(err *object.Error) string {
+	if err.Pos.Line > 0 {
+		return fmt.Sprintf("%d:%d: %s", err.Pos.Line, err.Pos.Column, err.Message)
+	}
+	return err.Message
+}
+
+// HIGHLIGHT
+// ---------------------------------------------------------------------------------
+// color of a token while it is typed , nil keep the terminal default
+func (p *palette) tokenColor(tok token.Token) *color.Color {
+	switch tok.Type {
+	case token.INT, token.FLOAT:
+		return p.number
+	case token.STRING:
+		return p.str
+	case token.TRUE, token.FALSE:
+		return p.boolean
+	case token.ILLEGAL:
+		return p.err
+	}
+	if tok.Type != token.IDENT && token.LookUpKeyword(tok.Literal) == tok.Type {
+		return p.keyword
+	}
+	return nil
+}
+
+type segment struct {
+	start, end int
+	color      *color.Color
+}
+
+// highlight a line of input using the lexer , the visible text is never changed
+func (p *palette) highlight(line string) string {
+	if !p.enabled {
+		return line
+	}
+	l := lexer.New(line)
+	tokens := []token.Token{}
+	for {
+		tok := l.NextToken()
+		if tok.Type == token.EOF || tok.Line != 1 {
+			break
+		}
+		tokens = append(tokens, tok)
+	}
+	comments := []token.Token{}
+	for _, comment := range l.Comments() {
+		if comment.Line == 1 {
+			comments = append(comments, comment)
+		}
+	}
+
+	// a token end where the next token or comment start , less the blanks between them : its width in the source ,
+	// not the one of its literal ( an escaped string , a byte of a multi-byte character read as ILLEGAL )
+	starts := []int{len(line)}
+	for _, tok := range tokens {
+		starts = append(starts, tok.Column-1)
+	}
+	for _, comment := range comments {
+		starts = append(starts, comment.Column-1)
+	}
+	sort.Ints(starts)
+	end := func(start int) int {
+		i := sort.SearchInts(starts, start+1)
+		// the ILLEGAL tokens of the bytes of one character are painted as a whole
+		for starts[i] < len(line) && !utf8.RuneStart(line[starts[i]]) {
+			i++
+		}
+		return start + len(strings.TrimRight(line[start:starts[i]], " \t\r"))
+	}
+
+	segments := []segment{}
+	for _, tok := range tokens {
+		start := tok.Column - 1
+		if start >= len(line) || !utf8.RuneStart(line[start]) {
+			continue
+		}
+		if c := p.tokenColor(tok); c != nil {
+			segments = append(segments, segment{start, end(start), c})
+		}
+	}
+	for _, comment := range comments {
+		segments = append(segments, segment{comment.Column - 1, len(line), p.comment})
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i].start < segments[j].start })
+
+	var out strings.Builder
+	last := 0
+	for _, seg := range segments {
+		if seg.start < last {
+			continue
+		}
+		out.WriteString(line[last:seg.start])
+		out.WriteString(p.paint(seg.color, line[seg.start:seg.end]))
+		last = seg.end
+	}
+	out.WriteString(line[last:])
+	return out.String()
+}
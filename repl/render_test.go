@@ -0,0 +1,113 @@
+package repl
+
+import (
+	"bytes"
+	"khanhanh_lang/object"
+	"khanhanh_lang/token"
+	"os"
+	"regexp"
+	"testing"
+	"unicode/utf8"
+)
+
+var escapes = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestHighlightKeepText(t *testing.T) {
+	p := newPalette(true)
+	inputs := []string{
+		`let x = 1 + 2.5`,
+		`漢 + 1`,
+		`let 🥲 = 1`,
+		`"é\"t" + "ü" // ça`,
+		`if (true) { "unterminated`,
+		"\tfunc(a, b) { a == b }  ",
+		`x // only a comment`,
+		"\xff + 1",
+	}
+	for _, input := range inputs {
+		got := p.highlight(input)
+		if plain := escapes.ReplaceAllString(got, ""); plain != input {
+			t.Errorf("%q: the visible text changed. got=%q", input, plain)
+		}
+		if utf8.ValidString(input) && !utf8.ValidString(got) {
+			t.Errorf("%q: a color landed inside a character. got=%q", input, got)
+		}
+	}
+
+	// each token is painted over its width in the source
+	tests := map[string]string{
+		`let x = 1`: p.paint(p.keyword, "let") + " x = " + p.paint(p.number, "1"),
+		`"é" + 1`:   p.paint(p.str, `"é"`) + " + " + p.paint(p.number, "1"),
+		`漢 + 1`:     p.paint(p.err, "漢") + " + " + p.paint(p.number, "1"),
+		`1 // one`:  p.paint(p.number, "1") + " " + p.paint(p.comment, "// one"),
+	}
+	for input, expected := range tests {
+		if got := p.highlight(input); got != expected {
+			t.Errorf("%q: wrong highlight. expected=%q got=%q", input, expected, got)
+		}
+	}
+
+	if got := newPalette(false).highlight(`let x = 1`); got != `let x = 1` {
+		t.Errorf("a disabled palette should not paint. got=%q", got)
+	}
+}
+
+func TestValue(t *testing.T) {
+	err := &object.Error{Message: "[Error]: boom", Pos: token.Position{Line: 2, Column: 5}}
+	tests := []struct {
+		obj   object.Object
+		plain string
+	}{
+		{&object.String{Value: "a\n"}, `"a\n"`},
+		{&object.Integer{Value: 1}, "1"},
+		{&object.Float{Value: 2.5}, "2.5"},
+		{&object.Boolean{Value: true}, "true"},
+		{&object.Nil{}, "nil"},
+		{err, "2:5: [Error]: boom"},
+		{&object.Array{Elements: []object.Object{&object.String{Value: "x"}}}, `["x"]`},
+	}
+	plain, colored := newPalette(false), newPalette(true)
+	for _, tt := range tests {
+		if got := plain.value(tt.obj); got != tt.plain {
+			t.Errorf("plain value of %s. expected=%q got=%q", tt.obj.Inspect(), tt.plain, got)
+		}
+		got := colored.value(tt.obj)
+		if stripped := escapes.ReplaceAllString(got, ""); stripped != tt.plain {
+			t.Errorf("colored value of %s should show the same text. expected=%q got=%q", tt.obj.Inspect(), tt.plain, stripped)
+		}
+	}
+	if got := colored.value(&object.Integer{Value: 1}); got != colored.paint(colored.number, "1") || got == "1" {
+		t.Errorf("a number should be painted. got=%q", got)
+	}
+	if got := colored.value(&object.Array{}); got != "[]" {
+		t.Errorf("a collection is not painted as a whole. got=%q", got)
+	}
+}
+
+func TestUseColor(t *testing.T) {
+	defer func(original func(uintptr) bool) { isTerminal = original }(isTerminal)
+	isTerminal = func(uintptr) bool { return true }
+	t.Setenv("NO_COLOR", "")
+
+	if useColor(&bytes.Buffer{}) {
+		t.Errorf("a writer that is not a file is never a terminal")
+	}
+	if !useColor(os.Stdout) {
+		t.Errorf("a terminal should be colored")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if useColor(os.Stdout) {
+		t.Errorf("NO_COLOR should disable the colors even on a terminal")
+	}
+
+	isTerminal = func(uintptr) bool { return false }
+	t.Setenv("NO_COLOR", "")
+	file, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if useColor(file) {
+		t.Errorf("a file that is not a terminal should stay plain")
+	}
+}
@@ -2,81 +2,282 @@ package repl
 
 import (
 	"bufio"
-	"fmt"
 	"io"
+	"khanhanh_lang/ast"
 	"khanhanh_lang/evaluator"
+	"khanhanh_lang/host"
 	"khanhanh_lang/lexer"
 	"khanhanh_lang/object"
 	"khanhanh_lang/parser"
+	"khanhanh_lang/repl/lineedit"
+	"khanhanh_lang/token"
 	"os"
+	"strings"
+	"unicode"
 
 	"github.com/diontr00/logStack"
-	"github.com/fatih/color"
 )
 
-var PROMPT string
+// source of input lines , the line editor on a terminal and plain lines otherwise
+type lineReader interface {
+	ReadLine(prompt string) (string, error)
+}
 
-var log *logStack.Logger
+// read lines without any editing , used when the input is not a terminal (pipe , file , test)
+type plainReader struct {
+	scanner *bufio.Scanner
+	out     io.Writer
+}
 
-func init() {
-	cyan := color.New(color.FgCyan, color.Bold).SprintFunc()
-	green := color.New(color.FgHiGreen, color.Bold).SprintFunc()
-	PROMPT = fmt.Sprintf("%s_%s ~>> ", cyan("khanhanh"), green("lang"))
-	log = logStack.DefaultLogger()
+func (r *plainReader) ReadLine(prompt string) (string, error) {
+	if _, err := io.WriteString(r.out, prompt); err != nil {
+		return "", err
+	}
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return r.scanner.Text(), nil
 }
 
-func Start(in io.Reader, out io.Writer) {
-	scanner := bufio.NewScanner(in)
-	tracker := object.NewTracker()
-	logFile, err := os.OpenFile("./log.test", os.O_CREATE|os.O_APPEND, 0644)
-	defer func() { logFile.Close() }()
+// use the line editor when in is a terminal , degrade to plain lines otherwise
+func newLineReader(config Config, s *session) lineReader {
+	plain := &plainReader{scanner: bufio.NewScanner(config.In), out: config.Out}
+	file, ok := config.In.(*os.File)
+	if !ok {
+		return plain
+	}
+	editor, err := lineedit.New(file, config.Out)
 	if err != nil {
-		log.DPanic(err.Error())
+		return plain
+	}
+	if config.HistoryFile != "" {
+		if err := editor.SetHistoryFile(config.HistoryFile); err != nil {
+			s.log.Warn(err.Error())
+		}
+	}
+	editor.Complete = completer(s)
+	if s.colors.enabled {
+		editor.Highlight = s.colors.highlight
 	}
+	return editor
+}
 
-	log := logStack.NewLogger(logFile, logStack.DPanicLevel)
-	logStack.ResetDefault(log)
-	for {
-		fmt.Printf("%s", PROMPT)
-		scanned := scanner.Scan()
-		if !scanned {
-			return
+// complete keywords , REPL commands and every name bound in the session
+func completer(s *session) lineedit.Completer {
+	return func(line []rune, pos int) (int, []string) {
+		start := pos
+		for start > 0 && (unicode.IsLetter(line[start-1]) || unicode.IsDigit(line[start-1]) || line[start-1] == '_') {
+			start--
+		}
+		word := string(line[start:pos])
+		// :command at the start of the line
+		if start == 1 && line[0] == ':' {
+			candidates := []string{}
+			for _, cmd := range commands {
+				if strings.HasPrefix(cmd.name, word) {
+					candidates = append(candidates, cmd.name)
+				}
+			}
+			return start, candidates
+		}
+		// member of a value , strings.up or name.tr
+		if start > 0 && line[start-1] == '.' {
+			return start, s.memberCandidates(line[:start-1], word)
+		}
+		if word == "" {
+			return start, nil
 		}
-		line := scanner.Text()
+		words := append(token.Keywords(), "quit")
+		words = append(words, s.tracker.Names()...)
+		words = append(words, evaluator.Builtins()...)
+		candidates := []string{}
+		seen := map[string]bool{}
+		for _, w := range words {
+			if strings.HasPrefix(w, word) && !seen[w] {
+				seen[w] = true
+				candidates = append(candidates, w)
+			}
+		}
+		return start, candidates
+	}
+}
 
-		if line == "quit" {
-			return
+// members of the value named just before the dot that start with prefix :
+// the members of a module , the fields of an instance , the string keys of a hash and the methods of its type
+func (s *session) memberCandidates(before []rune, prefix string) []string {
+	start := len(before)
+	for start > 0 && (unicode.IsLetter(before[start-1]) || unicode.IsDigit(before[start-1]) || before[start-1] == '_') {
+		start--
+	}
+	program, errors, _, _ := parse(string(before[start:]))
+	if len(errors) != 0 || len(program.Statements) != 1 {
+		return nil
+	}
+	value := evaluator.Eval(program, s.tracker)
+	if value == nil || value.Type() == object.ERROR_OBJ {
+		return nil
+	}
+	var names []string
+	switch value := value.(type) {
+	case *object.Module:
+		names = value.Names()
+	case *object.Instance:
+		names = append(names, value.Struct.Fields...)
+	case *object.Hash:
+		for _, pair := range value.Pairs() {
+			if key, ok := pair.Key.(*object.String); ok {
+				names = append(names, key.Value)
+			}
 		}
-		l := lexer.New(line)
-		p := parser.New(l)
-		program := p.ParseProgram()
-		if len(p.Errors()) != 0 {
-			printError(out, p.Errors())
+	}
+	names = append(names, evaluator.Methods(value.Type())...)
+	candidates := []string{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) && !seen[name] {
+			seen[name] = true
+			candidates = append(candidates, name)
+		}
+	}
+	return candidates
+}
+
+// state of one REPL run
+type session struct {
+	out       io.Writer
+	err       io.Writer
+	tracker   *object.Tracker
+	host      *host.Host // given to every tracker of the session
+	inputs    []string   // every input that parsed and evaluated without error , for :save
+	colors    *palette   // for out
+	errColors *palette   // for err
+	log       *logStack.Logger
+}
+
+func (s *session) newTracker() *object.Tracker {
+	tracker := object.NewTracker()
+	tracker.SetHost(s.host)
+	return tracker
+}
+
+// Start run a REPL reading from in and writing every output to out
+func Start(in io.Reader, out io.Writer) {
+	Run(Config{In: in, Out: out})
+}
+
+// Run a REPL until its input end or quit is typed
+func Run(config Config) {
+	config = config.withDefaults()
+	s := &session{
+		out:       config.Out,
+		err:       config.Err,
+		host:      config.Host,
+		colors:    newPalette(!config.NoColor && useColor(config.Out)),
+		errColors: newPalette(!config.NoColor && useColor(config.Err)),
+		log:       logStack.NewLogger(config.LogOutput, config.LogLevel),
+	}
+	s.tracker = s.newTracker()
+	// the tracker may be replaced by :reset , close the last one
+	defer func() { s.tracker.Close() }()
+	reader := newLineReader(config, s)
+	// lines of the statement being typed , kept until the parser accept them
+	var pending []string
+	for {
+		line, err := reader.ReadLine(s.prompt(config, len(pending) != 0))
+		if err == lineedit.ErrInterrupt {
+			pending = nil
 			continue
 		}
-		evaluated := evaluator.Eval(program, tracker)
+		if err != nil {
+			return
+		}
+		if editor, ok := reader.(*lineedit.Editor); ok {
+			if err := editor.AddHistory(line); err != nil {
+				s.log.Warn(err.Error())
+			}
+		}
 
-		if evaluated != nil {
-			_, err := io.WriteString(out, evaluated.Inspect())
-			if err != nil {
-				log.Warn(err.Error())
+		if len(pending) == 0 {
+			if line == "quit" {
+				return
 			}
-			_, err = io.WriteString(out, "\n")
-			if err != nil {
-				log.Warn(err.Error())
+			if strings.HasPrefix(strings.TrimSpace(line), ":") {
+				s.runCommand(strings.TrimSpace(line))
+				continue
 			}
-
 		}
+		pending = append(pending, line)
+		input := strings.Join(pending, "\n")
+		program, errors, warnings, incomplete := parse(input)
+		if len(errors) != 0 {
+			// wait for the rest of the statement , an empty line give up and show what is wrong
+			if incomplete && strings.TrimSpace(line) != "" {
+				continue
+			}
+			pending = nil
+			s.printError(errors)
+			continue
+		}
+		pending = nil
+		s.printWarnings(warnings)
+		s.eval(input, program)
+	}
+}
 
+// parse input , returning the program with the parser errors and warnings
+func parse(input string) (*ast.Program, []string, []string, bool) {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	return program, p.Errors(), p.Warnings(), p.Incomplete()
+}
+
+// evaluate a parsed input in the session , print the result and remember the input when it succeed
+func (s *session) eval(input string, program *ast.Program) object.Object {
+	evaluated := evaluator.Eval(program, s.tracker)
+	if evaluated == nil || evaluated.Type() != object.ERROR_OBJ {
+		s.inputs = append(s.inputs, input)
+	}
+	if err, ok := evaluated.(*object.Error); ok {
+		s.printError([]string{errorText(err)})
+	} else if evaluated != nil && evaluated != evaluator.NIL {
+		// like print , an input evaluating to nil show nothing
+		s.println(s.colors.value(evaluated))
 	}
+	return evaluated
+}
+
+func (s *session) prompt(config Config, continuation bool) string {
+	if !continuation && config.Prompt != "" {
+		return config.Prompt
+	}
+	if continuation && config.ContinuationPrompt != "" {
+		return config.ContinuationPrompt
+	}
+	return s.colors.prompt(continuation)
+}
+
+func (s *session) println(text string) {
+	s.write(s.out, text)
 }
 
-func printError(out io.Writer, errors []string) {
+func (s *session) printError(errors []string) {
 	for _, msg := range errors {
-		msg = color.RedString(msg)
-		_, err := io.WriteString(out, "\t"+msg+"\n")
-		if err != nil {
-			log.Warn(err.Error())
-		}
+		s.write(s.err, "\t"+s.errColors.paint(s.errColors.err, msg))
+	}
+}
+
+// warnings do not stop the input from running , they are shown before its result
+func (s *session) printWarnings(warnings []string) {
+	for _, msg := range warnings {
+		s.write(s.err, "\twarning: "+msg)
+	}
+}
+
+func (s *session) write(w io.Writer, text string) {
+	if _, err := io.WriteString(w, text+"\n"); err != nil {
+		s.log.Warn(err.Error())
 	}
 }
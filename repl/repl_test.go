@@ -0,0 +1,146 @@
+package repl
+
+import (
+	"bytes"
+	"khanhanh_lang/evaluator"
+	"khanhanh_lang/host"
+	"khanhanh_lang/lexer"
+	"khanhanh_lang/object"
+	"khanhanh_lang/parser"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunWithConfig(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	var out, errs bytes.Buffer
+	Run(Config{
+		In:     strings.NewReader("let a = \"hi\"\na\n1 + true\nquit\n"),
+		Out:    &out,
+		Err:    &errs,
+		Prompt: "> ",
+	})
+
+	if out.String() != "> > \"hi\"\n> > " {
+		t.Errorf("wrong output. got=%q", out.String())
+	}
+	if errs.String() != "\t1:3: [Error]: Mismatch INTEGER + BOOLEAN\n" {
+		t.Errorf("wrong errors. got=%q", errs.String())
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("the REPL should not create any file. got=%d entries", len(entries))
+	}
+}
+
+func TestRunHost(t *testing.T) {
+	var out, errs bytes.Buffer
+	Run(Config{
+		In:     strings.NewReader("print(\"hi\", 1)\neprint(\"oops\")\nread_file(\"go.mod\")\n"),
+		Out:    &out,
+		Err:    &errs,
+		Prompt: "> ",
+	})
+
+	if out.String() != "> hi 1\n> > > " {
+		t.Errorf("wrong output. got=%q", out.String())
+	}
+	if !strings.HasPrefix(errs.String(), "oops\n") {
+		t.Errorf("eprint should write to Err. got=%q", errs.String())
+	}
+	if !strings.Contains(errs.String(), "read_file: not allowed by the host") {
+		t.Errorf("the REPL should not give access to the filesystem by default. got=%q", errs.String())
+	}
+}
+
+func TestLoadAndSave(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "in.kh"), []byte("let a = 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fs := &host.FS{Roots: []string{dir}}
+
+	var out, errs bytes.Buffer
+	Run(Config{
+		In:     strings.NewReader(":load in.kh\na * 3\n:save out.kh\n:load ../outside.kh\n"),
+		Out:    &out,
+		Err:    &errs,
+		Prompt: "> ",
+		Host:   &host.Host{FS: fs},
+	})
+	if out.String() != "> > 6\n> saved 2 inputs to out.kh\n> > " {
+		t.Errorf("wrong output. got=%q", out.String())
+	}
+	if saved, err := os.ReadFile(filepath.Join(dir, "out.kh")); err != nil || string(saved) != "let a = 2\na * 3\n" {
+		t.Errorf("wrong saved inputs. got=%q , %v", saved, err)
+	}
+	if !strings.Contains(errs.String(), "not allowed by the host") {
+		t.Errorf(":load should not reach outside of the host roots. got=%q", errs.String())
+	}
+
+	// without a filesystem given by the host
+	out.Reset()
+	errs.Reset()
+	Run(Config{
+		In:     strings.NewReader(":load in.kh\n1\n:save out.kh\n"),
+		Out:    &out,
+		Err:    &errs,
+		Prompt: "> ",
+	})
+	if errs.String() != "\tno filesystem access\n\tno filesystem access\n" {
+		t.Errorf("wrong errors. got=%q", errs.String())
+	}
+}
+
+func TestRunWarnings(t *testing.T) {
+	var out, errs bytes.Buffer
+	Run(Config{
+		In:     strings.NewReader("match true { true => 1 }\n"),
+		Out:    &out,
+		Err:    &errs,
+		Prompt: "> ",
+	})
+
+	if out.String() != "> 1\n> " {
+		t.Errorf("a warning should not stop the input from running. got=%q", out.String())
+	}
+	if errs.String() != "\twarning: 1:1: match is not exhaustive , false is not matched\n" {
+		t.Errorf("wrong warning. got=%q", errs.String())
+	}
+}
+
+func TestCompleteMembers(t *testing.T) {
+	s := &session{tracker: object.NewTracker()}
+	s.tracker.Set("h", evaluator.Eval(parser.New(lexer.New(`{"name": 1, "keep": 2}`)).ParseProgram(), s.tracker))
+	s.tracker.Set("word", &object.String{Value: "abc"})
+	complete := completer(s)
+	tests := []struct {
+		line   string
+		expect []string
+	}{
+		{"strings.up", []string{"upper"}},
+		{"h.k", []string{"keep", "keys"}},
+		{"word.s", []string{"split", "starts_with", "substr"}},
+		{"missing.a", []string{}},
+	}
+	for _, tt := range tests {
+		_, candidates := complete([]rune(tt.line), len([]rune(tt.line)))
+		if strings.Join(candidates, " ") != strings.Join(tt.expect, " ") {
+			t.Errorf("%s wrong candidates. expect=%v got=%v", tt.line, tt.expect, candidates)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"khanhanh_lang/ast"
+	"khanhanh_lang/astjson"
+	"khanhanh_lang/evaluator"
+	"khanhanh_lang/host"
+	"khanhanh_lang/lexer"
+	"khanhanh_lang/object"
+	"khanhanh_lang/parser"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// run subcommand , evaluate a file ( or stdin ) and print the result unless it is nil
+// with --ast the input is a JSON AST as printed by the parse subcommand
+// the script get the standard streams and the whole filesystem , --root limit it to some directories
+// --strict make the operators refuse to convert a value to a string , "a" + 1 is an error
+func runRun(args []string) int {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	fromAST := flags.Bool("ast", false, "input is a JSON AST instead of source")
+	var roots rootsFlag
+	flags.Var(&roots, "root", "directory the script may access , can be repeated")
+	readOnly := flags.Bool("read-only", false, "forbid the script to write files")
+	strict := flags.Bool("strict", false, "never convert a value to a string in an operator")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	src, err := readInput(flags.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	var program *ast.Program
+	if *fromAST {
+		program, err = astjson.UnmarshalProgram(src)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	} else {
+		p := parser.New(lexer.New(string(src)))
+		program = p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			fmt.Fprintln(os.Stderr, strings.Join(p.Errors(), "\n"))
+			return 1
+		}
+		for _, warning := range p.Warnings() {
+			fmt.Fprintln(os.Stderr, "warning: "+warning)
+		}
+	}
+
+	scriptHost := host.Unrestricted()
+	if len(roots) != 0 {
+		scriptHost.FS.Roots = roots
+	}
+	scriptHost.FS.ReadOnly = *readOnly
+	tracker := object.NewTracker()
+	defer tracker.Close()
+	tracker.SetHost(scriptHost)
+	tracker.SetStrict(*strict)
+	// imports are relative to the script file
+	if len(flags.Args()) == 1 {
+		if file, err := scriptHost.FS.Resolve(flags.Arg(0)); err == nil {
+			tracker.SetFile(file)
+		} else if file, err := filepath.Abs(flags.Arg(0)); err == nil {
+			tracker.SetFile(file)
+		}
+	}
+
+	evaluated := evaluator.Eval(program, tracker)
+	if evaluated == nil || evaluated == evaluator.NIL {
+		return 0
+	}
+	if evaluated.Type() == object.ERROR_OBJ {
+		fmt.Fprintln(os.Stderr, evaluated.Inspect())
+		return 1
+	}
+	fmt.Println(evaluated.Inspect())
+	return 0
+}
+
+// --root can be given several times
+type rootsFlag []string
+
+func (r *rootsFlag) String() string {
+	return strings.Join(*r, ",")
+}
+
+func (r *rootsFlag) Set(dir string) error {
+	*r = append(*r, dir)
+	return nil
+}
@@ -1,5 +1,7 @@
 package token
 
+import "sort"
+
 // String is used as TokenType to easier for debug and distinguish
 type TokenType string
 
@@ -7,10 +9,12 @@ const (
 	EOF = "EOF" // End of file
 
 	ILLEGAL = "ILLEGAL" // Signifies unknown token
+	COMMENT = "COMMENT" // Line comment , never returned by NextToken but recorded by the lexer
 
 	// Identifier  and  Literal
 	IDENT  = "INDENT" // Identifier like foo , bar
 	INT    = "INT"    // Interger literal like 1 , 2 , 3
+	FLOAT  = "FLOAT"  // Float literal like 1.5 , 0.25
 	STRING = "STRING"
 
 	// Operators
@@ -26,18 +30,35 @@ const (
 	// Delimiters
 	COMMA     = ","
 	SEMICOLON = ";"
+	DOT       = "."
+	COLON     = ":"
+	ARROW     = "=>"
+	ELLIPSIS  = "..."
 
-	LPAREN = "("
-	RPAREN = ")"
-	LBRACE = "{"
-	RBRACE = "}"
+	LPAREN   = "("
+	RPAREN   = ")"
+	LBRACE   = "{"
+	RBRACE   = "}"
+	LBRACKET = "["
+	RBRACKET = "]"
 
 	// Keyword
 	FUNCTION = "FUNCTION"
 	LET      = "LET"
+	CONST    = "CONST"
 	IF       = "IF"
 	ELSE     = "ELSE"
 	RETURN   = "RETURN"
+	IMPORT   = "IMPORT"
+	STRUCT   = "STRUCT"
+	THROW    = "THROW"
+	TRY      = "TRY"
+	CATCH    = "CATCH"
+	FINALLY  = "FINALLY"
+	MATCH    = "MATCH"
+	FOR      = "FOR"
+	IN       = "IN"
+	YIELD    = "YIELD"
 	TRUE     = "TRUE"
 	FALSE    = "FALSE"
 	EQ       = "=="
@@ -47,18 +68,61 @@ const (
 )
 
 type Token struct {
-	Type    TokenType
-	Literal string
+	Type    TokenType `json:"type"`
+	Literal string    `json:"literal"`
+	Line    int       `json:"line"`   // 1-based line where the token start
+	Column  int       `json:"column"` // 1-based column (in byte) where the token start
+}
+
+// Line and column in the source , 1-based
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Position of the first character of the token
+func (t Token) Pos() Position {
+	return Position{Line: t.Line, Column: t.Column}
+}
+
+// Position just after the last character of the token , string literal include both quotes
+func (t Token) End() Position {
+	width := len(t.Literal)
+	if t.Type == STRING {
+		width += 2
+	}
+	return Position{Line: t.Line, Column: t.Column + width}
 }
 
 var keywords = map[string]TokenType{
-	"func":   FUNCTION,
-	"let":    LET,
-	"true":   TRUE,
-	"false":  FALSE,
-	"if":     IF,
-	"else":   ELSE,
-	"return": RETURN,
+	"func":    FUNCTION,
+	"let":     LET,
+	"const":   CONST,
+	"true":    TRUE,
+	"false":   FALSE,
+	"if":      IF,
+	"else":    ELSE,
+	"return":  RETURN,
+	"import":  IMPORT,
+	"struct":  STRUCT,
+	"throw":   THROW,
+	"try":     TRY,
+	"catch":   CATCH,
+	"finally": FINALLY,
+	"match":   MATCH,
+	"for":     FOR,
+	"in":      IN,
+	"yield":   YIELD,
+}
+
+// Keywords return every keyword of the language , used for completion
+func Keywords() []string {
+	result := make([]string, 0, len(keywords))
+	for keyword := range keywords {
+		result = append(result, keyword)
+	}
+	sort.Strings(result)
+	return result
 }
 
 // Look up the keyword table , if Identifier indeed a keyword , then return the keyword constant
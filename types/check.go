@@ -0,0 +1,615 @@
+package types
+
+import (
+	"fmt"
+	"khanhanh_lang/ast"
+	"khanhanh_lang/token"
+)
+
+// CHECK
+// ---------------------------------------------------------------------------------
+// Check infer the type of every expression before the program run and report the mismatches it is sure about :
+// an operator that cannot apply to its operands or that silently convert a value to a string , a value that does
+// not fit its annotation , a call with the wrong arguments . A name the checker know nothing about ( a builtin ,
+// an import ) is any and any is never reported , so code without annotations is only checked where the types are
+// obvious
+
+// Check return the problems found in program , prefixed by line:column like the parser errors
+func Check(program *ast.Program) []string {
+	c := &checker{scopes: []map[string]*Type{{}}, inferred: map[ast.Expression]*Type{}}
+	c.statements(program.Statements)
+	return c.errors
+}
+
+type checker struct {
+	errors   []string
+	scopes   []map[string]*Type       // type of the names bound in each scope , innermost last
+	results  []*Type                  // expected result of the functions being checked , innermost last
+	inferred map[ast.Expression]*Type // type of the expressions already checked
+}
+
+func (c *checker) errorAt(tok token.Token, format string, args ...any) {
+	c.errors = append(c.errors, fmt.Sprintf("%d:%d: ", tok.Line, tok.Column)+fmt.Sprintf(format, args...))
+}
+
+// report when a value of type value cannot go where a target is expected , what describe the place
+// the elements of an array or hash literal are checked one by one , so [1, "a"] is not just an array<any>
+func (c *checker) expect(value, target *Type, at ast.Expression, what string) {
+	switch lit := at.(type) {
+	case *ast.ArrayLiteral:
+		if value.Kind == Array && target.Kind == Array {
+			for _, el := range lit.Elements {
+				c.expect(c.inferred[el], target.Elem, el, "an element of "+what)
+			}
+			return
+		}
+	case *ast.HashLiteral:
+		if value.Kind == Hash && target.Kind == Hash {
+			for i := range lit.Keys {
+				c.expect(c.inferred[lit.Keys[i]], target.Key, lit.Keys[i], "a key of "+what)
+				c.expect(c.inferred[lit.Values[i]], target.Elem, lit.Values[i], "a value of "+what)
+			}
+			return
+		}
+	}
+	if !value.AssignableTo(target) {
+		c.errorAt(start(at), "cannot use %s as %s for %s", value, target, what)
+	}
+}
+
+// SCOPES
+// ---------------------------------------------------------------------------------
+// the same scopes as the evaluator : the program , each function , each catch , each match arm and the body of each for
+
+func (c *checker) open() {
+	c.scopes = append(c.scopes, map[string]*Type{})
+}
+
+func (c *checker) close() {
+	c.scopes = c.scopes[:len(c.scopes)-1]
+}
+
+func (c *checker) lookup(name string) *Type {
+	for i := len(c.scopes) - 1; i >= 0; i-- {
+		if t, ok := c.scopes[i][name]; ok {
+			return t
+		}
+	}
+	return AnyType
+}
+
+func (c *checker) bind(name string, t *Type) {
+	if name != "_" {
+		c.scopes[len(c.scopes)-1][name] = t
+	}
+}
+
+// bind the names of a let or parameter target , the parts of a pattern get the element type of t
+func (c *checker) bindTarget(target ast.Expression, t *Type) {
+	switch target := target.(type) {
+	case *ast.Identifier:
+		c.bind(target.Value, t)
+	case *ast.ArrayPattern:
+		elem := AnyType
+		switch t.Kind {
+		case Array:
+			elem = t.Elem
+		case Any:
+		default:
+			c.errorAt(target.Token, "cannot destructure %s into %s", t, target)
+		}
+		for _, el := range target.Elements {
+			c.bindTarget(el, elem)
+		}
+		if target.Rest != nil {
+			c.bind(target.Rest.Value, ArrayOf(elem))
+		}
+	case *ast.HashPattern:
+		value := AnyType
+		switch t.Kind {
+		case Hash:
+			value = t.Elem
+		case Any, Struct:
+		default:
+			c.errorAt(target.Token, "cannot destructure %s into %s", t, target)
+		}
+		for _, el := range target.Values {
+			c.bindTarget(el, value)
+		}
+	}
+}
+
+// the blocks of if and try share the scope around them but may not run , so a name they bind again keep a
+// type that fit both its old and its new value
+func (c *checker) branch(block *ast.BlockStatement) *Type {
+	if block == nil {
+		return AnyType
+	}
+	scope := c.scopes[len(c.scopes)-1]
+	before := map[string]*Type{}
+	for name, t := range scope {
+		before[name] = t
+	}
+	result := c.statements(block.Statements)
+	for name, t := range scope {
+		if old, ok := before[name]; ok {
+			scope[name] = Join(old, t)
+		}
+	}
+	return result
+}
+
+// STATEMENTS
+// ---------------------------------------------------------------------------------
+
+// check each statement and return the type of the last one , the value of a block
+func (c *checker) statements(stmts []ast.Statement) *Type {
+	result := AnyType
+	for _, stmt := range stmts {
+		result = c.statement(stmt)
+	}
+	return result
+}
+
+func (c *checker) statement(stmt ast.Statement) *Type {
+	switch s := stmt.(type) {
+	case *ast.LetStatement:
+		t := c.expr(s.Value)
+		if s.Type != nil {
+			annotation := FromAST(s.Type)
+			c.expect(t, annotation, s.Value, s.Target().String())
+			t = annotation
+		}
+		c.bindTarget(s.Target(), t)
+	case *ast.ReturnStatement:
+		t := c.expr(s.ReturnValue)
+		if len(c.results) != 0 && s.ReturnValue != nil {
+			c.expect(t, c.results[len(c.results)-1], s.ReturnValue, "the result")
+		}
+		return t
+	case *ast.ExpressionStatement:
+		return c.expr(s.Expression)
+	case *ast.ImportStatement:
+		c.bind(s.Name(), AnyType)
+	case *ast.ThrowStatement:
+		c.expr(s.Value)
+	case *ast.ForStatement:
+		c.forStatement(s)
+	case *ast.StructStatement:
+		// a struct is called like a function whose parameters are its fields
+		sig := &Signature{Result: StructOf(s.Name.Value)}
+		for _, field := range s.Fields {
+			sig.Params = append(sig.Params, Param{Name: field.Value, Type: AnyType})
+		}
+		c.bind(s.Name.Value, &Type{Kind: Func, Signature: sig})
+	case *ast.BlockStatement:
+		return c.statements(s.Statements)
+	}
+	return AnyType
+}
+
+// EXPRESSIONS
+// ---------------------------------------------------------------------------------
+
+func (c *checker) expr(exp ast.Expression) *Type {
+	t := c.infer(exp)
+	if exp != nil {
+		c.inferred[exp] = t
+	}
+	return t
+}
+
+func (c *checker) infer(exp ast.Expression) *Type {
+	switch e := exp.(type) {
+	case *ast.IntegerLiteral:
+		return IntType
+	case *ast.FloatLiteral:
+		return FloatType
+	case *ast.StringLiteral:
+		return StringType
+	case *ast.BooleanLiteral:
+		return BoolType
+	case *ast.Identifier:
+		return c.lookup(e.Value)
+	case *ast.PrefixExpression:
+		right := c.expr(e.Right)
+		if e.Operator == "!" {
+			return BoolType
+		}
+		switch right.Kind {
+		case Int, Float, Any:
+			return right
+		}
+		c.errorAt(e.Token, "operator %s is not defined for %s", e.Operator, right)
+	case *ast.InfixExpression:
+		left, right := c.expr(e.Left), c.expr(e.Right)
+		if t, ok := operatorResult(e.Operator, left, right); ok {
+			return t
+		}
+		c.errorAt(e.Token, "operator %s is not defined for %s and %s", e.Operator, left, right)
+	case *ast.IfExpression:
+		c.expr(e.Condition)
+		consequence := c.branch(e.Consequence)
+		if e.Alternative != nil {
+			return Join(consequence, c.branch(e.Alternative))
+		}
+	case *ast.TryExpression:
+		c.branch(e.Body)
+		if e.Catch != nil {
+			c.open()
+			c.bind(e.Param.Value, AnyType)
+			c.statements(e.Catch.Statements)
+			c.close()
+		}
+		c.branch(e.Finally)
+	case *ast.FunctionLiteral:
+		return c.function(e)
+	case *ast.CallExpression:
+		return c.call(e)
+	case *ast.ArrayLiteral:
+		elem := (*Type)(nil)
+		for _, el := range e.Elements {
+			elem = Join(elem, c.expr(el))
+		}
+		if elem == nil {
+			elem = AnyType
+		}
+		return ArrayOf(elem)
+	case *ast.HashLiteral:
+		if len(e.Keys) == 0 {
+			return HashOf(AnyType, AnyType)
+		}
+		key, value := (*Type)(nil), (*Type)(nil)
+		for i := range e.Keys {
+			k := c.expr(e.Keys[i])
+			if !hashable(k) {
+				c.errorAt(start(e.Keys[i]), "cannot use %s as a hash key", k)
+			}
+			key, value = Join(key, k), Join(value, c.expr(e.Values[i]))
+		}
+		return HashOf(key, value)
+	case *ast.IndexExpression:
+		return c.index(e)
+	case *ast.MemberExpression:
+		c.expr(e.Object)
+	case *ast.ImportExpression:
+		c.expr(e.Path)
+	case *ast.SpreadExpression:
+		c.expr(e.Value)
+	case *ast.NamedArgument:
+		c.expr(e.Value)
+	case *ast.YieldExpression:
+		// the value sent by next is only known when the program run
+		if e.Value != nil {
+			c.expr(e.Value)
+		}
+	case *ast.MatchExpression:
+		return c.match(e)
+	}
+	return AnyType
+}
+
+// type of left operator right as the evaluator compute it , ok is false when the evaluator would fail or when it
+// convert a value to a string the way a script rarely mean to : only a number appended to a string is let through ,
+// "a" + true and 1 + "a" are reported even though the evaluator accept them outside strict mode
+func operatorResult(operator string, left, right *Type) (t *Type, ok bool) {
+	compare := false
+	switch operator {
+	case "==", "!=", "<", ">", "<=", ">=":
+		compare = true
+	}
+	equality := operator == "==" || operator == "!="
+	numeric := func(t *Type) bool { return t.Kind == Int || t.Kind == Float }
+
+	switch {
+	case equality:
+		// values of different types are never equal , but any two values can be compared
+		return BoolType, true
+	case left.Kind == Any || right.Kind == Any:
+		if compare {
+			return BoolType, true
+		}
+		return AnyType, true
+	case numeric(left) && numeric(right):
+		switch {
+		case compare:
+			return BoolType, true
+		case left.Kind == Int && right.Kind == Int:
+			return IntType, true
+		}
+		return FloatType, true
+	case compare && left.Kind == right.Kind && (left.Kind == String || left.Kind == Array):
+		// strings and arrays are ordered like sort order them
+		return BoolType, true
+	case left.Kind == String || right.Kind == String:
+		switch operator {
+		case "+":
+			return StringType, left.Kind == String && (right.Kind == String || numeric(right))
+		case "*":
+			// a string is repeated an integer number of times
+			return StringType, left.Kind == Int || right.Kind == Int
+		}
+	}
+	return nil, false
+}
+
+func hashable(t *Type) bool {
+	switch t.Kind {
+	case Int, String, Bool, Any:
+		return true
+	}
+	return false
+}
+
+func (c *checker) index(e *ast.IndexExpression) *Type {
+	left, index := c.expr(e.Left), c.expr(e.Index)
+	switch {
+	case left.Kind == Any:
+		return AnyType
+	case left.Kind == Array && (index.Kind == Int || index.Kind == Any):
+		return left.Elem
+	case left.Kind == String && (index.Kind == Int || index.Kind == Any):
+		return StringType
+	case left.Kind == Hash && hashable(index):
+		return left.Elem
+	}
+	c.errorAt(e.Token, "cannot index %s with %s", left, index)
+	return AnyType
+}
+
+// FUNCTIONS
+// ---------------------------------------------------------------------------------
+
+// the body is checked once , with the parameters of the types they are annotated with
+func (c *checker) function(fn *ast.FunctionLiteral) *Type {
+	sig := SignatureOf(fn)
+	t := &Type{Kind: Func, Signature: sig}
+	if fn.Generator {
+		// a call return a generator , the annotated result is the one of the body
+		t = &Type{Kind: Func, Signature: &Signature{Params: sig.Params, Result: AnyType}}
+	}
+	if fn.Body == nil {
+		return t
+	}
+	c.open()
+	defer c.close()
+	for i, param := range fn.Parameters {
+		switch param := param.(type) {
+		case *ast.DefaultParameter:
+			// a default is evaluated in the function scope , after the parameters before it
+			c.expect(c.expr(param.Value), sig.Params[i].Type, param.Value, "parameter "+sig.Params[i].Name)
+			c.bindTarget(param.Target, sig.Params[i].Type)
+		case *ast.SpreadExpression:
+			c.bindTarget(param.Value, sig.Params[i].Type)
+		default:
+			c.bindTarget(param, sig.Params[i].Type)
+		}
+	}
+
+	c.results = append(c.results, sig.Result)
+	result := c.statements(fn.Body.Statements)
+	c.results = c.results[:len(c.results)-1]
+	// without return , the value of the last statement is the result
+	if n := len(fn.Body.Statements); n != 0 {
+		if last, ok := fn.Body.Statements[n-1].(*ast.ExpressionStatement); ok {
+			c.expect(result, sig.Result, last.Expression, "the result")
+		}
+	}
+	return t
+}
+
+// the element a for bind is the element of an array , a character of a string , a key of a hash ,
+// anything for a generator
+func (c *checker) forStatement(s *ast.ForStatement) {
+	iterable := c.expr(s.Iterable)
+	elem := AnyType
+	switch iterable.Kind {
+	case Array:
+		elem = iterable.Elem
+	case String:
+		elem = StringType
+	case Hash:
+		elem = iterable.Key
+	case Any:
+	default:
+		c.errorAt(start(s.Iterable), "cannot iterate over %s", iterable)
+	}
+	c.open()
+	c.bindTarget(s.Target, elem)
+	c.statements(s.Body.Statements)
+	c.close()
+}
+
+func (c *checker) call(e *ast.CallExpression) *Type {
+	callee := c.expr(e.Function)
+	args := make([]*Type, len(e.Arguments))
+	spread := false
+	for i, arg := range e.Arguments {
+		args[i] = c.expr(arg)
+		if _, ok := arg.(*ast.SpreadExpression); ok {
+			spread = true
+		}
+	}
+	switch {
+	case callee.Kind == Any:
+		return AnyType
+	case callee.Kind != Func:
+		c.errorAt(start(e.Function), "cannot call %s", callee)
+		return AnyType
+	case callee.Signature == nil:
+		return AnyType
+	}
+	// the number of arguments given by a spread is only known when the program run
+	if !spread {
+		c.arguments(e, callee.Signature, args)
+	}
+	return callee.Signature.Result
+}
+
+// check the arguments of a call against the parameters , the same way extendFunctionEnv bind them
+func (c *checker) arguments(e *ast.CallExpression, sig *Signature, args []*Type) {
+	name := e.Function.String()
+	params, rest := sig.Params, (*Param)(nil)
+	if n := len(params); n != 0 && params[n-1].Rest {
+		params, rest = params[:n-1], &params[n-1]
+	}
+	required := len(params)
+	for i, param := range params {
+		if param.Default {
+			required = i
+			break
+		}
+	}
+
+	given := map[string]bool{}
+	positional, named := 0, 0
+	for i, arg := range e.Arguments {
+		na, ok := arg.(*ast.NamedArgument)
+		if !ok {
+			positional++
+			switch {
+			case positional <= len(params):
+				param := params[positional-1]
+				c.expect(args[i], param.Type, arg, fmt.Sprintf("parameter %s of %s", param.Name, name))
+			case rest != nil && rest.Type.Kind == Array:
+				c.expect(args[i], rest.Type.Elem, arg, fmt.Sprintf("parameter %s of %s", rest.Name, name))
+			}
+			continue
+		}
+		named++
+		switch j := paramIndex(params, na.Name.Value); {
+		case j < 0:
+			c.errorAt(na.Token, "%s has no parameter %s", name, na.Name.Value)
+			return
+		case j < positional:
+			c.errorAt(na.Token, "%s got argument %s twice", name, na.Name.Value)
+			return
+		default:
+			given[na.Name.Value] = true
+			c.expect(args[i], params[j].Type, na.Value, fmt.Sprintf("parameter %s of %s", params[j].Name, name))
+		}
+	}
+
+	switch {
+	case rest == nil && positional > len(params):
+		c.errorAt(e.Token, "%s expect %s, got=%d", name, arity(required, len(params)), positional)
+	case named == 0 && positional < required && rest != nil:
+		c.errorAt(e.Token, "%s expect at least %s, got=%d", name, arity(required, required), positional)
+	case named == 0 && positional < required:
+		c.errorAt(e.Token, "%s expect %s, got=%d", name, arity(required, len(params)), positional)
+	default:
+		for i := positional; i < required; i++ {
+			if !given[params[i].Name] {
+				c.errorAt(e.Token, "%s missing argument %s", name, params[i].Name)
+			}
+		}
+	}
+}
+
+// position of the parameter called name , -1 when there is none
+func paramIndex(params []Param, name string) int {
+	for i, param := range params {
+		if param.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// 2 arguments , 1 to 3 arguments
+func arity(min, max int) string {
+	plural := func(n int) string {
+		if n == 1 {
+			return "1 argument"
+		}
+		return fmt.Sprintf("%d arguments", n)
+	}
+	if min == max {
+		return plural(max)
+	}
+	return fmt.Sprintf("%d to %s", min, plural(max))
+}
+
+// MATCH
+// ---------------------------------------------------------------------------------
+
+// every name of a pattern is any , but a whole subject bound to a name keep its type
+func (c *checker) match(e *ast.MatchExpression) *Type {
+	subject := c.expr(e.Subject)
+	result := (*Type)(nil)
+	for _, arm := range e.Arms {
+		c.open()
+		if ident, ok := arm.Pattern.(*ast.Identifier); ok {
+			c.bind(ident.Value, subject)
+		} else {
+			ast.Inspect(arm.Pattern, func(n ast.Node) bool {
+				if ident, ok := n.(*ast.Identifier); ok {
+					c.bind(ident.Value, AnyType)
+				}
+				return true
+			})
+		}
+		if arm.Guard != nil {
+			c.expr(arm.Guard)
+		}
+		result = Join(result, c.statement(arm.Body))
+		c.close()
+	}
+	if result == nil {
+		return AnyType
+	}
+	return result
+}
+
+// first token of an expression , where the problems of a whole expression are reported
+func start(exp ast.Expression) token.Token {
+	switch e := exp.(type) {
+	case *ast.InfixExpression:
+		return start(e.Left)
+	case *ast.CallExpression:
+		return start(e.Function)
+	case *ast.IndexExpression:
+		return start(e.Left)
+	case *ast.MemberExpression:
+		return start(e.Object)
+	case *ast.DefaultParameter:
+		return start(e.Target)
+	case *ast.Identifier:
+		return e.Token
+	case *ast.IntegerLiteral:
+		return e.Token
+	case *ast.FloatLiteral:
+		return e.Token
+	case *ast.StringLiteral:
+		return e.Token
+	case *ast.BooleanLiteral:
+		return e.Token
+	case *ast.PrefixExpression:
+		return e.Token
+	case *ast.IfExpression:
+		return e.Token
+	case *ast.TryExpression:
+		return e.Token
+	case *ast.FunctionLiteral:
+		return e.Token
+	case *ast.ArrayLiteral:
+		return e.Token
+	case *ast.HashLiteral:
+		return e.Token
+	case *ast.ImportExpression:
+		return e.Token
+	case *ast.SpreadExpression:
+		return e.Token
+	case *ast.YieldExpression:
+		return e.Token
+	case *ast.NamedArgument:
+		return e.Token
+	case *ast.MatchExpression:
+		return e.Token
+	case *ast.ArrayPattern:
+		return e.Token
+	case *ast.HashPattern:
+		return e.Token
+	}
+	return token.Token{}
+}
@@ -0,0 +1,109 @@
+package types
+
+import (
+	"khanhanh_lang/lexer"
+	"khanhanh_lang/parser"
+	"strings"
+	"testing"
+)
+
+func check(t *testing.T, input string) []string {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("%s: parser errors %v", input, p.Errors())
+	}
+	return Check(program)
+}
+
+func TestCheckReportMismatches(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		// operators
+		{`"a" + true`, "1:5: operator + is not defined for string and bool"},
+		{`1 + "a"`, "1:3: operator + is not defined for int and string"},
+		{`"a" + [1]`, "1:5: operator + is not defined for string and array<int>"},
+		{`1 - "a"`, "1:3: operator - is not defined for int and string"},
+		{`true < false`, "1:6: operator < is not defined for bool and bool"},
+		{`"a" * "b"`, "1:5: operator * is not defined for string and string"},
+		{`[1] + [1]`, "1:5: operator + is not defined for array<int> and array<int>"},
+		{`"a" < 1`, "1:5: operator < is not defined for string and int"},
+		{`[1] < "a"`, "1:5: operator < is not defined for array<int> and string"},
+		{`-"a"`, "1:1: operator - is not defined for string"},
+		{`let x = 1; let y = x + true`, "1:22: operator + is not defined for int and bool"},
+		// annotations
+		{`let x: int = "a"`, "1:14: cannot use string as int for x"},
+		{`let x: int = 1.5`, "1:14: cannot use float as int for x"},
+		{`let xs: array<int> = [1, "a"]`, "1:26: cannot use string as int for an element of xs"},
+		{`let h: hash<string, int> = {"a": true}`, "1:34: cannot use bool as int for a value of h"},
+		{`let [a, b]: array<int> = [1, 2]; a + "s"`, "1:36: operator + is not defined for int and string"},
+		{`struct P { x }; let p: P = 5`, "1:28: cannot use int as P for p"},
+		// functions
+		{`let f = func(a: string) { a }; f(1)`, "1:34: cannot use int as string for parameter a of f"},
+		{`let f = func(a: int): string { a }`, "1:32: cannot use int as string for the result"},
+		{`let f = func(a: int): string { return a; }`, "1:39: cannot use int as string for the result"},
+		{`let f = func(a: int = "x") { a }`, "1:23: cannot use string as int for parameter a"},
+		{`let f = func(a: int) { a + "s" }`, "1:26: operator + is not defined for int and string"},
+		{`let f = func(a, b) { a }; f(1)`, "1:28: f expect 2 arguments, got=1"},
+		{`let f = func(a, b = 1) { a }; f(1, 2, 3)`, "1:32: f expect 1 to 2 arguments, got=3"},
+		{`let f = func(a, ...r) { a }; f()`, "1:31: f expect at least 1 argument, got=0"},
+		{`let f = func(a, b) { a }; f(b: 1)`, "1:28: f missing argument a"},
+		{`let f = func(a) { a }; f(c: 1)`, "1:26: f has no parameter c"},
+		{`let f = func(a) { a }; f(1, a: 2)`, "1:29: f got argument a twice"},
+		{`let f = func(...r: array<int>) { r }; f(1, "a")`, "1:44: cannot use string as int for parameter r of f"},
+		{`let f = func(): bool { true }; let x: int = f()`, "1:45: cannot use bool as int for x"},
+		{`let n = 5; n(1)`, "1:12: cannot call int"},
+		{`struct P { x, y }; P(1)`, "1:21: P expect 2 arguments, got=1"},
+		// indexes and patterns
+		{`[1]["a"]`, "1:4: cannot index array<int> with string"},
+		{`{[1]: 2}`, "1:2: cannot use array<int> as a hash key"},
+		{`let [a] = 5`, "1:5: cannot destructure int into [a]"},
+		{`let {a} = "s"`, "1:5: cannot destructure string into {a}"},
+		// for and generators
+		{`for x in 5 { }`, "1:10: cannot iterate over int"},
+		{`for x in [1, 2] { x + true }`, "1:21: operator + is not defined for int and bool"},
+		{`for c in "ab" { c - 1 }`, "1:19: operator - is not defined for string and int"},
+		{`for [k, v] in {"a": 1} { }`, "1:5: cannot destructure string into [k, v]"},
+		{`let x = 1; for x in ["a"] { }; x + true`, "1:34: operator + is not defined for int and bool"},
+		{`let g = func*(): int { yield 1; "a" }`, "1:33: cannot use string as int for the result"},
+		{`let g = func*(a: int) { yield a }; g("a")`, "1:38: cannot use string as int for parameter a of g"},
+	}
+	for _, tt := range tests {
+		errs := strings.Join(check(t, tt.input), "\n")
+		if errs != tt.expect {
+			t.Errorf("%s\nexpect=%q\ngot=   %q", tt.input, tt.expect, errs)
+		}
+	}
+}
+
+// dynamic code and what the checker cannot know about must not be reported
+func TestCheckAcceptValidCode(t *testing.T) {
+	inputs := []string{
+		`let x = 1; let y = x + 2.5; let s = "a" + 1; let r = "ab" * 3; "5" == 5`,
+		`let f = func(a, b) { a + b }; f(1, "a"); f(true, false)`,
+		`let x = json_parse("1"); x + true`,
+		`let xs: array<float> = [1, 2.5]; let h: hash<string, any> = {"a": 1, "b": "c"}`,
+		`let f = func(a: int, b: int = 2, ...rest: array<int>): int { a + b }; f(1); f(1, b: 3); f(1, 2, 3, 4); f(...[1, 2])`,
+		`let fact = func(n: int): int { if (n < 2) { return 1 } else { return n * fact(n - 1) } }`,
+		`struct P { x, y }; let p: P = P(1, 2); let q: P = P(y: 1, x: 2); p == q`,
+		`let x = 1; if (true) { let x = "s" }; x + "a"`,
+		`let x = 1; let f = func(x) { x + true }`,
+		`match 5 { n if n > 1 => n + 1, [a, b] => a + b, _ => 0 }`,
+		`let e = try { throw "x" } catch (e) { e.message() + 1 }`,
+		`let {a, b: [c]} = {"a": 1, "b": [2]}; c + 1`,
+		`let f = func([a, b]: array<int>) { a + b }; f([1, 2])`,
+		`import "lib/strings"; strings + 1`,
+		`let p = print; p("a")`,
+		`let a = "a" < "b"; let b = [1, "x"] >= [1]; [1] == [1]`,
+		`let g = func*(): int { yield "a"; 1 }; let it: int = g(); it.next()`,
+		`let g = func() { let x = yield 1; x + true }; for x in g() { x - 1 }`,
+		`let h = {"a": [1]}; for k in h { k + "b" }; for [i, x] in enumerate([1]) { i + x }`,
+	}
+	for _, input := range inputs {
+		if errs := check(t, input); len(errs) != 0 {
+			t.Errorf("%s: unexpected problems %v", input, errs)
+		}
+	}
+}
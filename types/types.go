@@ -0,0 +1,317 @@
+// Package types give a meaning to the optional annotations of a program ( let x: int = 5 ,
+// func(a: string): bool ) : the evaluator check the values against them while the program run , and Check
+// infer the types of a whole program to report the mismatches before it run.
+// Typing is gradual , a value without annotation is any , which is compatible with every type.
+package types
+
+import (
+	"khanhanh_lang/ast"
+	"khanhanh_lang/object"
+	"strings"
+)
+
+type Kind int
+
+const (
+	Any Kind = iota
+	Int
+	Float
+	String
+	Bool
+	Nil
+	Array
+	Hash
+	Func
+	Struct // an instance of the struct Name
+)
+
+var kindStrings = [...]string{Any: "any", Int: "int", Float: "float", String: "string", Bool: "bool", Nil: "nil"}
+
+// the names usable in an annotation , any other name is a struct
+var kindNames = map[string]Kind{
+	"any":    Any,
+	"int":    Int,
+	"float":  Float,
+	"string": String,
+	"bool":   Bool,
+	"nil":    Nil,
+	"array":  Array,
+	"hash":   Hash,
+	"func":   Func,
+}
+
+// Type of a value , Elem is the element of an array and the value of a hash , Key the key of a hash
+type Type struct {
+	Kind      Kind
+	Name      string     // name of the struct , for Struct
+	Key, Elem *Type      // array and hash
+	Signature *Signature // parameters and result of a func , nil when they are not known
+}
+
+// Signature of a function , Result is any when the function has no annotation for it
+type Signature struct {
+	Params []Param
+	Result *Type
+}
+
+type Param struct {
+	Name    string // the name of the parameter , or its destructuring pattern as written
+	Type    *Type
+	Default bool // the parameter has a default , a call may leave it out
+	Rest    bool // the last ...rest , its Type is the type of the whole array
+}
+
+var (
+	AnyType    = &Type{Kind: Any}
+	IntType    = &Type{Kind: Int}
+	FloatType  = &Type{Kind: Float}
+	StringType = &Type{Kind: String}
+	BoolType   = &Type{Kind: Bool}
+	NilType    = &Type{Kind: Nil}
+	FuncType   = &Type{Kind: Func}
+)
+
+func ArrayOf(elem *Type) *Type      { return &Type{Kind: Array, Elem: elem} }
+func HashOf(key, value *Type) *Type { return &Type{Kind: Hash, Key: key, Elem: value} }
+func StructOf(name string) *Type    { return &Type{Kind: Struct, Name: name} }
+
+// String show the type as it is written in an annotation , array<int> , hash<string, any> or func(int): bool
+func (t *Type) String() string {
+	switch t.Kind {
+	case Array:
+		return "array<" + t.Elem.String() + ">"
+	case Hash:
+		return "hash<" + t.Key.String() + ", " + t.Elem.String() + ">"
+	case Func:
+		if t.Signature == nil {
+			return "func"
+		}
+		params := []string{}
+		for _, param := range t.Signature.Params {
+			text := param.Type.String()
+			if param.Rest {
+				text = "..." + text
+			}
+			params = append(params, text)
+		}
+		return "func(" + strings.Join(params, ", ") + "): " + t.Signature.Result.String()
+	case Struct:
+		return t.Name
+	}
+	return kindStrings[t.Kind]
+}
+
+// FromAST return the type an annotation stand for , any for a nil annotation
+func FromAST(annotation *ast.Type) *Type {
+	if annotation == nil {
+		return AnyType
+	}
+	kind, ok := kindNames[annotation.Token.Literal]
+	if !ok {
+		return StructOf(annotation.Token.Literal)
+	}
+	arg := func(i int) *Type {
+		if i < len(annotation.Arguments) {
+			return FromAST(annotation.Arguments[i])
+		}
+		return AnyType
+	}
+	switch kind {
+	case Array:
+		return ArrayOf(arg(0))
+	case Hash:
+		return HashOf(arg(0), arg(1))
+	}
+	return &Type{Kind: kind}
+}
+
+// SignatureOf return the signature of a function literal from its annotations
+func SignatureOf(fn *ast.FunctionLiteral) *Signature {
+	sig := &Signature{Result: FromAST(fn.ReturnType)}
+	for i, param := range fn.Parameters {
+		p := Param{Name: param.String(), Type: FromAST(fn.ParameterType(i))}
+		switch param := param.(type) {
+		case *ast.DefaultParameter:
+			p.Name, p.Default = param.Target.String(), true
+		case *ast.SpreadExpression:
+			p.Name, p.Rest = param.Value.String(), true
+			if fn.ParameterType(i) == nil {
+				p.Type = ArrayOf(AnyType)
+			}
+		}
+		sig.Params = append(sig.Params, p)
+	}
+	return sig
+}
+
+// VALUES
+// ---------------------------------------------------------------------------------
+
+// Of return the type of a value , the element type of an array is the one shared by all its elements
+func Of(obj object.Object) *Type {
+	return of(obj, map[object.Object]bool{})
+}
+
+// visiting hold the arrays and hashes being typed , a collection that contains itself is any inside itself
+func of(obj object.Object, visiting map[object.Object]bool) *Type {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return IntType
+	case *object.Float:
+		return FloatType
+	case *object.String:
+		return StringType
+	case *object.Boolean:
+		return BoolType
+	case *object.Nil:
+		return NilType
+	case *object.Array:
+		if visiting[obj] {
+			return AnyType
+		}
+		visiting[obj] = true
+		defer delete(visiting, obj)
+		elem := (*Type)(nil)
+		for _, el := range obj.Elements {
+			elem = Join(elem, of(el, visiting))
+		}
+		if elem == nil {
+			elem = AnyType
+		}
+		return ArrayOf(elem)
+	case *object.Hash:
+		if visiting[obj] {
+			return AnyType
+		}
+		visiting[obj] = true
+		defer delete(visiting, obj)
+		key, value := (*Type)(nil), (*Type)(nil)
+		for _, pair := range obj.Pairs() {
+			key, value = Join(key, of(pair.Key, visiting)), Join(value, of(pair.Value, visiting))
+		}
+		if key == nil {
+			key, value = AnyType, AnyType
+		}
+		return HashOf(key, value)
+	case *object.Function, *object.Builtin, *object.Struct:
+		return FuncType
+	case *object.Instance:
+		return StructOf(obj.Struct.Name)
+	}
+	return AnyType
+}
+
+// Matches report whether the value obj can be held by a slot of type t , an int can be held by a float
+// the elements of an array and the pairs of a hash are checked too , as deep as t go
+func (t *Type) Matches(obj object.Object) bool {
+	switch t.Kind {
+	case Any:
+		return true
+	case Int:
+		_, ok := obj.(*object.Integer)
+		return ok
+	case Float:
+		switch obj.(type) {
+		case *object.Integer, *object.Float:
+			return true
+		}
+		return false
+	case String:
+		_, ok := obj.(*object.String)
+		return ok
+	case Bool:
+		_, ok := obj.(*object.Boolean)
+		return ok
+	case Nil:
+		_, ok := obj.(*object.Nil)
+		return ok
+	case Array:
+		array, ok := obj.(*object.Array)
+		if !ok {
+			return false
+		}
+		for _, el := range array.Elements {
+			if !t.Elem.Matches(el) {
+				return false
+			}
+		}
+		return true
+	case Hash:
+		hash, ok := obj.(*object.Hash)
+		if !ok {
+			return false
+		}
+		for _, pair := range hash.Pairs() {
+			if !t.Key.Matches(pair.Key) || !t.Elem.Matches(pair.Value) {
+				return false
+			}
+		}
+		return true
+	case Func:
+		switch obj.(type) {
+		case *object.Function, *object.Builtin, *object.Struct:
+			return true
+		}
+		return false
+	case Struct:
+		instance, ok := obj.(*object.Instance)
+		return ok && instance.Struct.Name == t.Name
+	}
+	return false
+}
+
+// TYPES
+// ---------------------------------------------------------------------------------
+
+// AssignableTo report whether a value of type t can go where a value of type target is expected ,
+// any is assignable both ways
+func (t *Type) AssignableTo(target *Type) bool {
+	if t.Kind == Any || target.Kind == Any {
+		return true
+	}
+	switch {
+	case t.Kind == Int && target.Kind == Float:
+		return true
+	case t.Kind != target.Kind:
+		return false
+	}
+	switch t.Kind {
+	case Array:
+		return t.Elem.AssignableTo(target.Elem)
+	case Hash:
+		return t.Key.AssignableTo(target.Key) && t.Elem.AssignableTo(target.Elem)
+	case Struct:
+		return t.Name == target.Name
+	}
+	return true
+}
+
+// Join return the type of a value that is either a t or an other , any when they have nothing in common
+// a nil t is ignored , so Join can fold a list starting from nil
+func Join(t, other *Type) *Type {
+	switch {
+	case t == nil:
+		return other
+	case t.Kind == Any || other.Kind == Any:
+		return AnyType
+	case t.Kind == Int && other.Kind == Float, t.Kind == Float && other.Kind == Int:
+		return FloatType
+	case t.Kind != other.Kind:
+		return AnyType
+	}
+	switch t.Kind {
+	case Array:
+		return ArrayOf(Join(t.Elem, other.Elem))
+	case Hash:
+		return HashOf(Join(t.Key, other.Key), Join(t.Elem, other.Elem))
+	case Struct:
+		if t.Name != other.Name {
+			return AnyType
+		}
+	case Func:
+		if t.Signature != other.Signature {
+			return FuncType
+		}
+	}
+	return t
+}
@@ -0,0 +1,146 @@
+package types
+
+import (
+	"khanhanh_lang/ast"
+	"khanhanh_lang/lexer"
+	"khanhanh_lang/object"
+	"khanhanh_lang/parser"
+	"testing"
+)
+
+// the annotation of let x: <annotation> = x
+func annotation(t *testing.T, text string) *ast.Type {
+	p := parser.New(lexer.New("let x: " + text + " = x"))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("%s: parser errors %v", text, p.Errors())
+	}
+	return program.Statements[0].(*ast.LetStatement).Type
+}
+
+func TestFromAST(t *testing.T) {
+	tests := []struct {
+		annotation string
+		expect     string
+	}{
+		{"int", "int"},
+		{"any", "any"},
+		{"array", "array<any>"},
+		{"array<array<float>>", "array<array<float>>"},
+		{"hash", "hash<any, any>"},
+		{"hash<string, bool>", "hash<string, bool>"},
+		{"func", "func"},
+		{"nil", "nil"},
+		{"Point", "Point"},
+	}
+	for _, tt := range tests {
+		got := FromAST(annotation(t, tt.annotation))
+		if got.String() != tt.expect {
+			t.Errorf("%s: expect=%s got=%s", tt.annotation, tt.expect, got)
+		}
+	}
+	if FromAST(nil) != AnyType {
+		t.Errorf("a missing annotation should be any")
+	}
+}
+
+func TestMatches(t *testing.T) {
+	point := &object.Struct{Name: "Point", Fields: []string{"x"}}
+	hash := object.NewHash()
+	hash.Set(&object.String{Value: "a"}, &object.Integer{Value: 1})
+	ints := &object.Array{Elements: []object.Object{&object.Integer{Value: 1}, &object.Integer{Value: 2}}}
+	mixed := &object.Array{Elements: []object.Object{&object.Integer{Value: 1}, &object.String{Value: "a"}}}
+
+	tests := []struct {
+		annotation string
+		value      object.Object
+		expect     bool
+	}{
+		{"int", &object.Integer{Value: 1}, true},
+		{"int", &object.Float{Value: 1}, false},
+		{"float", &object.Integer{Value: 1}, true},
+		{"string", &object.Integer{Value: 1}, false},
+		{"bool", &object.Boolean{Value: true}, true},
+		{"nil", &object.Nil{}, true},
+		{"int", &object.Nil{}, false},
+		{"any", mixed, true},
+		{"array", mixed, true},
+		{"array<int>", ints, true},
+		{"array<float>", ints, true},
+		{"array<int>", mixed, false},
+		{"array<int>", &object.Array{}, true},
+		{"hash<string, int>", hash, true},
+		{"hash<string, string>", hash, false},
+		{"hash<int, int>", hash, false},
+		{"func", &object.Builtin{Name: "print"}, true},
+		{"func", point, true},
+		{"Point", &object.Instance{Struct: point, Values: []object.Object{&object.Integer{Value: 1}}}, true},
+		{"Point", hash, false},
+		{"Line", &object.Instance{Struct: point, Values: []object.Object{&object.Integer{Value: 1}}}, false},
+	}
+	for _, tt := range tests {
+		got := FromAST(annotation(t, tt.annotation)).Matches(tt.value)
+		if got != tt.expect {
+			t.Errorf("%s matches %s: expect=%v got=%v", tt.annotation, tt.value.Inspect(), tt.expect, got)
+		}
+	}
+}
+
+func TestOf(t *testing.T) {
+	hash := object.NewHash()
+	hash.Set(&object.String{Value: "a"}, &object.Float{Value: 1.5})
+	hash.Set(&object.String{Value: "b"}, &object.Integer{Value: 1})
+	tests := []struct {
+		value  object.Object
+		expect string
+	}{
+		{&object.Integer{Value: 1}, "int"},
+		{&object.Array{}, "array<any>"},
+		{&object.Array{Elements: []object.Object{&object.Integer{Value: 1}, &object.Float{Value: 2}}}, "array<float>"},
+		{&object.Array{Elements: []object.Object{&object.Integer{Value: 1}, &object.String{Value: "a"}}}, "array<any>"},
+		{hash, "hash<string, float>"},
+		{&object.Function{}, "func"},
+		{&object.Module{Name: "strings"}, "any"},
+	}
+	for _, tt := range tests {
+		if got := Of(tt.value); got.String() != tt.expect {
+			t.Errorf("type of %s: expect=%s got=%s", tt.value.Inspect(), tt.expect, got)
+		}
+	}
+
+	// an array that contains itself
+	self := &object.Array{Elements: []object.Object{&object.Integer{Value: 1}}}
+	self.Elements = append(self.Elements, self)
+	if got := Of(self); got.String() != "array<any>" {
+		t.Errorf("type of a cyclic array: expect=array<any> got=%s", got)
+	}
+}
+
+func TestAssignableAndJoin(t *testing.T) {
+	tests := []struct {
+		from, to   string
+		assignable bool
+		join       string
+	}{
+		{"int", "int", true, "int"},
+		{"int", "float", true, "float"},
+		{"float", "int", false, "float"},
+		{"string", "int", false, "any"},
+		{"any", "int", true, "any"},
+		{"array<int>", "array<float>", true, "array<float>"},
+		{"array<string>", "array<int>", false, "array<any>"},
+		{"array<int>", "array", true, "array<any>"},
+		{"hash<string, int>", "hash<string, int>", true, "hash<string, int>"},
+		{"Point", "Point", true, "Point"},
+		{"Point", "Line", false, "any"},
+	}
+	for _, tt := range tests {
+		from, to := FromAST(annotation(t, tt.from)), FromAST(annotation(t, tt.to))
+		if got := from.AssignableTo(to); got != tt.assignable {
+			t.Errorf("%s assignable to %s: expect=%v got=%v", tt.from, tt.to, tt.assignable, got)
+		}
+		if got := Join(from, to); got.String() != tt.join {
+			t.Errorf("join of %s and %s: expect=%s got=%s", tt.from, tt.to, tt.join, got)
+		}
+	}
+}